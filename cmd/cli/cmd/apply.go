@@ -1,12 +1,25 @@
 package cmd
 
 import (
+	"os"
+
 	"github.com/muhammedikinci/pin/internal/runner"
 	"github.com/spf13/cobra"
 )
 
 var pipelineName string
-var pipelineFilePath string
+var pipelineFilePaths []string
+var dryRun bool
+var detach bool
+var watch bool
+var setValues []string
+var envValues []string
+var applyOutput string
+var debugOnFailure bool
+var fromJobName string
+var resumeRunID string
+var applyJobs []string
+var maxParallel int
 
 // applyCmd represents the apply command
 var applyCmd = &cobra.Command{
@@ -19,15 +32,50 @@ Cobra is a CLI library for Go that empowers applications.
 This application is a tool to generate the needed files
 to quickly create a Cobra application.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		runner.Apply(pipelineFilePath)
+		runner.DebugOnFailure = debugOnFailure
+
+		err := runner.Apply(pipelineName, pipelineFilePaths, runner.ApplyOptions{DryRun: dryRun, Detach: detach, Watch: watch, Set: setValues, Env: envValues, Output: applyOutput, FromJob: fromJobName, Resume: resumeRunID, Jobs: applyJobs, MaxParallel: maxParallel})
+
+		if err != nil {
+			os.Exit(runner.ExitCode(err))
+		}
 	},
 }
 
+// completeApplyJobs completes a flag with the job names of the -f pipeline.
+func completeApplyJobs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	filepath := ""
+
+	if len(pipelineFilePaths) > 0 {
+		filepath = pipelineFilePaths[0]
+	}
+
+	return runner.CompleteJobNames(filepath), cobra.ShellCompDirectiveNoFileComp
+}
+
 func init() {
 	applyCmd.PersistentFlags().StringVarP(&pipelineName, "name", "n", "", "pipeline name")
-	applyCmd.PersistentFlags().StringVarP(&pipelineFilePath, "filepath", "f", "", "pipeline configuration file path")
+	applyCmd.PersistentFlags().StringArrayVarP(&pipelineFilePaths, "filepath", "f", []string{}, "pipeline configuration file path, - reads it from stdin, repeat to merge files over each other")
+
+	applyCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "print the plan and pre-flight checks without running")
+	applyCmd.PersistentFlags().BoolVar(&detach, "detach", false, "run the pipeline in the background, follow it with pin attach")
+	applyCmd.PersistentFlags().BoolVar(&watch, "watch", false, "run the pipeline again whenever a project file changes")
+	applyCmd.PersistentFlags().IntVar(&maxParallel, "max-parallel", 0, "run at most this many jobs at the same time, overrides maxParallel of the pipeline")
+	applyCmd.PersistentFlags().StringSliceVar(&applyJobs, "jobs", []string{}, "run only the named jobs, e.g. build,test")
+	applyCmd.PersistentFlags().StringVar(&fromJobName, "from-job", "", "skip the jobs of the workflow before the named one")
+	applyCmd.PersistentFlags().StringVar(&resumeRunID, "resume", "", "run the pipeline from the first job that did not succeed in the given run")
+	applyCmd.PersistentFlags().BoolVar(&debugOnFailure, "debug-on-failure", false, "attach a shell to the container of a failed command before it is removed")
+
+	applyCmd.PersistentFlags().StringArrayVar(&setValues, "set", []string{}, "override a value of the pipeline, e.g. build.image=golang:1.18")
+	applyCmd.PersistentFlags().StringArrayVar(&envValues, "env", []string{}, "add a KEY=VALUE variable to every job")
+
+	applyCmd.PersistentFlags().StringVarP(&applyOutput, "output", "o", "text", "output format (text, json or yaml), json and yaml write a run report to stdout and the logs to stderr")
 
 	applyCmd.MarkPersistentFlagRequired("filepath")
+	applyCmd.MarkPersistentFlagFilename("filepath", "yaml", "yml")
+	applyCmd.RegisterFlagCompletionFunc("resume", completeRunIDs)
+	applyCmd.RegisterFlagCompletionFunc("from-job", completeApplyJobs)
+	applyCmd.RegisterFlagCompletionFunc("jobs", completeApplyJobs)
 
 	rootCmd.AddCommand(applyCmd)
 }
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"github.com/muhammedikinci/pin/internal/runner"
+	"github.com/spf13/cobra"
+)
+
+var artifactsJob string
+var artifactsOutput string
+
+// artifactsCmd represents the artifacts command
+var artifactsCmd = &cobra.Command{
+	Use:   "artifacts",
+	Short: "Work with the artifacts stored by past runs",
+}
+
+// artifactsGetCmd represents the artifacts get command
+var artifactsGetCmd = &cobra.Command{
+	Use:   "get <run-id>",
+	Short: "Copy the artifacts of a past run",
+	Long: `Copy the artifacts a run collected from its run history, every job
+gets a directory of its own under the output directory.
+
+Use --job to copy only the artifacts of one job.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeRunID,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runner.ArtifactsGet(args[0], artifactsJob, artifactsOutput)
+	},
+	SilenceUsage:  true,
+	SilenceErrors: true,
+}
+
+func init() {
+	artifactsGetCmd.Flags().StringVar(&artifactsJob, "job", "", "copy only the artifacts of this job")
+	artifactsGetCmd.Flags().StringVarP(&artifactsOutput, "output", "o", "", "directory to copy the artifacts to, artifacts/<run-id> by default")
+	artifactsGetCmd.RegisterFlagCompletionFunc("job", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		return runner.CompleteRunJobs(args[0]), cobra.ShellCompDirectiveNoFileComp
+	})
+	artifactsGetCmd.MarkFlagDirname("output")
+
+	artifactsCmd.AddCommand(artifactsGetCmd)
+	rootCmd.AddCommand(artifactsCmd)
+}
@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"github.com/muhammedikinci/pin/internal/runner"
+	"github.com/spf13/cobra"
+)
+
+// attachCmd represents the attach command
+var attachCmd = &cobra.Command{
+	Use:   "attach <run-id>",
+	Short: "Follow the output of a detached run",
+	Long: `Print the output of a run started with pin apply --detach and follow
+it until the run finishes.
+
+Interrupting attach does not stop the run, it can be attached again.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeRunID,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runner.Attach(args[0])
+	},
+	SilenceUsage:  true,
+	SilenceErrors: true,
+}
+
+func init() {
+	rootCmd.AddCommand(attachCmd)
+}
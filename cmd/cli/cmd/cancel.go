@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"github.com/muhammedikinci/pin/internal/runner"
+	"github.com/spf13/cobra"
+)
+
+// cancelCmd represents the cancel command
+var cancelCmd = &cobra.Command{
+	Use:   "cancel <run-id>",
+	Short: "Cancel a detached run",
+	Long: `Cancel a run started with pin apply --detach.
+
+The run stops its commands, removes its job and service containers and is
+recorded with the cancelled status, onFailure notifications are sent.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeRunID,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runner.Cancel(args[0])
+	},
+	SilenceUsage:  true,
+	SilenceErrors: true,
+}
+
+func init() {
+	rootCmd.AddCommand(cancelCmd)
+}
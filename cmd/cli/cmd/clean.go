@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/muhammedikinci/pin/internal/runner"
+	"github.com/spf13/cobra"
+)
+
+var cleanOlderThan time.Duration
+
+// cleanCmd represents the clean command
+var cleanCmd = &cobra.Command{
+	Use:   "clean",
+	Short: "Remove leftover networks, workspace volumes and old caches",
+	Long: `Remove docker networks created by pin for job services, workspace
+volumes of copyStrategy: delta created before the retention period and
+cache entries that were not used within it.
+
+Networks and volumes still used by running jobs are skipped.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runner.Clean(cleanOlderThan)
+	},
+	SilenceUsage:  true,
+	SilenceErrors: true,
+}
+
+func init() {
+	cleanCmd.Flags().DurationVar(&cleanOlderThan, "older-than", 7*24*time.Hour, "remove resources not used within this duration")
+
+	rootCmd.AddCommand(cleanCmd)
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"github.com/muhammedikinci/pin/internal/runner"
+	"github.com/spf13/cobra"
+)
+
+var compareOutput string
+
+// compareCmd represents the compare command
+var compareCmd = &cobra.Command{
+	Use:   "compare <run-a> <run-b>",
+	Short: "Compare two recorded runs",
+	Long: `Compare two runs from the run history and print, for every job,
+the duration delta, whether it is newly failing or fixed, image
+digest changes and artifact size changes.
+
+Use --output json to get a machine-readable result.`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeRunIDs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runner.Compare(args[0], args[1], compareOutput)
+	},
+	SilenceUsage:  true,
+	SilenceErrors: true,
+}
+
+func init() {
+	compareCmd.Flags().StringVarP(&compareOutput, "output", "o", "text", "output format (text or json)")
+
+	rootCmd.AddCommand(compareCmd)
+}
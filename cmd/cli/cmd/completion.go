@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"github.com/muhammedikinci/pin/internal/runner"
+	"github.com/spf13/cobra"
+)
+
+// completeRunID completes the first argument with the ids of recorded runs.
+func completeRunID(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return runner.CompleteRunIDs(), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeRunIDs completes every argument with the ids of recorded runs.
+func completeRunIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return runner.CompleteRunIDs(), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completePipelineFile completes the first argument with yaml files.
+func completePipelineFile(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return []string{"yaml", "yml"}, cobra.ShellCompDirectiveFilterFileExt
+}
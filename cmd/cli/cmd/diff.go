@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"github.com/muhammedikinci/pin/internal/runner"
+	"github.com/spf13/cobra"
+)
+
+var diffOutput string
+
+// diffCmd represents the diff command
+var diffCmd = &cobra.Command{
+	Use:   "diff <old.yaml> <new.yaml>",
+	Short: "Show semantic differences between two pipeline files",
+	Long: `Compare two pipeline configuration files and print what changed
+for the pipeline instead of a text diff: added and removed jobs,
+image changes, script changes and port changes.
+
+Use --output json to get a machine-readable result.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runner.Diff(args[0], args[1], diffOutput)
+	},
+	SilenceUsage:  true,
+	SilenceErrors: true,
+}
+
+func init() {
+	diffCmd.Flags().StringVarP(&diffOutput, "output", "o", "text", "output format (text or json)")
+
+	rootCmd.AddCommand(diffCmd)
+}
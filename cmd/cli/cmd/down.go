@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"github.com/muhammedikinci/pin/internal/runner"
+	"github.com/spf13/cobra"
+)
+
+// downCmd represents the down command
+var downCmd = &cobra.Command{
+	Use:   "down <run-id>",
+	Short: "Stop the containers a run left running",
+	Long: `Stop and remove the containers that jobs with detach: true left
+running after the run finished.
+
+Containers that were already removed are skipped, the run metadata no longer
+lists them afterwards.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeRunID,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runner.Down(args[0])
+	},
+	SilenceUsage:  true,
+	SilenceErrors: true,
+}
+
+func init() {
+	rootCmd.AddCommand(downCmd)
+}
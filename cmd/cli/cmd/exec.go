@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/muhammedikinci/pin/internal/runner"
+	"github.com/spf13/cobra"
+)
+
+var execFilePaths []string
+var execShell string
+var execSetValues []string
+var execEnvValues []string
+
+// execCmd represents the exec command
+var execCmd = &cobra.Command{
+	Use:   "exec <job>",
+	Short: "Open a shell in the container of a job",
+	Long: `Start the container of a job like pin apply does, with its image, env,
+services and copied or mounted project files, and attach an interactive
+shell to it instead of running the script.
+
+The container and its services are removed when the shell exits.`,
+	Args: cobra.ExactArgs(1),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) != 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		filepath := ""
+
+		if len(execFilePaths) > 0 {
+			filepath = execFilePaths[0]
+		}
+
+		return runner.CompleteJobNames(filepath), cobra.ShellCompDirectiveNoFileComp
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		err := runner.Exec(args[0], execFilePaths, runner.ExecOptions{Shell: execShell, Set: execSetValues, Env: execEnvValues})
+
+		if err != nil {
+			os.Exit(runner.ExitCode(err))
+		}
+	},
+}
+
+func init() {
+	execCmd.Flags().StringArrayVarP(&execFilePaths, "filepath", "f", []string{}, "pipeline configuration file path, repeat to merge files over each other")
+	execCmd.Flags().StringVar(&execShell, "shell", "sh", "shell started in the container")
+
+	execCmd.Flags().StringArrayVar(&execSetValues, "set", []string{}, "override a value of the pipeline, e.g. build.image=golang:1.18")
+	execCmd.Flags().StringArrayVar(&execEnvValues, "env", []string{}, "add a KEY=VALUE variable to the job")
+
+	execCmd.MarkFlagRequired("filepath")
+	execCmd.MarkFlagFilename("filepath", "yaml", "yml")
+
+	rootCmd.AddCommand(execCmd)
+}
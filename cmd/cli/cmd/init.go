@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"github.com/muhammedikinci/pin/internal/runner"
+	"github.com/spf13/cobra"
+)
+
+var initDockerfile bool
+var initForce bool
+
+// initCmd represents the init command
+var initCmd = &cobra.Command{
+	Use:   "init [go|node|python]",
+	Short: "Generate a starter pipeline file",
+	Long: `Generate a starter pipeline.yaml in the current directory for a go,
+node or python project. Without a template the project type is detected
+from the files in the directory (go.mod, package.json, requirements.txt,
+pyproject.toml or setup.py).
+
+Use --dockerfile to also generate a Dockerfile the job image is built from.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		template := ""
+
+		if len(args) > 0 {
+			template = args[0]
+		}
+
+		return runner.Init(template, runner.InitOptions{Dockerfile: initDockerfile, Force: initForce})
+	},
+	SilenceUsage:  true,
+	SilenceErrors: true,
+}
+
+func init() {
+	initCmd.Flags().BoolVar(&initDockerfile, "dockerfile", false, "also generate a Dockerfile")
+	initCmd.Flags().BoolVar(&initForce, "force", false, "overwrite existing files")
+
+	rootCmd.AddCommand(initCmd)
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"github.com/muhammedikinci/pin/internal/runner"
+	"github.com/spf13/cobra"
+)
+
+var lintFix bool
+var lintYes bool
+
+// lintCmd represents the lint command
+var lintCmd = &cobra.Command{
+	Use:   "lint <pipeline.yaml>",
+	Short: "Check a pipeline file for issues",
+	Long: `Check a pipeline configuration file and print its warnings.
+
+Use --fix to rewrite mechanical issues like unquoted or malformed ports.
+The changes are shown as a diff and applied after confirmation, use --yes
+to apply them without asking.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completePipelineFile,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runner.Lint(args[0], lintFix, lintYes)
+	},
+	SilenceUsage:  true,
+	SilenceErrors: true,
+}
+
+func init() {
+	lintCmd.Flags().BoolVar(&lintFix, "fix", false, "rewrite mechanical issues in the pipeline file")
+	lintCmd.Flags().BoolVarP(&lintYes, "yes", "y", false, "apply fixes without confirmation")
+
+	rootCmd.AddCommand(lintCmd)
+}
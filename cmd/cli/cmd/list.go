@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"github.com/muhammedikinci/pin/internal/runner"
+	"github.com/spf13/cobra"
+)
+
+var listTag string
+
+// listCmd represents the list command
+var listCmd = &cobra.Command{
+	Use:   "list <pipeline.yaml>",
+	Short: "List the jobs of a pipeline",
+	Long: `List the jobs of a pipeline configuration file in workflow order
+with whether they run in parallel, the job they wait for, their images,
+conditions, retry settings, tags and descriptions.
+
+Use --tag to list only the jobs carrying a tag.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completePipelineFile,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runner.List(args[0], listTag)
+	},
+	SilenceUsage:  true,
+	SilenceErrors: true,
+}
+
+func init() {
+	listCmd.Flags().StringVar(&listTag, "tag", "", "list only the jobs with this tag")
+	listCmd.RegisterFlagCompletionFunc("tag", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		filepath := ""
+
+		if len(args) > 0 {
+			filepath = args[0]
+		}
+
+		return runner.CompleteJobTags(filepath), cobra.ShellCompDirectiveNoFileComp
+	})
+
+	rootCmd.AddCommand(listCmd)
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"github.com/muhammedikinci/pin/internal/runner"
+	"github.com/spf13/cobra"
+)
+
+var logsFollow bool
+var logsJob string
+
+// logsCmd represents the logs command
+var logsCmd = &cobra.Command{
+	Use:   "logs <run-id>",
+	Short: "Print the output of a detached run",
+	Long: `Print the recorded output of a run started with pin apply --detach.
+
+Use --follow to keep printing until the run finishes and --job to print
+only the lines of one job.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeRunID,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runner.Logs(args[0], logsFollow, logsJob)
+	},
+	SilenceUsage:  true,
+	SilenceErrors: true,
+}
+
+func init() {
+	logsCmd.Flags().BoolVarP(&logsFollow, "follow", "f", false, "follow the output until the run finishes")
+	logsCmd.Flags().StringVar(&logsJob, "job", "", "print only the lines of this job")
+	logsCmd.RegisterFlagCompletionFunc("job", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		return runner.CompleteRunJobs(args[0]), cobra.ShellCompDirectiveNoFileComp
+	})
+
+	rootCmd.AddCommand(logsCmd)
+}
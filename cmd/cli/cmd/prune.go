@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"github.com/muhammedikinci/pin/internal/runner"
+	"github.com/spf13/cobra"
+)
+
+var pruneRunID string
+
+// pruneCmd represents the prune command
+var pruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove leftover containers, networks and built images",
+	Long: `Remove the docker resources labeled by pin: stopped job and service
+containers, networks and images built from a dockerfile.
+
+Use --run to remove only the resources of a single run. Running containers
+and resources they use are skipped.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runner.Prune(pruneRunID)
+	},
+	SilenceUsage:  true,
+	SilenceErrors: true,
+}
+
+func init() {
+	pruneCmd.Flags().StringVar(&pruneRunID, "run", "", "remove only the resources of this run id")
+
+	rootCmd.AddCommand(pruneCmd)
+}
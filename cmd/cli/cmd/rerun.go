@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/muhammedikinci/pin/internal/runner"
+	"github.com/spf13/cobra"
+)
+
+var rerunOnly string
+var rerunDebugOnFailure bool
+
+// rerunCmd represents the rerun command
+var rerunCmd = &cobra.Command{
+	Use:   "rerun <run-id>",
+	Short: "Execute a previous run again",
+	Long: `Execute a previous run again with the pipeline configuration stored
+for it, from the project directory it was started in.
+
+Use --only failed to execute only the jobs that failed.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeRunID,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if rerunOnly != "" && rerunOnly != "failed" {
+			return fmt.Errorf("unsupported --only value: %s", rerunOnly)
+		}
+
+		runner.DebugOnFailure = rerunDebugOnFailure
+
+		return runner.Rerun(args[0], rerunOnly == "failed")
+	},
+	SilenceUsage:  true,
+	SilenceErrors: true,
+}
+
+func init() {
+	rerunCmd.Flags().StringVar(&rerunOnly, "only", "", "rerun only a subset of jobs (failed)")
+	rerunCmd.Flags().BoolVar(&rerunDebugOnFailure, "debug-on-failure", false, "attach a shell to the container of a failed command before it is removed")
+
+	rootCmd.AddCommand(rerunCmd)
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"github.com/muhammedikinci/pin/internal/runner"
+	"github.com/spf13/cobra"
+)
+
+var retryDebugOnFailure bool
+
+// retryCmd represents the retry command
+var retryCmd = &cobra.Command{
+	Use:   "retry <run-id>",
+	Short: "Execute the failed jobs of a previous run again",
+	Long: `Execute the jobs of a previous run that failed, and the jobs that did
+not run because of them, with the pipeline configuration stored for it.
+
+Jobs that succeeded are not run again, the artifacts they collected are
+restored from the run history.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeRunID,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		runner.DebugOnFailure = retryDebugOnFailure
+
+		return runner.Retry(args[0])
+	},
+	SilenceUsage:  true,
+	SilenceErrors: true,
+}
+
+func init() {
+	retryCmd.Flags().BoolVar(&retryDebugOnFailure, "debug-on-failure", false, "attach a shell to the container of a failed command before it is removed")
+
+	rootCmd.AddCommand(retryCmd)
+}
@@ -1,14 +1,19 @@
 package cmd
 
 import (
+	"errors"
 	"os"
+	"path/filepath"
 
+	"github.com/fatih/color"
+	"github.com/muhammedikinci/pin/internal/log_level"
+	"github.com/muhammedikinci/pin/internal/runner"
 	"github.com/spf13/cobra"
 )
 
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
-	Use:   "cli",
+	Use:   "pin",
 	Short: "A brief description of your application",
 	Long: `A longer description that spans multiple lines and likely contains
 examples and usage of using your application. For example:
@@ -19,14 +24,95 @@ to quickly create a Cobra application.`,
 	// Uncomment the following line if your bare application
 	// has an action associated with it:
 	// Run: func(cmd *cobra.Command, args []string) { },
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if ascii {
+			runner.ASCIIOutput = true
+		}
+
+		// NO_COLOR and output that is not a terminal already disable colors
+		if noColor {
+			color.NoColor = true
+		}
+
+		if chaos != "" {
+			chaosMode, err := runner.ParseChaos(chaos)
+
+			if err != nil {
+				return err
+			}
+
+			runner.ChaosMode = chaosMode
+		}
+
+		// rerun and retry change to the project directory before the run
+		if logDir != "" {
+			dir, err := filepath.Abs(logDir)
+
+			if err != nil {
+				return err
+			}
+
+			runner.LogDir = dir
+		}
+
+		return setLogLevel(cmd)
+	},
+}
+
+var ascii bool
+var noColor bool
+var chaos string
+var logDir string
+var quiet bool
+var verbose bool
+var logLevel string
+
+// setLogLevel applies --quiet, --verbose or --log-level, only one of them
+// can be given.
+func setLogLevel(cmd *cobra.Command) error {
+	given := 0
+
+	for _, name := range []string{"quiet", "verbose", "log-level"} {
+		if cmd.Flags().Changed(name) {
+			given++
+		}
+	}
+
+	if given > 1 {
+		return errors.New("--quiet, --verbose and --log-level can not be used together")
+	}
+
+	level := log_level.Info
+
+	switch {
+	case quiet:
+		level = log_level.Quiet
+	case verbose:
+		level = log_level.Verbose
+	case logLevel != "":
+		parsed, err := log_level.Parse(logLevel)
+
+		if err != nil {
+			return err
+		}
+
+		level = parsed
+	}
+
+	log_level.Set(level)
+
+	return nil
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
-func Execute() {
+func Execute(version string) {
+	rootCmd.Version = version
+	runner.Version = version
+
 	err := rootCmd.Execute()
 	if err != nil {
-		os.Exit(1)
+		os.Exit(runner.ExitCode(err))
 	}
 }
 
@@ -36,6 +122,18 @@ func init() {
 	// will be global for your application.
 
 	// rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.cli.yaml)")
+	rootCmd.PersistentFlags().BoolVar(&ascii, "ascii", false, "use plain ASCII markers instead of glyphs in the output")
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "disable colors and cursor movements in the output, same as NO_COLOR")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "only show the output of failed jobs and the summary")
+	rootCmd.PersistentFlags().BoolVar(&verbose, "verbose", false, "also show docker api timings and copied files")
+	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "", "quiet, info or verbose")
+	rootCmd.PersistentFlags().StringVar(&logDir, "log-dir", "", "also write the output of every job to <dir>/<run id>/<job>.log")
+	rootCmd.MarkPersistentFlagDirname("log-dir")
+
+	// chaos is for testing retry handling of pipelines and of pin, it is not
+	// part of the documented interface
+	rootCmd.PersistentFlags().StringVar(&chaos, "chaos", "", "inject command failures, e.g. p=0.3,seed=42")
+	rootCmd.PersistentFlags().MarkHidden("chaos")
 
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/muhammedikinci/pin/internal/runner"
+	"github.com/spf13/cobra"
+)
+
+var statusOptions runner.StatusOptions
+
+// statusCmd represents the status command
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show recent runs or the docker resources pin left behind",
+	Long: `Show the recent runs with their status and detached containers.
+
+With --resources, list the containers, networks and workspace volumes of pin
+and the containers of detached jobs with their age and owning run. Resources
+older than their ttl are reported as stale and --clean removes them.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runner.Status(statusOptions)
+	},
+	SilenceUsage:  true,
+	SilenceErrors: true,
+}
+
+func init() {
+	statusCmd.Flags().BoolVar(&statusOptions.Resources, "resources", false, "list the docker resources of pin")
+	statusCmd.Flags().DurationVar(&statusOptions.TTL, "ttl", 24*time.Hour, "age after which job and service containers and networks are stale, 0 never")
+	statusCmd.Flags().DurationVar(&statusOptions.DetachedTTL, "detached-ttl", 7*24*time.Hour, "age after which containers of detached jobs are stale, 0 never")
+	statusCmd.Flags().DurationVar(&statusOptions.VolumeTTL, "volume-ttl", 30*24*time.Hour, "age after which workspace volumes are stale, 0 never")
+	statusCmd.Flags().BoolVar(&statusOptions.Clean, "clean", false, "remove the stale resources")
+
+	rootCmd.AddCommand(statusCmd)
+}
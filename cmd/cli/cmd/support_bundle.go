@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"github.com/muhammedikinci/pin/internal/runner"
+	"github.com/spf13/cobra"
+)
+
+var supportBundlePipeline string
+var supportBundleOutput string
+
+// supportBundleCmd represents the support-bundle command
+var supportBundleCmd = &cobra.Command{
+	Use:   "support-bundle",
+	Short: "Collect diagnostics for a bug report",
+	Long: `Collect the pipeline configuration, its resolved plan, the latest
+runs, docker information and the pin version into a tar.gz file that can
+be attached to a bug report.
+
+Secret looking values, credentials in urls and webhooks are redacted.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runner.SupportBundle(supportBundlePipeline, supportBundleOutput)
+	},
+	SilenceUsage:  true,
+	SilenceErrors: true,
+}
+
+func init() {
+	supportBundleCmd.Flags().StringVarP(&supportBundlePipeline, "filepath", "f", "", "pipeline configuration file path")
+	supportBundleCmd.Flags().StringVarP(&supportBundleOutput, "output", "o", "", "bundle file (default pin-support-<time>.tar.gz)")
+
+	rootCmd.AddCommand(supportBundleCmd)
+}
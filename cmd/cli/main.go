@@ -2,6 +2,9 @@ package main
 
 import "github.com/muhammedikinci/pin/cmd/cli/cmd"
 
+// version is set by goreleaser through ldflags.
+var version = "dev"
+
 func main() {
-	cmd.Execute()
+	cmd.Execute(version)
 }
@@ -2,23 +2,37 @@ package container_manager
 
 import (
 	"archive/tar"
-	"bytes"
+	"compress/gzip"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"os"
+	"path"
 	"path/filepath"
-	"regexp"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/network"
 	"github.com/docker/go-connections/nat"
 	"github.com/fatih/color"
+	"github.com/muhammedikinci/pin/internal/glob"
+	"github.com/muhammedikinci/pin/internal/ignore"
 	"github.com/muhammedikinci/pin/internal/interfaces"
+	"github.com/muhammedikinci/pin/internal/log_level"
+	"github.com/muhammedikinci/pin/internal/pin_error"
 )
 
+// ManagedLabel marks docker resources created by pin so they can be found
+// and cleaned later.
+const ManagedLabel = "pin.managed"
+
 type containerManager struct {
 	cli interfaces.Client
 	log interfaces.Log
@@ -31,17 +45,17 @@ func NewContainerManager(cli interfaces.Client, log interfaces.Log) containerMan
 	}
 }
 
-func (cm containerManager) StartContainer(ctx context.Context, jobName string, image string, ports map[string]string) (container.ContainerCreateCreatedBody, error) {
+func (cm containerManager) StartContainer(ctx context.Context, options interfaces.ContainerOptions) (container.ContainerCreateCreatedBody, error) {
 	color.Set(color.FgGreen)
 	cm.log.Println("Start creating container")
 	color.Unset()
 
-	containerName := jobName + "_" + strconv.Itoa(int(time.Now().UnixMilli()))
+	containerName := options.Name + "_" + strconv.Itoa(int(time.Now().UnixMilli()))
 
 	portBindings := nat.PortMap{}
 	exposedPorts := nat.PortSet{}
 
-	for out, in := range ports {
+	for out, in := range options.Ports {
 		inPort, _ := nat.NewPort("tcp", in)
 
 		if _, ok := portBindings[inPort]; ok {
@@ -53,13 +67,37 @@ func (cm containerManager) StartContainer(ctx context.Context, jobName string, i
 		exposedPorts[inPort] = struct{}{}
 	}
 
-	hostConfig := &container.HostConfig{PortBindings: portBindings}
+	hostConfig := &container.HostConfig{
+		PortBindings: portBindings,
+		Binds:        options.Volumes,
+		Privileged:   options.Privileged,
+		CapAdd:       options.CapAdd,
+		CapDrop:      options.CapDrop,
+		ExtraHosts:   options.ExtraHosts,
+		DNS:          options.DNS,
+		DNSSearch:    options.DNSSearch,
+	}
+
+	var networkingConfig *network.NetworkingConfig
+
+	if options.Network != "" {
+		hostConfig.NetworkMode = container.NetworkMode(options.Network)
+		networkingConfig = &network.NetworkingConfig{
+			EndpointsConfig: map[string]*network.EndpointSettings{
+				options.Network: {Aliases: options.NetworkAliases},
+			},
+		}
+	}
 
 	resp, err := cm.cli.ContainerCreate(ctx, &container.Config{
-		Image:        image,
+		Image:        options.Image,
+		Hostname:     options.Hostname,
 		Tty:          true,
 		ExposedPorts: exposedPorts,
-	}, hostConfig, nil, nil, containerName)
+		Env:          options.Env,
+		Labels:       managedLabels(options.Labels),
+		Healthcheck:  options.Healthcheck,
+	}, hostConfig, networkingConfig, nil, containerName)
 
 	if err != nil {
 		return container.ContainerCreateCreatedBody{}, err
@@ -68,11 +106,13 @@ func (cm containerManager) StartContainer(ctx context.Context, jobName string, i
 	return resp, nil
 }
 
-func (cm containerManager) StopContainer(ctx context.Context, containerID string) error {
+// StopContainer sends SIGTERM and waits timeout before SIGKILL, a nil
+// timeout falls back to the daemon default.
+func (cm containerManager) StopContainer(ctx context.Context, containerID string, timeout *time.Duration) error {
 	color.Set(color.FgBlue)
 	cm.log.Println("Container stopping")
 
-	if err := cm.cli.ContainerStop(ctx, containerID, nil); err != nil {
+	if err := cm.cli.ContainerStop(ctx, containerID, timeout); err != nil {
 		return err
 	}
 
@@ -96,60 +136,621 @@ func (cm containerManager) RemoveContainer(ctx context.Context, containerID stri
 	return nil
 }
 
-func (cm containerManager) CopyToContainer(ctx context.Context, containerID, workDir string, copyIgnore []string) error {
-	var buf bytes.Buffer
+func (cm containerManager) CreateNetwork(ctx context.Context, name string, labels map[string]string) (string, error) {
+	resp, err := cm.cli.NetworkCreate(ctx, name, types.NetworkCreate{
+		CheckDuplicate: true,
+		Driver:         "bridge",
+		Labels:         managedLabels(labels),
+	})
+
+	if err != nil {
+		return "", err
+	}
 
-	tw := tar.NewWriter(&buf)
-	defer tw.Close()
+	return resp.ID, nil
+}
 
-	currentPath, _ := os.Getwd()
+func (cm containerManager) RemoveNetwork(ctx context.Context, networkID string) error {
+	return cm.cli.NetworkRemove(ctx, networkID)
+}
 
-	err := filepath.Walk(currentPath, func(path string, info os.FileInfo, err error) error {
-		return cm.appender(path, info, err, currentPath, tw, copyIgnore)
+// PruneNetworks removes pin networks created before olderThan that have all
+// the given labels, networks still used by running jobs are skipped.
+func (cm containerManager) PruneNetworks(ctx context.Context, olderThan time.Duration, labels []string) ([]string, error) {
+	networks, err := cm.cli.NetworkList(ctx, types.NetworkListOptions{
+		Filters: LabelFilters(labels),
 	})
 
 	if err != nil {
-		return err
+		return []string{}, err
+	}
+
+	removed := []string{}
+	threshold := time.Now().Add(-olderThan)
+
+	for _, n := range networks {
+		if n.Created.After(threshold) {
+			continue
+		}
+
+		if err := cm.cli.NetworkRemove(ctx, n.ID); err != nil {
+			cm.log.Printf("Network skipped: %s (%s)", n.Name, err)
+			continue
+		}
+
+		removed = append(removed, n.Name)
+	}
+
+	return removed, nil
+}
+
+// PruneVolumes removes the volumes whose name starts with prefix created
+// before olderThan, volumes still used by a container are skipped.
+func (cm containerManager) PruneVolumes(ctx context.Context, prefix string, olderThan time.Duration) ([]string, error) {
+	volumes, err := cm.cli.VolumeList(ctx, filters.NewArgs(filters.Arg("name", prefix)))
+
+	if err != nil {
+		return []string{}, err
+	}
+
+	removed := []string{}
+	threshold := time.Now().Add(-olderThan)
+
+	for _, v := range volumes.Volumes {
+		// the name filter matches anywhere in the name
+		if !strings.HasPrefix(v.Name, prefix) {
+			continue
+		}
+
+		if created, err := time.Parse(time.RFC3339, v.CreatedAt); err == nil && created.After(threshold) {
+			continue
+		}
+
+		if err := cm.cli.VolumeRemove(ctx, v.Name, false); err != nil {
+			cm.log.Printf("Volume skipped: %s (%s)", v.Name, err)
+			continue
+		}
+
+		removed = append(removed, v.Name)
+	}
+
+	return removed, nil
+}
+
+// PruneContainers force removes the stopped pin containers that have all the
+// given labels, containers of running jobs are kept.
+func (cm containerManager) PruneContainers(ctx context.Context, labels []string) ([]string, error) {
+	containers, err := cm.cli.ContainerList(ctx, types.ContainerListOptions{
+		All:     true,
+		Filters: LabelFilters(labels),
+	})
+
+	if err != nil {
+		return []string{}, err
+	}
+
+	removed := []string{}
+
+	for _, c := range containers {
+		if c.State == "running" {
+			continue
+		}
+
+		if err := cm.cli.ContainerRemove(ctx, c.ID, types.ContainerRemoveOptions{Force: true}); err != nil {
+			cm.log.Printf("Container skipped: %s (%s)", c.ID, err)
+			continue
+		}
+
+		name := c.ID
+
+		if len(c.Names) > 0 {
+			name = strings.TrimPrefix(c.Names[0], "/")
+		}
+
+		removed = append(removed, name)
+	}
+
+	return removed, nil
+}
+
+// ListResources returns the pin containers and networks, and the volumes
+// whose name starts with volumePrefix because docker creates the volumes of
+// binds without labels.
+func (cm containerManager) ListResources(ctx context.Context, volumePrefix string) ([]interfaces.Resource, error) {
+	resources := []interfaces.Resource{}
+
+	containers, err := cm.cli.ContainerList(ctx, types.ContainerListOptions{
+		All:     true,
+		Filters: LabelFilters(nil),
+	})
+
+	if err != nil {
+		return resources, err
+	}
+
+	for _, c := range containers {
+		name := c.ID
+
+		if len(c.Names) > 0 {
+			name = strings.TrimPrefix(c.Names[0], "/")
+		}
+
+		resources = append(resources, interfaces.Resource{
+			Kind:    interfaces.ResourceContainer,
+			ID:      c.ID,
+			Name:    name,
+			State:   c.State,
+			Labels:  c.Labels,
+			Created: time.Unix(c.Created, 0),
+		})
+	}
+
+	networks, err := cm.cli.NetworkList(ctx, types.NetworkListOptions{
+		Filters: LabelFilters(nil),
+	})
+
+	if err != nil {
+		return resources, err
+	}
+
+	for _, n := range networks {
+		resources = append(resources, interfaces.Resource{
+			Kind:    interfaces.ResourceNetwork,
+			ID:      n.ID,
+			Name:    n.Name,
+			Labels:  n.Labels,
+			Created: n.Created,
+		})
+	}
+
+	if volumePrefix == "" {
+		return resources, nil
+	}
+
+	volumes, err := cm.cli.VolumeList(ctx, filters.NewArgs(filters.Arg("name", volumePrefix)))
+
+	if err != nil {
+		return resources, err
+	}
+
+	for _, v := range volumes.Volumes {
+		// the name filter matches anywhere in the name
+		if !strings.HasPrefix(v.Name, volumePrefix) {
+			continue
+		}
+
+		created, _ := time.Parse(time.RFC3339, v.CreatedAt)
+
+		resources = append(resources, interfaces.Resource{
+			Kind:    interfaces.ResourceVolume,
+			ID:      v.Name,
+			Name:    v.Name,
+			Labels:  v.Labels,
+			Created: created,
+		})
+	}
+
+	return resources, nil
+}
+
+// RemoveResource force removes a container, running or not, or removes a
+// network or a volume that is not in use.
+func (cm containerManager) RemoveResource(ctx context.Context, resource interfaces.Resource) error {
+	switch resource.Kind {
+	case interfaces.ResourceContainer:
+		return cm.cli.ContainerRemove(ctx, resource.ID, types.ContainerRemoveOptions{Force: true})
+	case interfaces.ResourceNetwork:
+		return cm.cli.NetworkRemove(ctx, resource.ID)
+	case interfaces.ResourceVolume:
+		return cm.cli.VolumeRemove(ctx, resource.ID, false)
+	}
+
+	return fmt.Errorf("unknown resource kind: %s", resource.Kind)
+}
+
+// managedLabels adds ManagedLabel to the labels of a resource.
+func managedLabels(labels map[string]string) map[string]string {
+	result := map[string]string{ManagedLabel: "true"}
+
+	for k, v := range labels {
+		result[k] = v
+	}
+
+	return result
+}
+
+// LabelFilters matches pin resources that also have all the given labels,
+// a label is either a key or a key=value pair.
+func LabelFilters(labels []string) filters.Args {
+	args := filters.NewArgs(filters.Arg("label", ManagedLabel))
+
+	for _, label := range labels {
+		args.Add("label", label)
+	}
+
+	return args
+}
+
+// WaitForContainer polls the container state until it is running, or healthy
+// when it has a healthcheck, or the timeout is exceeded.
+func (cm containerManager) WaitForContainer(ctx context.Context, containerID string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		inspect, err := cm.cli.ContainerInspect(ctx, containerID)
+
+		if err != nil {
+			return err
+		}
+
+		if inspect.State != nil {
+			if inspect.State.Running {
+				health := inspect.State.Health
+
+				if health == nil || health.Status == types.NoHealthcheck || health.Status == types.Healthy {
+					return nil
+				}
+
+				if health.Status == types.Unhealthy {
+					return fmt.Errorf("container is unhealthy: %s", lastHealthOutput(health))
+				}
+			}
+
+			if inspect.State.Status == "exited" || inspect.State.Status == "dead" {
+				return fmt.Errorf("container exited with code %d", inspect.State.ExitCode)
+			}
+		}
+
+		if time.Now().After(deadline) {
+			return errors.New("timed out waiting for container to be ready")
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+}
+
+func lastHealthOutput(health *types.Health) string {
+	if len(health.Log) == 0 {
+		return "no healthcheck output"
+	}
+
+	return strings.TrimSpace(health.Log[len(health.Log)-1].Output)
+}
+
+// copyProgressInterval is how many files are copied between two progress
+// logs.
+const copyProgressInterval = 1000
+
+// countingWriter counts the bytes written through it.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+
+	return n, err
+}
+
+// copyAttempts is how often a copy that breaks off mid-stream is tried, a
+// large copy over a remote daemon occasionally does.
+const copyAttempts = 3
+
+var copyRetryDelay = time.Second
+
+// transientCopyError reports whether err is a stream that ended early, other
+// errors fail the same way when retried.
+func transientCopyError(err error) bool {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+
+	// the docker client does not always wrap the transport error
+	msg := err.Error()
+
+	return strings.Contains(msg, "unexpected EOF") || strings.Contains(msg, "connection reset by peer") || strings.HasSuffix(msg, ": EOF")
+}
+
+// retryCopy runs try until it succeeds, fails with an error that is not
+// transient or used up copyAttempts, the last transient error is returned as
+// a COPY_FAILED error.
+func (cm containerManager) retryCopy(ctx context.Context, operation, what string, try func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := try()
+
+		if err == nil || ctx.Err() != nil || !transientCopyError(err) {
+			return err
+		}
+
+		if attempt == copyAttempts {
+			return pin_error.CopyFailed(operation, what, attempt, err)
+		}
+
+		color.Set(color.FgYellow)
+		cm.log.Printf("%s broke off: %s, retrying (attempt %d of %d)", what, err, attempt+1, copyAttempts)
+		color.Unset()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(copyRetryDelay):
+		}
+	}
+}
+
+// CopyToContainer streams the project tar to docker while it is written, so
+// the archive is never held in memory. A copy that breaks off is started over.
+func (cm containerManager) CopyToContainer(ctx context.Context, containerID, workDir string, copyIgnore, copyInclude []string, compression string) error {
+	return cm.retryCopy(ctx, "CopyToContainer", "copying project files to "+workDir, func() error {
+		return cm.copyToContainer(ctx, containerID, workDir, copyIgnore, copyInclude, compression)
+	})
+}
+
+func (cm containerManager) copyToContainer(ctx context.Context, containerID, workDir string, copyIgnore, copyInclude []string, compression string) error {
+	pr, pw := io.Pipe()
+
+	sent := &countingWriter{w: pw}
+
+	var w io.Writer = sent
+	var gw *gzip.Writer
+
+	if compression == interfaces.CompressionGzip {
+		gw = gzip.NewWriter(sent)
+		w = gw
 	}
 
-	err = cm.cli.CopyToContainer(ctx, containerID, workDir, &buf, types.CopyToContainerOptions{})
+	archived := &countingWriter{w: w}
+	tw := tar.NewWriter(archived)
+
+	currentPath, _ := os.Getwd()
+
+	files := 0
+	done := make(chan error, 1)
+
+	go func() {
+		err := filepath.Walk(currentPath, func(path string, info os.FileInfo, err error) error {
+			before := archived.n
+
+			if err := cm.appender(path, info, err, currentPath, tw, copyIgnore, copyInclude); err != nil {
+				return err
+			}
+
+			// appender writes the tar header right away when it adds a file
+			if archived.n > before {
+				files++
+
+				if log_level.IsVerbose() {
+					rel, _ := filepath.Rel(currentPath, path)
+					cm.log.Printf("Copying %s (%d bytes)", filepath.ToSlash(rel), info.Size())
+				} else if files%copyProgressInterval == 0 {
+					cm.log.Printf("Copying project files: %d files, %d bytes", files, archived.n)
+				}
+			}
+
+			return nil
+		})
+
+		if err == nil {
+			err = tw.Close()
+		}
+
+		if err == nil && gw != nil {
+			err = gw.Close()
+		}
+
+		pw.CloseWithError(err)
+		done <- err
+	}()
+
+	err := cm.cli.CopyToContainer(ctx, containerID, workDir, pr, types.CopyToContainerOptions{})
+
+	// unblocks the walk when docker stopped reading early
+	pr.CloseWithError(io.ErrClosedPipe)
+
+	if walkErr := <-done; walkErr != nil && !errors.Is(walkErr, io.ErrClosedPipe) {
+		return walkErr
+	}
 
 	if err != nil {
 		return err
 	}
 
+	if gw != nil {
+		cm.log.Printf("Project files copied: %d files, %d bytes compressed with gzip to %d bytes", files, archived.n, sent.n)
+	} else {
+		cm.log.Printf("Project files copied: %d files, %d bytes", files, archived.n)
+	}
+
 	return nil
 }
 
-func (cm containerManager) appender(path string, info os.FileInfo, err error, currentPath string, tw *tar.Writer, copyIgnore []string) error {
+// CopyFromContainer copies the files matching the glob patterns out of the
+// container into destination, keeping their path relative to workDir. The
+// extraction of a pattern that breaks off is started over.
+func (cm containerManager) CopyFromContainer(ctx context.Context, containerID, workDir string, patterns []string, destination string) ([]string, error) {
+	copied := []string{}
+	seen := map[string]bool{}
+
+	for _, pattern := range patterns {
+		absPattern := pattern
+
+		if !path.IsAbs(pattern) {
+			absPattern = path.Join(workDir, pattern)
+		}
+
+		root := glob.Root(absPattern)
+
+		var files []string
+		found := true
+
+		err := cm.retryCopy(ctx, "CopyFromContainer", "copying artifacts of "+pattern, func() error {
+			reader, stat, err := cm.cli.CopyFromContainer(ctx, containerID, root)
+
+			if err != nil && transientCopyError(err) {
+				return err
+			}
+
+			if err != nil {
+				found = false
+				return nil
+			}
+
+			defer reader.Close()
+
+			// files of a failed attempt are extracted again
+			attemptSeen := map[string]bool{}
+
+			for key := range seen {
+				attemptSeen[key] = true
+			}
+
+			files, err = cm.extractArtifacts(reader, path.Dir(root), absPattern, workDir, destination, attemptSeen)
+
+			if err != nil {
+				return err
+			}
+
+			if err := verifyExtraction(stat, files, destination); err != nil {
+				return err
+			}
+
+			seen = attemptSeen
+
+			return nil
+		})
+
+		if err != nil {
+			return copied, err
+		}
+
+		if !found {
+			cm.log.Printf("Artifact not found: %s", pattern)
+			continue
+		}
+
+		copied = append(copied, files...)
+	}
+
+	return copied, nil
+}
+
+// verifyExtraction compares a copied single file with the size docker
+// reported for it, the sizes of archived files are checked while extracting.
+func verifyExtraction(stat types.ContainerPathStat, files []string, destination string) error {
+	if stat.Name == "" || !stat.Mode.IsRegular() || len(files) != 1 {
+		return nil
+	}
+
+	info, err := os.Stat(filepath.Join(destination, filepath.FromSlash(files[0])))
+
 	if err != nil {
 		return err
 	}
 
-	if !info.Mode().IsRegular() {
-		return nil
+	if info.Size() != stat.Size {
+		return fmt.Errorf("%s: extracted %d of %d bytes: %w", files[0], info.Size(), stat.Size, io.ErrUnexpectedEOF)
 	}
 
-	for _, ignore := range copyIgnore {
-		if info.IsDir() && info.Name() == ignore {
-			return filepath.SkipDir
+	return nil
+}
+
+func (cm containerManager) extractArtifacts(reader io.Reader, parent, pattern, workDir, destination string, seen map[string]bool) ([]string, error) {
+	copied := []string{}
+	tr := tar.NewReader(reader)
+
+	for {
+		header, err := tr.Next()
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return copied, err
 		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		containerPath := path.Join(parent, header.Name)
+
+		if seen[containerPath] || !glob.Match(pattern, containerPath) {
+			continue
+		}
+
+		seen[containerPath] = true
+
+		rel := strings.TrimPrefix(containerPath, "/")
+
+		if strings.HasPrefix(containerPath, strings.TrimSuffix(workDir, "/")+"/") {
+			rel = strings.TrimPrefix(containerPath, strings.TrimSuffix(workDir, "/")+"/")
+		}
+
+		target := filepath.Join(destination, filepath.FromSlash(rel))
+
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return copied, err
+		}
+
+		f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(header.Mode).Perm())
+
+		if err != nil {
+			return copied, err
+		}
+
+		n, err := io.Copy(f, tr)
+		f.Close()
+
+		if err != nil {
+			return copied, err
+		}
+
+		if n != header.Size {
+			return copied, fmt.Errorf("%s: extracted %d of %d bytes: %w", rel, n, header.Size, io.ErrUnexpectedEOF)
+		}
+
+		copied = append(copied, rel)
 	}
 
-	header, err := tar.FileInfoHeader(info, info.Name())
+	return copied, nil
+}
+
+func (cm containerManager) appender(path string, info os.FileInfo, err error, currentPath string, tw *tar.Writer, copyIgnore, copyInclude []string) error {
 	if err != nil {
 		return err
 	}
 
-	header.Name = strings.TrimPrefix(strings.Replace(path, currentPath, "", -1), string(filepath.Separator))
-	header.Name = strings.ReplaceAll(header.Name, "\\", "/")
+	name := strings.TrimPrefix(strings.Replace(path, currentPath, "", -1), string(filepath.Separator))
+	name = strings.ReplaceAll(name, "\\", "/")
 
-	for _, ignore := range copyIgnore {
-		if mathced, err := regexp.MatchString(ignore, header.Name); err != nil || mathced {
-			return nil
+	// an excluded directory is not walked, so files in it can not be
+	// included again by a negated pattern
+	if info.IsDir() {
+		if name != "" && (ignore.Ignored(name, copyIgnore) || !ignore.Included(name, true, copyInclude)) {
+			return filepath.SkipDir
 		}
+
+		return nil
+	}
+
+	if !info.Mode().IsRegular() || ignore.Ignored(name, copyIgnore) || !ignore.Included(name, false, copyInclude) {
+		return nil
 	}
 
+	header, err := tar.FileInfoHeader(info, info.Name())
+	if err != nil {
+		return err
+	}
+
+	header.Name = name
+
 	if err := tw.WriteHeader(header); err != nil {
 		return err
 	}
@@ -167,3 +768,72 @@ func (cm containerManager) appender(path string, info os.FileInfo, err error, cu
 
 	return nil
 }
+
+// SampleResourceUsage reads the docker stats stream of the container until
+// ctx is cancelled or the stream ends and returns the observed peaks.
+func (cm containerManager) SampleResourceUsage(ctx context.Context, containerID string) (interfaces.ResourceUsage, error) {
+	usage := interfaces.ResourceUsage{}
+
+	stats, err := cm.cli.ContainerStats(ctx, containerID, true)
+
+	if err != nil {
+		return usage, err
+	}
+
+	defer stats.Body.Close()
+
+	decoder := json.NewDecoder(stats.Body)
+
+	for {
+		var stat types.StatsJSON
+
+		if err := decoder.Decode(&stat); err != nil {
+			if err == io.EOF || ctx.Err() != nil {
+				return usage, nil
+			}
+
+			return usage, err
+		}
+
+		usage.Samples++
+
+		if cpu := cpuPercent(stat); cpu > usage.PeakCPUPercent {
+			usage.PeakCPUPercent = cpu
+		}
+
+		if stat.MemoryStats.MaxUsage > usage.PeakMemoryBytes {
+			usage.PeakMemoryBytes = stat.MemoryStats.MaxUsage
+		}
+
+		if stat.MemoryStats.Usage > usage.PeakMemoryBytes {
+			usage.PeakMemoryBytes = stat.MemoryStats.Usage
+		}
+
+		var rx, tx uint64
+
+		for _, network := range stat.Networks {
+			rx += network.RxBytes
+			tx += network.TxBytes
+		}
+
+		usage.NetworkRxBytes = rx
+		usage.NetworkTxBytes = tx
+	}
+}
+
+func cpuPercent(stat types.StatsJSON) float64 {
+	cpuDelta := float64(stat.CPUStats.CPUUsage.TotalUsage) - float64(stat.PreCPUStats.CPUUsage.TotalUsage)
+	systemDelta := float64(stat.CPUStats.SystemUsage) - float64(stat.PreCPUStats.SystemUsage)
+
+	if cpuDelta <= 0 || systemDelta <= 0 {
+		return 0
+	}
+
+	onlineCPUs := float64(stat.CPUStats.OnlineCPUs)
+
+	if onlineCPUs == 0 {
+		onlineCPUs = float64(len(stat.CPUStats.CPUUsage.PercpuUsage))
+	}
+
+	return cpuDelta / systemDelta * onlineCPUs * 100
+}
@@ -3,6 +3,7 @@ package container_manager
 import (
 	"archive/tar"
 	"bytes"
+	"compress/gzip"
 	"context"
 	"errors"
 	"fmt"
@@ -12,10 +13,17 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	volumetypes "github.com/docker/docker/api/types/volume"
 	"github.com/golang/mock/gomock"
+	"github.com/muhammedikinci/pin/internal/interfaces"
 	"github.com/muhammedikinci/pin/internal/mocks"
+	"github.com/muhammedikinci/pin/internal/pin_error"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -43,7 +51,7 @@ func TestWhenContainerCreateReturnErrorStartContainerMustReturnSameError(t *test
 		log: mockLog,
 	}
 
-	resp, err := cm.StartContainer(context.Background(), "", "", map[string]string{})
+	resp, err := cm.StartContainer(context.Background(), interfaces.ContainerOptions{})
 
 	assert.Equal(t, resp, container.ContainerCreateCreatedBody{})
 	assert.Equal(t, err, merror)
@@ -75,12 +83,44 @@ func TestWhenContainerCreateReturnResponseStartContainerMustSameResponseWithNilE
 		log: mockLog,
 	}
 
-	resp, err := cm.StartContainer(context.Background(), "", "", map[string]string{})
+	resp, err := cm.StartContainer(context.Background(), interfaces.ContainerOptions{})
 
 	assert.Equal(t, resp.ID, mres.ID)
 	assert.Equal(t, err, nil)
 }
 
+func TestStartContainerMustSetHostnameAndLabels(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	mockCli := mocks.NewMockClient(ctrl)
+	mockLog := mocks.NewMockLog(ctrl)
+
+	mockLog.
+		EXPECT().
+		Println("Start creating container")
+
+	mockCli.
+		EXPECT().
+		ContainerCreate(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, config *container.Config, _ *container.HostConfig, _ *network.NetworkingConfig, _ *specs.Platform, _ string) (container.ContainerCreateCreatedBody, error) {
+			assert.Equal(t, "db-test.local", config.Hostname)
+			assert.Equal(t, map[string]string{ManagedLabel: "true", "com.example.team": "core"}, config.Labels)
+
+			return container.ContainerCreateCreatedBody{ID: "test"}, nil
+		})
+
+	cm := containerManager{
+		cli: mockCli,
+		log: mockLog,
+	}
+
+	_, err := cm.StartContainer(context.Background(), interfaces.ContainerOptions{Hostname: "db-test.local", Labels: map[string]string{"com.example.team": "core"}})
+
+	assert.Equal(t, err, nil)
+}
+
 func TestWhenContainerStopReturnErrorStopContainerMustReturnSameError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 
@@ -105,7 +145,7 @@ func TestWhenContainerStopReturnErrorStopContainerMustReturnSameError(t *testing
 		log: mockLog,
 	}
 
-	err := cm.StopContainer(context.Background(), "")
+	err := cm.StopContainer(context.Background(), "", nil)
 
 	assert.Equal(t, err, merror)
 }
@@ -136,7 +176,7 @@ func TestWhenContainerStopReturnNilStopContainerMustReturnNil(t *testing.T) {
 		log: mockLog,
 	}
 
-	err := cm.StopContainer(context.Background(), "")
+	err := cm.StopContainer(context.Background(), "", nil)
 
 	assert.Equal(t, err, nil)
 }
@@ -212,7 +252,7 @@ func TestAppender(t *testing.T) {
 	cm := containerManager{}
 
 	err := filepath.Walk(currentPath, func(path string, info os.FileInfo, err error) error {
-		return cm.appender(path, info, err, currentPath, tw, []string{"node_modules", "ignore_test1.txt", ".test_point_folder"})
+		return cm.appender(path, info, err, currentPath, tw, []string{"node_modules", "ignore_test1.txt", ".test_point_folder"}, nil)
 	})
 
 	assert.Equal(t, err, nil)
@@ -243,3 +283,670 @@ func TestAppender(t *testing.T) {
 
 	assert.Contains(t, headerNames, "ignore_test/ignore_test2.py")
 }
+
+func TestAppenderMustPruneIgnoredDirectoriesAndHonourNegations(t *testing.T) {
+	dir := t.TempDir()
+
+	os.MkdirAll(filepath.Join(dir, "node_modules", "left-pad"), 0755)
+	os.WriteFile(filepath.Join(dir, "node_modules", "left-pad", "index.js"), []byte(""), 0644)
+	os.WriteFile(filepath.Join(dir, "debug.log"), []byte(""), 0644)
+	os.WriteFile(filepath.Join(dir, "keep.log"), []byte(""), 0644)
+	os.WriteFile(filepath.Join(dir, "index.js"), []byte(""), 0644)
+
+	var buf bytes.Buffer
+
+	tw := tar.NewWriter(&buf)
+	cm := containerManager{}
+	visited := []string{}
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		visited = append(visited, info.Name())
+		return cm.appender(path, info, err, dir, tw, []string{"^node_modules$", `\.log$`, `!^keep\.log$`}, nil)
+	})
+
+	assert.Equal(t, err, nil)
+
+	tw.Close()
+
+	headerNames := []string{}
+	tr := tar.NewReader(&buf)
+
+	for {
+		header, err := tr.Next()
+
+		if err != nil {
+			break
+		}
+
+		headerNames = append(headerNames, header.Name)
+	}
+
+	assert.Equal(t, []string{"index.js", "keep.log"}, headerNames)
+	assert.NotContains(t, visited, "left-pad")
+}
+
+func TestAppenderMustCopyOnlyIncludedPaths(t *testing.T) {
+	dir := t.TempDir()
+
+	os.MkdirAll(filepath.Join(dir, "services", "api"), 0755)
+	os.MkdirAll(filepath.Join(dir, "services", "web"), 0755)
+	os.WriteFile(filepath.Join(dir, "services", "api", "main.go"), []byte(""), 0644)
+	os.WriteFile(filepath.Join(dir, "services", "web", "index.js"), []byte(""), 0644)
+	os.WriteFile(filepath.Join(dir, "go.mod"), []byte(""), 0644)
+	os.WriteFile(filepath.Join(dir, "README.md"), []byte(""), 0644)
+
+	var buf bytes.Buffer
+
+	tw := tar.NewWriter(&buf)
+	cm := containerManager{}
+	visited := []string{}
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		visited = append(visited, info.Name())
+		return cm.appender(path, info, err, dir, tw, nil, []string{"services/api/**", "go.mod"})
+	})
+
+	assert.Equal(t, err, nil)
+
+	tw.Close()
+
+	headerNames := []string{}
+	tr := tar.NewReader(&buf)
+
+	for {
+		header, err := tr.Next()
+
+		if err != nil {
+			break
+		}
+
+		headerNames = append(headerNames, header.Name)
+	}
+
+	assert.Equal(t, []string{"go.mod", "services/api/main.go"}, headerNames)
+	assert.NotContains(t, visited, "index.js")
+}
+
+func TestSampleResourceUsageMustReturnPeaksOfStatsStream(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	mockCli := mocks.NewMockClient(ctrl)
+	mockLog := mocks.NewMockLog(ctrl)
+
+	var buf bytes.Buffer
+
+	fmt.Fprintln(&buf, `{"cpu_stats":{"cpu_usage":{"total_usage":200},"system_cpu_usage":1000,"online_cpus":2},"precpu_stats":{"cpu_usage":{"total_usage":100},"system_cpu_usage":800},"memory_stats":{"usage":2048},"networks":{"eth0":{"rx_bytes":10,"tx_bytes":20}}}`)
+	fmt.Fprintln(&buf, `{"cpu_stats":{"cpu_usage":{"total_usage":210},"system_cpu_usage":1200,"online_cpus":2},"precpu_stats":{"cpu_usage":{"total_usage":200},"system_cpu_usage":1000},"memory_stats":{"usage":1024},"networks":{"eth0":{"rx_bytes":30,"tx_bytes":40}}}`)
+
+	mockCli.
+		EXPECT().
+		ContainerStats(gomock.Any(), "test", true).
+		Return(types.ContainerStats{Body: io.NopCloser(&buf)}, nil)
+
+	cm := containerManager{
+		cli: mockCli,
+		log: mockLog,
+	}
+
+	usage, err := cm.SampleResourceUsage(context.Background(), "test")
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, usage.Samples, 2)
+	assert.Equal(t, usage.PeakCPUPercent, 100.0)
+	assert.Equal(t, usage.PeakMemoryBytes, uint64(2048))
+	assert.Equal(t, usage.NetworkRxBytes, uint64(30))
+	assert.Equal(t, usage.NetworkTxBytes, uint64(40))
+}
+
+func TestWhenContainerIsRunningWaitForContainerMustReturnNil(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	mockCli := mocks.NewMockClient(ctrl)
+	mockLog := mocks.NewMockLog(ctrl)
+
+	mockCli.
+		EXPECT().
+		ContainerInspect(gomock.Any(), "test").
+		Return(types.ContainerJSON{ContainerJSONBase: &types.ContainerJSONBase{State: &types.ContainerState{Running: true}}}, nil)
+
+	cm := containerManager{
+		cli: mockCli,
+		log: mockLog,
+	}
+
+	err := cm.WaitForContainer(context.Background(), "test", time.Second)
+
+	assert.Equal(t, err, nil)
+}
+
+func TestWhenContainerExitedWaitForContainerMustReturnError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	mockCli := mocks.NewMockClient(ctrl)
+	mockLog := mocks.NewMockLog(ctrl)
+
+	mockCli.
+		EXPECT().
+		ContainerInspect(gomock.Any(), "test").
+		Return(types.ContainerJSON{ContainerJSONBase: &types.ContainerJSONBase{State: &types.ContainerState{Status: "exited", ExitCode: 1}}}, nil)
+
+	cm := containerManager{
+		cli: mockCli,
+		log: mockLog,
+	}
+
+	err := cm.WaitForContainer(context.Background(), "test", time.Second)
+
+	assert.EqualError(t, err, "container exited with code 1")
+}
+
+func TestWhenContainerIsStartingWaitForContainerMustWaitUntilHealthy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	mockCli := mocks.NewMockClient(ctrl)
+	mockLog := mocks.NewMockLog(ctrl)
+
+	gomock.InOrder(
+		mockCli.
+			EXPECT().
+			ContainerInspect(gomock.Any(), "test").
+			Return(types.ContainerJSON{ContainerJSONBase: &types.ContainerJSONBase{State: &types.ContainerState{Running: true, Health: &types.Health{Status: types.Starting}}}}, nil),
+		mockCli.
+			EXPECT().
+			ContainerInspect(gomock.Any(), "test").
+			Return(types.ContainerJSON{ContainerJSONBase: &types.ContainerJSONBase{State: &types.ContainerState{Running: true, Health: &types.Health{Status: types.Healthy}}}}, nil),
+	)
+
+	cm := containerManager{
+		cli: mockCli,
+		log: mockLog,
+	}
+
+	err := cm.WaitForContainer(context.Background(), "test", 5*time.Second)
+
+	assert.Equal(t, err, nil)
+}
+
+func TestWhenContainerIsUnhealthyWaitForContainerMustReturnLastHealthcheckOutput(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	mockCli := mocks.NewMockClient(ctrl)
+	mockLog := mocks.NewMockLog(ctrl)
+
+	health := &types.Health{
+		Status: types.Unhealthy,
+		Log:    []*types.HealthcheckResult{{Output: "connection refused\n"}},
+	}
+
+	mockCli.
+		EXPECT().
+		ContainerInspect(gomock.Any(), "test").
+		Return(types.ContainerJSON{ContainerJSONBase: &types.ContainerJSONBase{State: &types.ContainerState{Running: true, Health: health}}}, nil)
+
+	cm := containerManager{
+		cli: mockCli,
+		log: mockLog,
+	}
+
+	err := cm.WaitForContainer(context.Background(), "test", time.Second)
+
+	assert.EqualError(t, err, "container is unhealthy: connection refused")
+}
+
+func TestPruneNetworksMustRemoveOnlyNetworksOlderThanRetention(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	mockCli := mocks.NewMockClient(ctrl)
+	mockLog := mocks.NewMockLog(ctrl)
+
+	mockCli.
+		EXPECT().
+		NetworkList(gomock.Any(), gomock.Any()).
+		Return([]types.NetworkResource{
+			{ID: "old", Name: "build_network_1", Created: time.Now().Add(-2 * time.Hour)},
+			{ID: "new", Name: "build_network_2", Created: time.Now()},
+		}, nil)
+
+	mockCli.
+		EXPECT().
+		NetworkRemove(gomock.Any(), "old").
+		Return(nil)
+
+	cm := containerManager{
+		cli: mockCli,
+		log: mockLog,
+	}
+
+	removed, err := cm.PruneNetworks(context.Background(), time.Hour, nil)
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, []string{"build_network_1"}, removed)
+}
+
+func TestPruneVolumesMustRemoveOnlyUnusedVolumesOlderThanRetention(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	mockCli := mocks.NewMockClient(ctrl)
+	mockLog := mocks.NewMockLog(ctrl)
+
+	old := time.Now().Add(-48 * time.Hour).Format(time.RFC3339)
+
+	mockCli.
+		EXPECT().
+		VolumeList(gomock.Any(), gomock.Any()).
+		Return(volumetypes.VolumeListOKBody{Volumes: []*types.Volume{
+			{Name: "pin_workspace_a_build", CreatedAt: old},
+			{Name: "pin_workspace_a_test", CreatedAt: old},
+			{Name: "pin_workspace_a_lint", CreatedAt: time.Now().Format(time.RFC3339)},
+			{Name: "other_pin_workspace_a", CreatedAt: old},
+		}}, nil)
+
+	mockCli.EXPECT().VolumeRemove(gomock.Any(), "pin_workspace_a_build", false).Return(nil)
+	mockCli.EXPECT().VolumeRemove(gomock.Any(), "pin_workspace_a_test", false).Return(errors.New("volume is in use"))
+	mockLog.EXPECT().Printf("Volume skipped: %s (%s)", "pin_workspace_a_test", gomock.Any())
+
+	cm := containerManager{
+		cli: mockCli,
+		log: mockLog,
+	}
+
+	removed, err := cm.PruneVolumes(context.Background(), "pin_workspace_", 24*time.Hour)
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, []string{"pin_workspace_a_build"}, removed)
+}
+
+func TestPruneContainersMustRemoveOnlyStoppedContainers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	mockCli := mocks.NewMockClient(ctrl)
+	mockLog := mocks.NewMockLog(ctrl)
+
+	mockCli.
+		EXPECT().
+		ContainerList(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, options types.ContainerListOptions) ([]types.Container, error) {
+			assert.True(t, options.All)
+			assert.ElementsMatch(t, []string{ManagedLabel, "pin.run_id=1"}, options.Filters.Get("label"))
+
+			return []types.Container{
+				{ID: "exited", Names: []string{"/build_1"}, State: "exited"},
+				{ID: "running", Names: []string{"/test_1"}, State: "running"},
+			}, nil
+		})
+
+	mockCli.
+		EXPECT().
+		ContainerRemove(gomock.Any(), "exited", types.ContainerRemoveOptions{Force: true}).
+		Return(nil)
+
+	cm := containerManager{
+		cli: mockCli,
+		log: mockLog,
+	}
+
+	removed, err := cm.PruneContainers(context.Background(), []string{"pin.run_id=1"})
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, []string{"build_1"}, removed)
+}
+
+func TestListResourcesMustListContainersNetworksAndPrefixedVolumes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	mockCli := mocks.NewMockClient(ctrl)
+	mockLog := mocks.NewMockLog(ctrl)
+
+	created := time.Date(2022, 5, 15, 10, 0, 0, 0, time.UTC)
+
+	mockCli.
+		EXPECT().
+		ContainerList(gomock.Any(), gomock.Any()).
+		Return([]types.Container{{ID: "c", Names: []string{"/build_1"}, State: "exited", Created: created.Unix()}}, nil)
+
+	mockCli.
+		EXPECT().
+		NetworkList(gomock.Any(), gomock.Any()).
+		Return([]types.NetworkResource{{ID: "n", Name: "build_network_1", Created: created}}, nil)
+
+	mockCli.
+		EXPECT().
+		VolumeList(gomock.Any(), gomock.Any()).
+		Return(volumetypes.VolumeListOKBody{Volumes: []*types.Volume{
+			{Name: "pin_workspace_a_build", CreatedAt: created.Format(time.RFC3339)},
+			{Name: "other_pin_workspace_a", CreatedAt: created.Format(time.RFC3339)},
+		}}, nil)
+
+	cm := containerManager{
+		cli: mockCli,
+		log: mockLog,
+	}
+
+	resources, err := cm.ListResources(context.Background(), "pin_workspace_")
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, []interfaces.Resource{
+		{Kind: interfaces.ResourceContainer, ID: "c", Name: "build_1", State: "exited", Created: time.Unix(created.Unix(), 0)},
+		{Kind: interfaces.ResourceNetwork, ID: "n", Name: "build_network_1", Created: created},
+		{Kind: interfaces.ResourceVolume, ID: "pin_workspace_a_build", Name: "pin_workspace_a_build", Created: created},
+	}, resources)
+}
+
+func TestStartContainerMustAddManagedLabel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	mockCli := mocks.NewMockClient(ctrl)
+	mockLog := mocks.NewMockLog(ctrl)
+
+	mockLog.
+		EXPECT().
+		Println("Start creating container")
+
+	mockCli.
+		EXPECT().
+		ContainerCreate(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, config *container.Config, _, _, _, _ interface{}) (container.ContainerCreateCreatedBody, error) {
+			assert.Equal(t, map[string]string{ManagedLabel: "true", "pin.job": "build"}, config.Labels)
+
+			return container.ContainerCreateCreatedBody{}, nil
+		})
+
+	cm := containerManager{
+		cli: mockCli,
+		log: mockLog,
+	}
+
+	_, err := cm.StartContainer(context.Background(), interfaces.ContainerOptions{Labels: map[string]string{"pin.job": "build"}})
+
+	assert.Equal(t, err, nil)
+}
+
+func TestCopyFromContainerMustExtractMatchingFilesPreservingStructure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	mockCli := mocks.NewMockClient(ctrl)
+	mockLog := mocks.NewMockLog(ctrl)
+
+	var buf bytes.Buffer
+
+	tw := tar.NewWriter(&buf)
+
+	for _, name := range []string{"dist/app.js", "dist/lib/vendor.js", "dist/style.css"} {
+		tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: 4, Typeflag: tar.TypeReg})
+		tw.Write([]byte("test"))
+	}
+
+	tw.Close()
+
+	mockCli.
+		EXPECT().
+		CopyFromContainer(gomock.Any(), "test", "/root/dist").
+		Return(io.NopCloser(&buf), types.ContainerPathStat{}, nil)
+
+	cm := containerManager{
+		cli: mockCli,
+		log: mockLog,
+	}
+
+	destination := t.TempDir()
+
+	files, err := cm.CopyFromContainer(context.Background(), "test", "/root", []string{"dist/**/*.js"}, destination)
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, []string{"dist/app.js", "dist/lib/vendor.js"}, files)
+	assert.FileExists(t, filepath.Join(destination, "dist", "lib", "vendor.js"))
+	assert.NoFileExists(t, filepath.Join(destination, "dist", "style.css"))
+}
+
+func TestCopyToContainerWithGzipMustSendCompressedArchive(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	mockCli := mocks.NewMockClient(ctrl)
+	mockLog := mocks.NewMockLog(ctrl)
+
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main"), 0644)
+
+	wd, _ := os.Getwd()
+	os.Chdir(dir)
+	defer os.Chdir(wd)
+
+	names := []string{}
+
+	mockLog.EXPECT().Printf(gomock.Any(), gomock.Any()).AnyTimes()
+	mockLog.EXPECT().Println(gomock.Any()).AnyTimes()
+
+	mockCli.
+		EXPECT().
+		CopyToContainer(gomock.Any(), "test", "/root", gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, containerID, dstPath string, content io.Reader, options types.CopyToContainerOptions) error {
+			gr, err := gzip.NewReader(content)
+
+			if err != nil {
+				return err
+			}
+
+			tr := tar.NewReader(gr)
+
+			for {
+				header, err := tr.Next()
+
+				if err != nil {
+					break
+				}
+
+				names = append(names, header.Name)
+			}
+
+			return nil
+		})
+
+	cm := containerManager{
+		cli: mockCli,
+		log: mockLog,
+	}
+
+	err := cm.CopyToContainer(context.Background(), "test", "/root", []string{}, nil, interfaces.CompressionGzip)
+
+	assert.Equal(t, err, nil)
+	assert.Contains(t, names, "main.go")
+}
+
+func TestWhenDockerFailsCopyToContainerMustReturnItsErrorWithoutBlocking(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	mockCli := mocks.NewMockClient(ctrl)
+	mockLog := mocks.NewMockLog(ctrl)
+
+	dir := t.TempDir()
+
+	for i := 0; i < 100; i++ {
+		os.WriteFile(filepath.Join(dir, fmt.Sprintf("file%d.txt", i)), bytes.Repeat([]byte("pin"), 1024), 0644)
+	}
+
+	wd, _ := os.Getwd()
+	os.Chdir(dir)
+	defer os.Chdir(wd)
+
+	mockCli.
+		EXPECT().
+		CopyToContainer(gomock.Any(), "test", "/root", gomock.Any(), gomock.Any()).
+		Return(errors.New("no such container"))
+
+	cm := containerManager{
+		cli: mockCli,
+		log: mockLog,
+	}
+
+	err := cm.CopyToContainer(context.Background(), "test", "/root", []string{}, nil, interfaces.CompressionNone)
+
+	assert.EqualError(t, err, "no such container")
+}
+
+func TestCopyToContainerMustLogCopiedFilesAndBytes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	mockCli := mocks.NewMockClient(ctrl)
+	mockLog := mocks.NewMockLog(ctrl)
+
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0644)
+	os.WriteFile(filepath.Join(dir, "b.txt"), []byte("b"), 0644)
+
+	wd, _ := os.Getwd()
+	os.Chdir(dir)
+	defer os.Chdir(wd)
+
+	mockCli.
+		EXPECT().
+		CopyToContainer(gomock.Any(), "test", "/root", gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, containerID, dstPath string, content io.Reader, options types.CopyToContainerOptions) error {
+			_, err := io.Copy(io.Discard, content)
+			return err
+		})
+
+	mockLog.
+		EXPECT().
+		Printf("Project files copied: %d files, %d bytes", 2, int64(3072))
+
+	cm := containerManager{
+		cli: mockCli,
+		log: mockLog,
+	}
+
+	err := cm.CopyToContainer(context.Background(), "test", "/root", []string{}, nil, interfaces.CompressionNone)
+
+	assert.Equal(t, err, nil)
+}
+
+func TestCopyToContainerMustStartOverWhenTheStreamBreaksOff(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	defer func(d time.Duration) { copyRetryDelay = d }(copyRetryDelay)
+
+	copyRetryDelay = 0
+
+	mockCli := mocks.NewMockClient(ctrl)
+	mockLog := mocks.NewMockLog(ctrl)
+
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0644)
+
+	wd, _ := os.Getwd()
+	os.Chdir(dir)
+	defer os.Chdir(wd)
+
+	gomock.InOrder(
+		mockCli.
+			EXPECT().
+			CopyToContainer(gomock.Any(), "test", "/root", gomock.Any(), gomock.Any()).
+			Return(io.ErrUnexpectedEOF),
+		mockCli.
+			EXPECT().
+			CopyToContainer(gomock.Any(), "test", "/root", gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, containerID, dstPath string, content io.Reader, options types.CopyToContainerOptions) error {
+				_, err := io.Copy(io.Discard, content)
+				return err
+			}),
+	)
+
+	mockLog.
+		EXPECT().
+		Printf("%s broke off: %s, retrying (attempt %d of %d)", "copying project files to /root", io.ErrUnexpectedEOF, 2, copyAttempts)
+	mockLog.
+		EXPECT().
+		Printf("Project files copied: %d files, %d bytes", 1, gomock.Any())
+
+	cm := containerManager{
+		cli: mockCli,
+		log: mockLog,
+	}
+
+	err := cm.CopyToContainer(context.Background(), "test", "/root", []string{}, nil, interfaces.CompressionNone)
+
+	assert.Equal(t, err, nil)
+}
+
+func TestCopyMustReturnCopyFailedAfterTheLastAttempt(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	defer func(d time.Duration) { copyRetryDelay = d }(copyRetryDelay)
+
+	copyRetryDelay = 0
+
+	mockCli := mocks.NewMockClient(ctrl)
+	mockLog := mocks.NewMockLog(ctrl)
+
+	var buf bytes.Buffer
+
+	tw := tar.NewWriter(&buf)
+	tw.WriteHeader(&tar.Header{Name: "dist/app.js", Mode: 0644, Size: 100, Typeflag: tar.TypeReg})
+	tw.Write([]byte("truncated"))
+
+	archive := buf.Bytes()
+
+	mockCli.
+		EXPECT().
+		CopyFromContainer(gomock.Any(), "test", "/root/dist").
+		DoAndReturn(func(ctx context.Context, containerID, srcPath string) (io.ReadCloser, types.ContainerPathStat, error) {
+			return io.NopCloser(bytes.NewReader(archive)), types.ContainerPathStat{}, nil
+		}).
+		Times(copyAttempts)
+
+	mockLog.EXPECT().Printf(gomock.Any(), gomock.Any()).Times(copyAttempts - 1)
+
+	cm := containerManager{
+		cli: mockCli,
+		log: mockLog,
+	}
+
+	_, err := cm.CopyFromContainer(context.Background(), "test", "/root", []string{"dist/*.js"}, t.TempDir())
+
+	var pinErr *pin_error.PinError
+
+	assert.True(t, errors.As(err, &pinErr))
+	assert.Equal(t, pin_error.CodeCopyFailed, pinErr.Code)
+	assert.Equal(t, "CopyFromContainer", pinErr.Operation)
+	assert.Contains(t, err.Error(), "copying artifacts of dist/*.js failed after 3 attempts")
+}
+
+func TestCopyFromContainerMustCompareASingleFileWithItsStat(t *testing.T) {
+	destination := t.TempDir()
+	os.WriteFile(filepath.Join(destination, "app.js"), []byte("test"), 0644)
+
+	assert.NoError(t, verifyExtraction(types.ContainerPathStat{Name: "app.js", Size: 4}, []string{"app.js"}, destination))
+	assert.ErrorIs(t, verifyExtraction(types.ContainerPathStat{Name: "app.js", Size: 8}, []string{"app.js"}, destination), io.ErrUnexpectedEOF)
+	assert.NoError(t, verifyExtraction(types.ContainerPathStat{Name: "dist", Mode: os.ModeDir}, []string{"app.js"}, destination))
+}
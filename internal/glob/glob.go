@@ -0,0 +1,97 @@
+package glob
+
+import (
+	"regexp"
+	"strings"
+)
+
+// toRegexp converts a slash separated glob to a regular expression,
+// "**" matches any number of directories and "*" stays in one segment.
+func toRegexp(pattern string) (*regexp.Regexp, error) {
+	var sb strings.Builder
+
+	sb.WriteString("^")
+
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+
+		switch c {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				if i+2 < len(pattern) && pattern[i+2] == '/' {
+					sb.WriteString("(.*/)?")
+					i += 2
+				} else {
+					sb.WriteString(".*")
+					i++
+				}
+			} else {
+				sb.WriteString("[^/]*")
+			}
+		case '?':
+			sb.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(pattern[i:], ']')
+
+			if end < 0 {
+				sb.WriteString(`\[`)
+				continue
+			}
+
+			sb.WriteString(pattern[i : i+end+1])
+			i += end
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	sb.WriteString("$")
+
+	return regexp.Compile(sb.String())
+}
+
+// Match reports whether name matches the glob pattern, a pattern
+// without wildcards also matches everything below it.
+func Match(pattern, name string) bool {
+	if !HasMeta(pattern) {
+		return name == pattern || strings.HasPrefix(name, strings.TrimSuffix(pattern, "/")+"/")
+	}
+
+	re, err := toRegexp(pattern)
+
+	if err != nil {
+		return false
+	}
+
+	return re.MatchString(name)
+}
+
+// HasMeta reports whether the pattern contains any wildcard.
+func HasMeta(pattern string) bool {
+	return strings.ContainsAny(pattern, "*?[")
+}
+
+// Root returns the longest leading directory of the pattern without
+// wildcards.
+func Root(pattern string) string {
+	if !HasMeta(pattern) {
+		return pattern
+	}
+
+	segments := strings.Split(pattern, "/")
+	root := []string{}
+
+	for _, segment := range segments {
+		if HasMeta(segment) {
+			break
+		}
+
+		root = append(root, segment)
+	}
+
+	if len(root) == 1 && root[0] == "" {
+		return "/"
+	}
+
+	return strings.Join(root, "/")
+}
@@ -0,0 +1,39 @@
+package glob
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type matchTestCase struct {
+	pattern string
+	name    string
+	result  bool
+}
+
+func TestMatch(t *testing.T) {
+	testCases := []matchTestCase{
+		{pattern: "dist/**/*.js", name: "dist/app.js", result: true},
+		{pattern: "dist/**/*.js", name: "dist/lib/vendor/app.js", result: true},
+		{pattern: "dist/**/*.js", name: "dist/app.css", result: false},
+		{pattern: "dist/*.js", name: "dist/lib/app.js", result: false},
+		{pattern: "build/app.bin", name: "build/app.bin", result: true},
+		{pattern: "build", name: "build/app.bin", result: true},
+		{pattern: "build", name: "builder/app.bin", result: false},
+		{pattern: "report-?.xml", name: "report-1.xml", result: true},
+		{pattern: "report-[0-9].xml", name: "report-a.xml", result: false},
+		{pattern: "**", name: "any/thing", result: true},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.result, Match(testCase.pattern, testCase.name), testCase.pattern+" "+testCase.name)
+	}
+}
+
+func TestRoot(t *testing.T) {
+	assert.Equal(t, "/root/dist", Root("/root/dist/**/*.js"))
+	assert.Equal(t, "/root/build/app.bin", Root("/root/build/app.bin"))
+	assert.Equal(t, "/", Root("/*.log"))
+	assert.Equal(t, "", Root("*.log"))
+}
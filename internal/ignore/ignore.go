@@ -0,0 +1,60 @@
+package ignore
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/muhammedikinci/pin/internal/glob"
+)
+
+// Ignored reports whether a slash separated path relative to the project is
+// excluded by copyIgnore. Patterns are regular expressions checked in order
+// and the last one that matches decides, a pattern starting with "!"
+// includes the path again. An invalid pattern excludes everything it is
+// compared with.
+func Ignored(name string, patterns []string) bool {
+	ignored := false
+
+	for _, pattern := range patterns {
+		negated := strings.HasPrefix(pattern, "!")
+
+		if negated {
+			pattern = pattern[1:]
+		}
+
+		if matched, err := regexp.MatchString(pattern, name); err != nil || matched {
+			ignored = !negated
+		}
+	}
+
+	return ignored
+}
+
+// Included reports whether a path is selected by the copyInclude globs,
+// every path is when there are none. A directory is included when paths
+// below it can match, so the walk can skip the others.
+func Included(name string, isDir bool, patterns []string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+
+	for _, pattern := range patterns {
+		pattern = strings.TrimPrefix(pattern, "./")
+
+		if glob.Match(pattern, name) {
+			return true
+		}
+
+		if !isDir {
+			continue
+		}
+
+		root := glob.Root(pattern)
+
+		if root == "" || root == name || strings.HasPrefix(root, name+"/") {
+			return true
+		}
+	}
+
+	return false
+}
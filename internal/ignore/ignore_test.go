@@ -0,0 +1,57 @@
+package ignore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type ignoredTestCase struct {
+	name     string
+	patterns []string
+	result   bool
+}
+
+func TestIgnored(t *testing.T) {
+	testCases := []ignoredTestCase{
+		{name: "node_modules", patterns: []string{"node_modules"}, result: true},
+		{name: "src/main.go", patterns: []string{"node_modules"}, result: false},
+		{name: "debug.log", patterns: []string{`\.log$`}, result: true},
+		{name: "keep.log", patterns: []string{`\.log$`, `!^keep\.log$`}, result: false},
+		{name: "keep.log", patterns: []string{`!^keep\.log$`, `\.log$`}, result: true},
+		{name: "docs/README.md", patterns: []string{"^docs/", "!README.md"}, result: false},
+		{name: "docs/guide.md", patterns: []string{"^docs/", "!README.md"}, result: true},
+		{name: "main.go", patterns: []string{"("}, result: true},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.result, Ignored(testCase.name, testCase.patterns), testCase.name, testCase.patterns)
+	}
+}
+
+type includedTestCase struct {
+	name     string
+	isDir    bool
+	patterns []string
+	result   bool
+}
+
+func TestIncluded(t *testing.T) {
+	testCases := []includedTestCase{
+		{name: "main.go", patterns: nil, result: true},
+		{name: "services", isDir: true, patterns: []string{"services/api/**"}, result: true},
+		{name: "services/api", isDir: true, patterns: []string{"services/api/**"}, result: true},
+		{name: "services/api/main.go", patterns: []string{"services/api/**"}, result: true},
+		{name: "services/web", isDir: true, patterns: []string{"services/api/**"}, result: false},
+		{name: "services/web/main.go", patterns: []string{"services/api/**"}, result: false},
+		{name: "go.mod", patterns: []string{"services/api/**", "./go.mod"}, result: true},
+		{name: "libs", isDir: true, patterns: []string{"**/*.go"}, result: true},
+		{name: "libs/README.md", patterns: []string{"**/*.go"}, result: false},
+		{name: "libs/log", isDir: true, patterns: []string{"libs/log"}, result: true},
+		{name: "libs/log/log.go", patterns: []string{"libs/log"}, result: true},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.result, Included(testCase.name, testCase.isDir, testCase.patterns), testCase.name, testCase.patterns)
+	}
+}
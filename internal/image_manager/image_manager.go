@@ -1,16 +1,23 @@
 package image_manager
 
 import (
+	"archive/tar"
 	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
 	"github.com/fatih/color"
+	"github.com/muhammedikinci/pin/internal/glob"
 	"github.com/muhammedikinci/pin/internal/interfaces"
+	"github.com/muhammedikinci/pin/internal/log_level"
 )
 
 func NewImageManager(cli interfaces.Client, log interfaces.Log) imageManager {
@@ -84,8 +91,220 @@ func (im imageManager) PullImage(ctx context.Context, image string) error {
 			return err
 		}
 
-		fmt.Printf("\033[A\033[K%s %s\n", res.Status, res.Progress)
+		if !log_level.IsQuiet() {
+			fmt.Print(pullProgressLine(res))
+		}
 	}
 
 	return nil
 }
+
+// pullProgressLine redraws the previous line with the pull progress, without
+// colors the output is not a terminal so only the status changes are printed
+// and no cursor movements.
+func pullProgressLine(res imagePullingResult) string {
+	if color.NoColor {
+		if res.Progress != "" {
+			return ""
+		}
+
+		return res.Status + "\n"
+	}
+
+	return fmt.Sprintf("\033[A\033[K%s %s\n", res.Status, res.Progress)
+}
+
+// ImageDigest returns the repo digest of the local image, or its ID for
+// images that were built locally and never pushed.
+func (im imageManager) ImageDigest(ctx context.Context, image string) (string, error) {
+	inspect, _, err := im.cli.ImageInspectWithRaw(ctx, image)
+
+	if err != nil {
+		return "", err
+	}
+
+	if len(inspect.RepoDigests) > 0 {
+		return inspect.RepoDigests[0], nil
+	}
+
+	return inspect.ID, nil
+}
+
+type imageBuildResult struct {
+	Stream string `json:"stream"`
+	Error  string `json:"error"`
+}
+
+func (im imageManager) BuildImage(ctx context.Context, options interfaces.BuildOptions) error {
+	color.Set(color.FgBlue)
+	im.log.Printf("Image building: %s from %s", options.Tag, options.Dockerfile)
+	color.Unset()
+
+	buildContext, err := buildContextArchive(options.ContextDir)
+
+	if err != nil {
+		return err
+	}
+
+	res, err := im.cli.ImageBuild(ctx, buildContext, types.ImageBuildOptions{
+		Dockerfile: filepath.ToSlash(options.Dockerfile),
+		Tags:       []string{options.Tag},
+		BuildArgs:  options.BuildArgs,
+		Target:     options.Target,
+		Labels:     options.Labels,
+		Remove:     true,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+
+	decoder := json.NewDecoder(res.Body)
+
+	for {
+		result := imageBuildResult{}
+
+		if err := decoder.Decode(&result); err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		if result.Error != "" {
+			return fmt.Errorf("image build failed: %s", result.Error)
+		}
+
+		if line := strings.TrimRight(result.Stream, "\n"); line != "" {
+			im.log.Println(line)
+		}
+	}
+
+	color.Set(color.FgGreen)
+	im.log.Printf("Image built: %s", options.Tag)
+	color.Unset()
+
+	return nil
+}
+
+// PruneImages removes the images that have all the given labels, images used
+// by containers are skipped.
+func (im imageManager) PruneImages(ctx context.Context, labels []string) ([]string, error) {
+	args := filters.NewArgs()
+
+	for _, label := range labels {
+		args.Add("label", label)
+	}
+
+	images, err := im.cli.ImageList(ctx, types.ImageListOptions{Filters: args})
+
+	if err != nil {
+		return []string{}, err
+	}
+
+	removed := []string{}
+
+	for _, image := range images {
+		if _, err := im.cli.ImageRemove(ctx, image.ID, types.ImageRemoveOptions{PruneChildren: true}); err != nil {
+			im.log.Printf("Image skipped: %s (%s)", image.ID, err)
+			continue
+		}
+
+		name := image.ID
+
+		if len(image.RepoTags) > 0 {
+			name = image.RepoTags[0]
+		}
+
+		removed = append(removed, name)
+	}
+
+	return removed, nil
+}
+
+// buildContextArchive tars the build context, skipping .git and the paths
+// matched by .dockerignore.
+func buildContextArchive(dir string) (*bytes.Buffer, error) {
+	ignore := []string{".git"}
+
+	if content, err := os.ReadFile(filepath.Join(dir, ".dockerignore")); err == nil {
+		for _, line := range strings.Split(string(content), "\n") {
+			line = strings.TrimSpace(line)
+
+			if line != "" && !strings.HasPrefix(line, "#") {
+				ignore = append(ignore, strings.TrimPrefix(line, "/"))
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+
+	tw := tar.NewWriter(&buf)
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, path)
+
+		if err != nil || rel == "." {
+			return err
+		}
+
+		name := filepath.ToSlash(rel)
+
+		for _, pattern := range ignore {
+			if glob.Match(pattern, name) {
+				if info.IsDir() {
+					return filepath.SkipDir
+				}
+
+				return nil
+			}
+		}
+
+		if !info.Mode().IsRegular() && !info.IsDir() {
+			return nil
+		}
+
+		header, err := tar.FileInfoHeader(info, "")
+
+		if err != nil {
+			return err
+		}
+
+		header.Name = name
+
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			return nil
+		}
+
+		f, err := os.Open(path)
+
+		if err != nil {
+			return err
+		}
+
+		defer f.Close()
+
+		_, err = io.Copy(tw, f)
+
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+
+	return &buf, nil
+}
@@ -1,17 +1,22 @@
 package image_manager
 
 import (
+	"archive/tar"
 	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/docker/docker/api/types"
+	"github.com/fatih/color"
 	"github.com/golang/mock/gomock"
+	"github.com/muhammedikinci/pin/internal/interfaces"
 	"github.com/muhammedikinci/pin/internal/mocks"
 	"github.com/stretchr/testify/assert"
 )
@@ -217,3 +222,168 @@ func TestWhenClientImagePullFunctionReturnSuccessfulStreamPullImageMustReturnNil
 
 	assert.Equal(t, err, nil)
 }
+
+func TestImageDigestMustReturnRepoDigestOrImageID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	mockCli := mocks.NewMockClient(ctrl)
+	mockLog := mocks.NewMockLog(ctrl)
+
+	mockCli.
+		EXPECT().
+		ImageInspectWithRaw(gomock.Any(), "pulled").
+		Return(types.ImageInspect{ID: "sha256:1", RepoDigests: []string{"golang@sha256:2"}}, []byte{}, nil)
+
+	mockCli.
+		EXPECT().
+		ImageInspectWithRaw(gomock.Any(), "built").
+		Return(types.ImageInspect{ID: "sha256:3"}, []byte{}, nil)
+
+	im := imageManager{
+		cli: mockCli,
+		log: mockLog,
+	}
+
+	digest, err := im.ImageDigest(context.Background(), "pulled")
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, "golang@sha256:2", digest)
+
+	digest, err = im.ImageDigest(context.Background(), "built")
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, "sha256:3", digest)
+}
+
+func TestPruneImagesMustSkipImagesThatCanNotBeRemoved(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	mockCli := mocks.NewMockClient(ctrl)
+	mockLog := mocks.NewMockLog(ctrl)
+
+	mockCli.
+		EXPECT().
+		ImageList(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, options types.ImageListOptions) ([]types.ImageSummary, error) {
+			assert.Equal(t, []string{"pin.managed"}, options.Filters.Get("label"))
+
+			return []types.ImageSummary{
+				{ID: "sha256:1", RepoTags: []string{"build-custom:latest"}},
+				{ID: "sha256:2"},
+			}, nil
+		})
+
+	mockCli.
+		EXPECT().
+		ImageRemove(gomock.Any(), "sha256:1", gomock.Any()).
+		Return([]types.ImageDeleteResponseItem{}, nil)
+
+	mockCli.
+		EXPECT().
+		ImageRemove(gomock.Any(), "sha256:2", gomock.Any()).
+		Return(nil, errors.New("image is being used by a running container"))
+
+	mockLog.
+		EXPECT().
+		Printf("Image skipped: %s (%s)", "sha256:2", gomock.Any())
+
+	im := imageManager{
+		cli: mockCli,
+		log: mockLog,
+	}
+
+	removed, err := im.PruneImages(context.Background(), []string{"pin.managed"})
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, []string{"build-custom:latest"}, removed)
+}
+
+func TestWhenBuildStreamReturnsErrorBuildImageMustReturnIt(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	mockCli := mocks.NewMockClient(ctrl)
+	mockLog := mocks.NewMockLog(ctrl)
+
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "Dockerfile"), []byte("FROM alpine:3.15"), 0644)
+
+	version := "1.2.3"
+	stream := `{"stream":"Step 1/1 : FROM alpine:3.15\n"}` + "\n" + `{"error":"pull access denied"}` + "\n"
+
+	mockLog.EXPECT().Printf(gomock.Any(), gomock.Any()).AnyTimes()
+	mockLog.EXPECT().Println("Step 1/1 : FROM alpine:3.15")
+
+	mockCli.
+		EXPECT().
+		ImageBuild(gomock.Any(), gomock.Any(), types.ImageBuildOptions{
+			Dockerfile: "Dockerfile",
+			Tags:       []string{"build-custom:latest"},
+			BuildArgs:  map[string]*string{"VERSION": &version},
+			Remove:     true,
+		}).
+		Return(types.ImageBuildResponse{Body: io.NopCloser(strings.NewReader(stream))}, nil)
+
+	im := imageManager{
+		cli: mockCli,
+		log: mockLog,
+	}
+
+	err := im.BuildImage(context.Background(), interfaces.BuildOptions{
+		ContextDir: dir,
+		Dockerfile: "Dockerfile",
+		Tag:        "build-custom:latest",
+		BuildArgs:  map[string]*string{"VERSION": &version},
+	})
+
+	assert.EqualError(t, err, "image build failed: pull access denied")
+}
+
+func TestBuildContextArchiveMustSkipDockerignoredFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	os.MkdirAll(filepath.Join(dir, "node_modules", "lib"), 0755)
+	os.MkdirAll(filepath.Join(dir, ".git"), 0755)
+	os.WriteFile(filepath.Join(dir, "Dockerfile"), []byte("FROM alpine:3.15"), 0644)
+	os.WriteFile(filepath.Join(dir, "debug.log"), []byte("log"), 0644)
+	os.WriteFile(filepath.Join(dir, ".dockerignore"), []byte("# deps\nnode_modules\n*.log\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "node_modules", "lib", "index.js"), []byte("js"), 0644)
+	os.WriteFile(filepath.Join(dir, ".git", "HEAD"), []byte("ref"), 0644)
+
+	buf, err := buildContextArchive(dir)
+
+	assert.Equal(t, err, nil)
+
+	names := []string{}
+	tr := tar.NewReader(buf)
+
+	for {
+		header, err := tr.Next()
+
+		if err != nil {
+			break
+		}
+
+		names = append(names, header.Name)
+	}
+
+	assert.ElementsMatch(t, []string{".dockerignore", "Dockerfile"}, names)
+}
+
+func TestPullProgressLineMustNotMoveTheCursorWithoutColors(t *testing.T) {
+	defer func(v bool) { color.NoColor = v }(color.NoColor)
+
+	color.NoColor = false
+
+	assert.Equal(t, "\033[A\033[KDownloading [==>  ] 1MB/4MB\n", pullProgressLine(imagePullingResult{Status: "Downloading", Progress: "[==>  ] 1MB/4MB"}))
+
+	color.NoColor = true
+
+	assert.Equal(t, "", pullProgressLine(imagePullingResult{Status: "Downloading", Progress: "[==>  ] 1MB/4MB"}))
+	assert.Equal(t, "Pull complete\n", pullProgressLine(imagePullingResult{Status: "Pull complete"}))
+}
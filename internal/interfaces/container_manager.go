@@ -2,14 +2,75 @@ package interfaces
 
 import (
 	"context"
+	"time"
 
 	"github.com/docker/docker/api/types/container"
 )
 
 //go:generate mockgen -source $GOFILE -destination ../mocks/mock_$GOFILE -package mocks
 type ContainerManager interface {
-	StartContainer(ctx context.Context, jobName string, image string, ports map[string]string) (container.ContainerCreateCreatedBody, error)
-	StopContainer(ctx context.Context, containerID string) error
+	StartContainer(ctx context.Context, options ContainerOptions) (container.ContainerCreateCreatedBody, error)
+	StopContainer(ctx context.Context, containerID string, timeout *time.Duration) error
 	RemoveContainer(ctx context.Context, containerID string, forceRemove bool) error
-	CopyToContainer(ctx context.Context, containerID, workDir string, copyIgnore []string) error
+	CopyToContainer(ctx context.Context, containerID, workDir string, copyIgnore, copyInclude []string, compression string) error
+	CopyFromContainer(ctx context.Context, containerID, workDir string, patterns []string, destination string) ([]string, error)
+	SampleResourceUsage(ctx context.Context, containerID string) (ResourceUsage, error)
+	CreateNetwork(ctx context.Context, name string, labels map[string]string) (string, error)
+	RemoveNetwork(ctx context.Context, networkID string) error
+	WaitForContainer(ctx context.Context, containerID string, timeout time.Duration) error
+	PruneNetworks(ctx context.Context, olderThan time.Duration, labels []string) ([]string, error)
+	PruneContainers(ctx context.Context, labels []string) ([]string, error)
+	PruneVolumes(ctx context.Context, prefix string, olderThan time.Duration) ([]string, error)
+	ListResources(ctx context.Context, volumePrefix string) ([]Resource, error)
+	RemoveResource(ctx context.Context, resource Resource) error
+}
+
+// Kinds of the docker resources pin leaves behind.
+const (
+	ResourceContainer = "container"
+	ResourceNetwork   = "network"
+	ResourceVolume    = "volume"
+)
+
+type Resource struct {
+	Kind    string
+	ID      string
+	Name    string
+	State   string
+	Labels  map[string]string
+	Created time.Time
+}
+
+// Compression algorithms for copying files into containers, the docker api
+// accepts gzip but not zstd archives.
+const (
+	CompressionNone = "none"
+	CompressionGzip = "gzip"
+)
+
+type ContainerOptions struct {
+	Name           string
+	Image          string
+	Ports          map[string]string
+	Env            []string
+	Volumes        []string
+	Privileged     bool
+	CapAdd         []string
+	CapDrop        []string
+	ExtraHosts     []string
+	DNS            []string
+	DNSSearch      []string
+	Hostname       string
+	Network        string
+	NetworkAliases []string
+	Labels         map[string]string
+	Healthcheck    *container.HealthConfig
+}
+
+type ResourceUsage struct {
+	PeakCPUPercent  float64
+	PeakMemoryBytes uint64
+	NetworkRxBytes  uint64
+	NetworkTxBytes  uint64
+	Samples         int
 }
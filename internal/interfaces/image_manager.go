@@ -6,4 +6,21 @@ import "context"
 type ImageManager interface {
 	CheckTheImageAvailable(ctx context.Context, image string) (bool, error)
 	PullImage(ctx context.Context, image string) error
+	ImageDigest(ctx context.Context, image string) (string, error)
+	BuildImage(ctx context.Context, options BuildOptions) error
+	PruneImages(ctx context.Context, labels []string) ([]string, error)
+}
+
+type BuildOptions struct {
+	// ContextDir is sent to docker as the build context, .dockerignore in it
+	// is honoured.
+	ContextDir string
+	// Dockerfile is relative to ContextDir.
+	Dockerfile string
+	Tag        string
+	BuildArgs  map[string]*string
+	// Target is the stage of a multi-stage Dockerfile to build, the last
+	// stage when empty.
+	Target string
+	Labels map[string]string
 }
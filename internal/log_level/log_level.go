@@ -0,0 +1,58 @@
+// Package log_level holds the verbosity of the output, it is set once from
+// the command line before anything runs.
+package log_level
+
+import "fmt"
+
+type Level int
+
+const (
+	// Quiet only shows failures and summaries.
+	Quiet Level = iota
+	// Info is the default output.
+	Info
+	// Verbose adds docker api timings and copy details.
+	Verbose
+)
+
+var current = Info
+
+func Set(level Level) {
+	current = level
+}
+
+func Get() Level {
+	return current
+}
+
+func IsQuiet() bool {
+	return current == Quiet
+}
+
+func IsVerbose() bool {
+	return current == Verbose
+}
+
+func Parse(level string) (Level, error) {
+	switch level {
+	case "quiet":
+		return Quiet, nil
+	case "info":
+		return Info, nil
+	case "verbose":
+		return Verbose, nil
+	}
+
+	return Info, fmt.Errorf("invalid log level: %s, use quiet, info or verbose", level)
+}
+
+func (l Level) String() string {
+	switch l {
+	case Quiet:
+		return "quiet"
+	case Verbose:
+		return "verbose"
+	}
+
+	return "info"
+}
@@ -0,0 +1,30 @@
+package log_level
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	for _, level := range []Level{Quiet, Info, Verbose} {
+		parsed, err := Parse(level.String())
+
+		assert.Equal(t, nil, err)
+		assert.Equal(t, level, parsed)
+	}
+
+	_, err := Parse("debug")
+
+	assert.EqualError(t, err, "invalid log level: debug, use quiet, info or verbose")
+}
+
+func TestSet(t *testing.T) {
+	defer Set(Info)
+
+	Set(Verbose)
+
+	assert.True(t, IsVerbose())
+	assert.False(t, IsQuiet())
+	assert.Equal(t, Verbose, Get())
+}
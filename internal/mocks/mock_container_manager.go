@@ -7,9 +7,11 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	container "github.com/docker/docker/api/types/container"
 	gomock "github.com/golang/mock/gomock"
+	interfaces "github.com/muhammedikinci/pin/internal/interfaces"
 )
 
 // MockContainerManager is a mock of ContainerManager interface.
@@ -35,18 +37,108 @@ func (m *MockContainerManager) EXPECT() *MockContainerManagerMockRecorder {
 	return m.recorder
 }
 
+// CopyFromContainer mocks base method.
+func (m *MockContainerManager) CopyFromContainer(ctx context.Context, containerID, workDir string, patterns []string, destination string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CopyFromContainer", ctx, containerID, workDir, patterns, destination)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CopyFromContainer indicates an expected call of CopyFromContainer.
+func (mr *MockContainerManagerMockRecorder) CopyFromContainer(ctx, containerID, workDir, patterns, destination interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyFromContainer", reflect.TypeOf((*MockContainerManager)(nil).CopyFromContainer), ctx, containerID, workDir, patterns, destination)
+}
+
 // CopyToContainer mocks base method.
-func (m *MockContainerManager) CopyToContainer(ctx context.Context, containerID, workDir string, copyIgnore []string) error {
+func (m *MockContainerManager) CopyToContainer(ctx context.Context, containerID, workDir string, copyIgnore, copyInclude []string, compression string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CopyToContainer", ctx, containerID, workDir, copyIgnore)
+	ret := m.ctrl.Call(m, "CopyToContainer", ctx, containerID, workDir, copyIgnore, copyInclude, compression)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // CopyToContainer indicates an expected call of CopyToContainer.
-func (mr *MockContainerManagerMockRecorder) CopyToContainer(ctx, containerID, workDir, copyIgnore interface{}) *gomock.Call {
+func (mr *MockContainerManagerMockRecorder) CopyToContainer(ctx, containerID, workDir, copyIgnore, copyInclude, compression interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyToContainer", reflect.TypeOf((*MockContainerManager)(nil).CopyToContainer), ctx, containerID, workDir, copyIgnore, copyInclude, compression)
+}
+
+// CreateNetwork mocks base method.
+func (m *MockContainerManager) CreateNetwork(ctx context.Context, name string, labels map[string]string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateNetwork", ctx, name, labels)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateNetwork indicates an expected call of CreateNetwork.
+func (mr *MockContainerManagerMockRecorder) CreateNetwork(ctx, name, labels interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNetwork", reflect.TypeOf((*MockContainerManager)(nil).CreateNetwork), ctx, name, labels)
+}
+
+// ListResources mocks base method.
+func (m *MockContainerManager) ListResources(ctx context.Context, volumePrefix string) ([]interfaces.Resource, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListResources", ctx, volumePrefix)
+	ret0, _ := ret[0].([]interfaces.Resource)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListResources indicates an expected call of ListResources.
+func (mr *MockContainerManagerMockRecorder) ListResources(ctx, volumePrefix interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListResources", reflect.TypeOf((*MockContainerManager)(nil).ListResources), ctx, volumePrefix)
+}
+
+// PruneContainers mocks base method.
+func (m *MockContainerManager) PruneContainers(ctx context.Context, labels []string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PruneContainers", ctx, labels)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PruneContainers indicates an expected call of PruneContainers.
+func (mr *MockContainerManagerMockRecorder) PruneContainers(ctx, labels interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneContainers", reflect.TypeOf((*MockContainerManager)(nil).PruneContainers), ctx, labels)
+}
+
+// PruneNetworks mocks base method.
+func (m *MockContainerManager) PruneNetworks(ctx context.Context, olderThan time.Duration, labels []string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PruneNetworks", ctx, olderThan, labels)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PruneNetworks indicates an expected call of PruneNetworks.
+func (mr *MockContainerManagerMockRecorder) PruneNetworks(ctx, olderThan, labels interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyToContainer", reflect.TypeOf((*MockContainerManager)(nil).CopyToContainer), ctx, containerID, workDir, copyIgnore)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneNetworks", reflect.TypeOf((*MockContainerManager)(nil).PruneNetworks), ctx, olderThan, labels)
+}
+
+// PruneVolumes mocks base method.
+func (m *MockContainerManager) PruneVolumes(ctx context.Context, prefix string, olderThan time.Duration) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PruneVolumes", ctx, prefix, olderThan)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PruneVolumes indicates an expected call of PruneVolumes.
+func (mr *MockContainerManagerMockRecorder) PruneVolumes(ctx, prefix, olderThan interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneVolumes", reflect.TypeOf((*MockContainerManager)(nil).PruneVolumes), ctx, prefix, olderThan)
 }
 
 // RemoveContainer mocks base method.
@@ -63,31 +155,88 @@ func (mr *MockContainerManagerMockRecorder) RemoveContainer(ctx, containerID, fo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveContainer", reflect.TypeOf((*MockContainerManager)(nil).RemoveContainer), ctx, containerID, forceRemove)
 }
 
+// RemoveNetwork mocks base method.
+func (m *MockContainerManager) RemoveNetwork(ctx context.Context, networkID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveNetwork", ctx, networkID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveNetwork indicates an expected call of RemoveNetwork.
+func (mr *MockContainerManagerMockRecorder) RemoveNetwork(ctx, networkID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveNetwork", reflect.TypeOf((*MockContainerManager)(nil).RemoveNetwork), ctx, networkID)
+}
+
+// RemoveResource mocks base method.
+func (m *MockContainerManager) RemoveResource(ctx context.Context, resource interfaces.Resource) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveResource", ctx, resource)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveResource indicates an expected call of RemoveResource.
+func (mr *MockContainerManagerMockRecorder) RemoveResource(ctx, resource interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveResource", reflect.TypeOf((*MockContainerManager)(nil).RemoveResource), ctx, resource)
+}
+
+// SampleResourceUsage mocks base method.
+func (m *MockContainerManager) SampleResourceUsage(ctx context.Context, containerID string) (interfaces.ResourceUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SampleResourceUsage", ctx, containerID)
+	ret0, _ := ret[0].(interfaces.ResourceUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SampleResourceUsage indicates an expected call of SampleResourceUsage.
+func (mr *MockContainerManagerMockRecorder) SampleResourceUsage(ctx, containerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SampleResourceUsage", reflect.TypeOf((*MockContainerManager)(nil).SampleResourceUsage), ctx, containerID)
+}
+
 // StartContainer mocks base method.
-func (m *MockContainerManager) StartContainer(ctx context.Context, jobName, image string, ports map[string]string) (container.ContainerCreateCreatedBody, error) {
+func (m *MockContainerManager) StartContainer(ctx context.Context, options interfaces.ContainerOptions) (container.ContainerCreateCreatedBody, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "StartContainer", ctx, jobName, image, ports)
+	ret := m.ctrl.Call(m, "StartContainer", ctx, options)
 	ret0, _ := ret[0].(container.ContainerCreateCreatedBody)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // StartContainer indicates an expected call of StartContainer.
-func (mr *MockContainerManagerMockRecorder) StartContainer(ctx, jobName, image, ports interface{}) *gomock.Call {
+func (mr *MockContainerManagerMockRecorder) StartContainer(ctx, options interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartContainer", reflect.TypeOf((*MockContainerManager)(nil).StartContainer), ctx, jobName, image, ports)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartContainer", reflect.TypeOf((*MockContainerManager)(nil).StartContainer), ctx, options)
 }
 
 // StopContainer mocks base method.
-func (m *MockContainerManager) StopContainer(ctx context.Context, containerID string) error {
+func (m *MockContainerManager) StopContainer(ctx context.Context, containerID string, timeout *time.Duration) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "StopContainer", ctx, containerID)
+	ret := m.ctrl.Call(m, "StopContainer", ctx, containerID, timeout)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // StopContainer indicates an expected call of StopContainer.
-func (mr *MockContainerManagerMockRecorder) StopContainer(ctx, containerID interface{}) *gomock.Call {
+func (mr *MockContainerManagerMockRecorder) StopContainer(ctx, containerID, timeout interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StopContainer", reflect.TypeOf((*MockContainerManager)(nil).StopContainer), ctx, containerID, timeout)
+}
+
+// WaitForContainer mocks base method.
+func (m *MockContainerManager) WaitForContainer(ctx context.Context, containerID string, timeout time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WaitForContainer", ctx, containerID, timeout)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WaitForContainer indicates an expected call of WaitForContainer.
+func (mr *MockContainerManagerMockRecorder) WaitForContainer(ctx, containerID, timeout interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StopContainer", reflect.TypeOf((*MockContainerManager)(nil).StopContainer), ctx, containerID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitForContainer", reflect.TypeOf((*MockContainerManager)(nil).WaitForContainer), ctx, containerID, timeout)
 }
@@ -9,6 +9,7 @@ import (
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
+	interfaces "github.com/muhammedikinci/pin/internal/interfaces"
 )
 
 // MockImageManager is a mock of ImageManager interface.
@@ -34,6 +35,20 @@ func (m *MockImageManager) EXPECT() *MockImageManagerMockRecorder {
 	return m.recorder
 }
 
+// BuildImage mocks base method.
+func (m *MockImageManager) BuildImage(ctx context.Context, options interfaces.BuildOptions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BuildImage", ctx, options)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BuildImage indicates an expected call of BuildImage.
+func (mr *MockImageManagerMockRecorder) BuildImage(ctx, options interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuildImage", reflect.TypeOf((*MockImageManager)(nil).BuildImage), ctx, options)
+}
+
 // CheckTheImageAvailable mocks base method.
 func (m *MockImageManager) CheckTheImageAvailable(ctx context.Context, image string) (bool, error) {
 	m.ctrl.T.Helper()
@@ -49,6 +64,36 @@ func (mr *MockImageManagerMockRecorder) CheckTheImageAvailable(ctx, image interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckTheImageAvailable", reflect.TypeOf((*MockImageManager)(nil).CheckTheImageAvailable), ctx, image)
 }
 
+// ImageDigest mocks base method.
+func (m *MockImageManager) ImageDigest(ctx context.Context, image string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImageDigest", ctx, image)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImageDigest indicates an expected call of ImageDigest.
+func (mr *MockImageManagerMockRecorder) ImageDigest(ctx, image interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImageDigest", reflect.TypeOf((*MockImageManager)(nil).ImageDigest), ctx, image)
+}
+
+// PruneImages mocks base method.
+func (m *MockImageManager) PruneImages(ctx context.Context, labels []string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PruneImages", ctx, labels)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PruneImages indicates an expected call of PruneImages.
+func (mr *MockImageManagerMockRecorder) PruneImages(ctx, labels interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneImages", reflect.TypeOf((*MockImageManager)(nil).PruneImages), ctx, labels)
+}
+
 // PullImage mocks base method.
 func (m *MockImageManager) PullImage(ctx context.Context, image string) error {
 	m.ctrl.T.Helper()
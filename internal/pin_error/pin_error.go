@@ -0,0 +1,96 @@
+package pin_error
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// CodeTimeout is a docker call that did not finish within its timeout.
+	CodeTimeout = "TIMEOUT"
+	// CodeError is an error that has no more specific code.
+	CodeError = "ERROR"
+	// CodeCopyFailed is a copy into or out of a container that kept breaking
+	// off mid-stream.
+	CodeCopyFailed = "COPY_FAILED"
+	// CodeValidation is a pipeline or a command line that can not be run.
+	CodeValidation = "VALIDATION"
+	// CodeDockerConnection is a docker daemon that can not be reached.
+	CodeDockerConnection = "DOCKER_CONNECTION"
+	// CodeScriptFailed is a script command that exited with a non-zero code.
+	CodeScriptFailed = "SCRIPT_FAILED"
+	// CodeInterrupted is a run stopped by a signal.
+	CodeInterrupted = "INTERRUPTED"
+)
+
+// exitCodes are the process exit codes of the error codes, shell wrappers
+// branch on them so they must not change. Other codes exit with 1.
+var exitCodes = map[string]int{
+	CodeValidation:       2,
+	CodeDockerConnection: 3,
+	CodeScriptFailed:     4,
+	CodeTimeout:          5,
+	CodeInterrupted:      130,
+}
+
+// PinError is an error with a stable code so callers can tell failure types
+// apart, Operation names what was being done when it happened.
+type PinError struct {
+	Code      string `json:"code" yaml:"code"`
+	Operation string `json:"operation,omitempty" yaml:"operation,omitempty"`
+	Message   string `json:"message" yaml:"message"`
+	Err       error  `json:"-" yaml:"-"`
+}
+
+func New(code, operation, message string, err error) *PinError {
+	return &PinError{
+		Code:      code,
+		Operation: operation,
+		Message:   message,
+		Err:       err,
+	}
+}
+
+// Timeout is the error of a docker call that exceeded timeout.
+func Timeout(operation string, timeout time.Duration, err error) *PinError {
+	return New(CodeTimeout, operation, fmt.Sprintf("%s did not finish within %s", operation, timeout), err)
+}
+
+// From returns the PinError in the chain of err, errors without one get
+// CodeError.
+func From(err error) *PinError {
+	if err == nil {
+		return nil
+	}
+
+	var pinErr *PinError
+
+	if errors.As(err, &pinErr) {
+		return pinErr
+	}
+
+	return New(CodeError, "", err.Error(), err)
+}
+
+// CopyFailed is the error of a copy that still failed after attempts.
+func CopyFailed(operation, what string, attempts int, err error) *PinError {
+	return New(CodeCopyFailed, operation, fmt.Sprintf("%s failed after %d attempts: %s", what, attempts, err), err)
+}
+
+// ExitCode is the process exit code of an error code.
+func ExitCode(code string) int {
+	if exitCode, ok := exitCodes[code]; ok {
+		return exitCode
+	}
+
+	return 1
+}
+
+func (e *PinError) Error() string {
+	return e.Code + ": " + e.Message
+}
+
+func (e *PinError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,146 @@
+package retry_client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/errdefs"
+	"github.com/muhammedikinci/pin/internal/interfaces"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// retryClient retries container create, start and exec create and inspect
+// calls that failed with a transient daemon error, every other call goes to
+// the client as is. Attaching to an exec starts it, so it is never retried.
+type retryClient struct {
+	interfaces.Client
+	attempts int
+	delay    time.Duration
+	log      interfaces.Log
+}
+
+// NewRetryClient wraps cli so a transient error is retried until attempts
+// calls were made, waiting delay between them.
+func NewRetryClient(cli interfaces.Client, attempts int, delay time.Duration, log interfaces.Log) retryClient {
+	return retryClient{
+		Client:   cli,
+		attempts: attempts,
+		delay:    delay,
+		log:      log,
+	}
+}
+
+func (rc retryClient) ContainerCreate(ctx context.Context, config *container.Config, hostConfig *container.HostConfig, networkingConfig *network.NetworkingConfig, platform *v1.Platform, containerName string) (container.ContainerCreateCreatedBody, error) {
+	var resp container.ContainerCreateCreatedBody
+
+	failed := false
+
+	err := rc.retry(ctx, "ContainerCreate", func() (err error) {
+		resp, err = rc.Client.ContainerCreate(ctx, config, hostConfig, networkingConfig, platform, containerName)
+
+		// the daemon may have created the container before the connection
+		// dropped, the retry then conflicts with the name of that container
+		if failed && containerName != "" && errdefs.IsConflict(err) {
+			return rc.createdContainer(ctx, containerName, err, &resp)
+		}
+
+		failed = true
+
+		return err
+	})
+
+	return resp, err
+}
+
+// createdContainer fills resp with the id of the container the failed create
+// call left behind, conflict is returned when it can not be inspected.
+func (rc retryClient) createdContainer(ctx context.Context, containerName string, conflict error, resp *container.ContainerCreateCreatedBody) error {
+	inspect, err := rc.Client.ContainerInspect(ctx, containerName)
+
+	if err != nil || inspect.ContainerJSONBase == nil {
+		return conflict
+	}
+
+	rc.log.Printf("ContainerCreate created %s before it failed, using it", containerName)
+
+	resp.ID = inspect.ID
+	resp.Warnings = nil
+
+	return nil
+}
+
+func (rc retryClient) ContainerStart(ctx context.Context, containerID string, options types.ContainerStartOptions) error {
+	return rc.retry(ctx, "ContainerStart", func() error {
+		return rc.Client.ContainerStart(ctx, containerID, options)
+	})
+}
+
+func (rc retryClient) ContainerExecCreate(ctx context.Context, container string, config types.ExecConfig) (types.IDResponse, error) {
+	var resp types.IDResponse
+
+	err := rc.retry(ctx, "ContainerExecCreate", func() (err error) {
+		resp, err = rc.Client.ContainerExecCreate(ctx, container, config)
+		return err
+	})
+
+	return resp, err
+}
+
+func (rc retryClient) ContainerExecInspect(ctx context.Context, execID string) (types.ContainerExecInspect, error) {
+	var resp types.ContainerExecInspect
+
+	err := rc.retry(ctx, "ContainerExecInspect", func() (err error) {
+		resp, err = rc.Client.ContainerExecInspect(ctx, execID)
+		return err
+	})
+
+	return resp, err
+}
+
+func (rc retryClient) retry(ctx context.Context, operation string, call func() error) error {
+	var err error
+
+	for attempt := 1; ; attempt++ {
+		err = call()
+
+		if err == nil || attempt >= rc.attempts || !IsTransient(err) || ctx.Err() != nil {
+			return err
+		}
+
+		rc.log.Printf("%s failed with a transient error, retrying (%d/%d): %s", operation, attempt, rc.attempts-1, err)
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(rc.delay):
+		}
+	}
+}
+
+// IsTransient reports whether err is a daemon error that may not happen
+// again: a dropped or reset connection or an internal server error.
+func IsTransient(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+
+	if errdefs.IsSystem(err) || errdefs.IsUnavailable(err) {
+		return true
+	}
+
+	// the docker client does not wrap every transport error
+	message := err.Error()
+
+	return strings.Contains(message, "connection reset by peer") || strings.HasSuffix(message, "EOF")
+}
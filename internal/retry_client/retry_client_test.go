@@ -0,0 +1,124 @@
+package retry_client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/errdefs"
+	"github.com/golang/mock/gomock"
+	"github.com/muhammedikinci/pin/internal/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContainerStartRetriesTransientErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	mockCli := mocks.NewMockClient(ctrl)
+	mockLog := mocks.NewMockLog(ctrl)
+
+	gomock.InOrder(
+		mockCli.EXPECT().ContainerStart(gomock.Any(), "id", types.ContainerStartOptions{}).Return(io.EOF),
+		mockCli.EXPECT().ContainerStart(gomock.Any(), "id", types.ContainerStartOptions{}).Return(nil),
+	)
+
+	mockLog.EXPECT().Printf(gomock.Any(), gomock.Any()).Times(1)
+
+	rc := NewRetryClient(mockCli, 3, time.Millisecond, mockLog)
+
+	assert.Equal(t, nil, rc.ContainerStart(context.Background(), "id", types.ContainerStartOptions{}))
+}
+
+func TestContainerExecCreateStopsAfterAttempts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	mockCli := mocks.NewMockClient(ctrl)
+	mockLog := mocks.NewMockLog(ctrl)
+
+	merror := errdefs.System(errors.New("internal server error"))
+
+	mockCli.EXPECT().ContainerExecCreate(gomock.Any(), "id", types.ExecConfig{}).Return(types.IDResponse{}, merror).Times(2)
+	mockLog.EXPECT().Printf(gomock.Any(), gomock.Any()).Times(1)
+
+	rc := NewRetryClient(mockCli, 2, time.Millisecond, mockLog)
+
+	_, err := rc.ContainerExecCreate(context.Background(), "id", types.ExecConfig{})
+
+	assert.Equal(t, merror, err)
+}
+
+func TestContainerCreateDoesNotRetryOtherErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	mockCli := mocks.NewMockClient(ctrl)
+	mockLog := mocks.NewMockLog(ctrl)
+
+	merror := errdefs.Conflict(errors.New("name in use"))
+
+	mockCli.EXPECT().ContainerCreate(gomock.Any(), nil, nil, nil, nil, "job").Return(container.ContainerCreateCreatedBody{}, merror).Times(1)
+
+	rc := NewRetryClient(mockCli, 3, time.Millisecond, mockLog)
+
+	_, err := rc.ContainerCreate(context.Background(), nil, nil, nil, nil, "job")
+
+	assert.Equal(t, merror, err)
+}
+
+func TestContainerCreateUsesTheContainerCreatedBeforeATransientError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	mockCli := mocks.NewMockClient(ctrl)
+	mockLog := mocks.NewMockLog(ctrl)
+
+	gomock.InOrder(
+		mockCli.EXPECT().ContainerCreate(gomock.Any(), nil, nil, nil, nil, "job").Return(container.ContainerCreateCreatedBody{}, io.EOF),
+		mockCli.EXPECT().ContainerCreate(gomock.Any(), nil, nil, nil, nil, "job").Return(container.ContainerCreateCreatedBody{}, errdefs.Conflict(errors.New("name in use"))),
+		mockCli.EXPECT().ContainerInspect(gomock.Any(), "job").Return(types.ContainerJSON{ContainerJSONBase: &types.ContainerJSONBase{ID: "created"}}, nil),
+	)
+
+	mockLog.EXPECT().Printf(gomock.Any(), gomock.Any()).Times(2)
+
+	rc := NewRetryClient(mockCli, 3, time.Millisecond, mockLog)
+
+	resp, err := rc.ContainerCreate(context.Background(), nil, nil, nil, nil, "job")
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "created", resp.ID)
+}
+
+func TestContainerExecAttachIsNotRetried(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	mockCli := mocks.NewMockClient(ctrl)
+	mockLog := mocks.NewMockLog(ctrl)
+
+	mockCli.EXPECT().ContainerExecAttach(gomock.Any(), "exec", types.ExecStartCheck{}).Return(types.HijackedResponse{}, io.EOF).Times(1)
+
+	rc := NewRetryClient(mockCli, 3, time.Millisecond, mockLog)
+
+	_, err := rc.ContainerExecAttach(context.Background(), "exec", types.ExecStartCheck{})
+
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestIsTransient(t *testing.T) {
+	assert.True(t, IsTransient(io.ErrUnexpectedEOF))
+	assert.True(t, IsTransient(errors.New("read tcp: connection reset by peer")))
+	assert.True(t, IsTransient(errdefs.Unavailable(errors.New("daemon restarting"))))
+	assert.False(t, IsTransient(errdefs.NotFound(errors.New("no such container"))))
+	assert.False(t, IsTransient(context.Canceled))
+}
@@ -1,44 +1,361 @@
 package runner
 
 import (
-	"bytes"
 	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/fatih/color"
-	"github.com/spf13/viper"
+	"github.com/muhammedikinci/pin/internal/pin_error"
+	"gopkg.in/yaml.v3"
 )
 
-func Apply(filepath string) error {
-	if err := checkFileExists(filepath); err != nil {
-		return err
+// stdinConfigPath reads the pipeline from stdin instead of a file.
+const stdinConfigPath = "-"
+
+type ApplyOptions struct {
+	// DryRun prints the plan and the pre-flight checks instead of running.
+	DryRun bool
+	// Detach runs the pipeline in a background process, see Attach.
+	Detach bool
+	// Watch runs the pipeline again whenever a project file changes.
+	Watch bool
+	// Set overrides values of the pipeline, see applySet.
+	Set []string
+	// Env adds variables to every job, see applyEnv.
+	Env []string
+	// Output json or yaml writes a RunReport to stdout and the logs to
+	// stderr, text only prints the logs.
+	Output string
+	// FromJob skips the jobs of the workflow before the named one.
+	FromJob string
+	// Resume runs the pipeline from the first job that did not succeed in
+	// the given run, the artifacts of the skipped jobs are restored from it.
+	Resume string
+	// Jobs runs only the named jobs of the workflow, the whole file is still
+	// parsed and validated.
+	Jobs []string
+	// MaxParallel overrides the maxParallel of the pipeline.
+	MaxParallel int
+}
+
+// Apply runs the pipeline of the given files, later files are merged over
+// earlier ones, see mergeConfig.
+func Apply(name string, filepaths []string, options ApplyOptions) error {
+	if len(filepaths) == 0 {
+		err := errors.New("pipeline file not specified")
+		fmt.Println(err)
+		return validationError(err)
 	}
 
-	if err := readConfig(filepath); err != nil {
-		return err
+	if err := checkOutputFormat(options.Output); err != nil {
+		fmt.Println(err)
+		return validationError(err)
+	}
+
+	if options.Output == "text" {
+		options.Output = ""
+	}
+
+	// the debug shell needs the terminal and the container of the run
+	if DebugOnFailure && (options.Detach || options.Output != "") {
+		err := errors.New("--debug-on-failure can not be used with --detach or --output")
+		fmt.Println(err)
+		return validationError(err)
+	}
+
+	if options.Output != "" && (options.Watch || options.Detach || options.DryRun) {
+		err := errors.New("--output can not be used with --watch, --detach or --dry-run")
+		fmt.Println(err)
+		return validationError(err)
+	}
+
+	var report io.Writer
+
+	if options.Output != "" {
+		var restore func()
+
+		report, restore = redirectLogs()
+		defer restore()
+	}
+
+	for _, filepath := range filepaths {
+		if err := checkFileExists(filepath); err != nil {
+			return reportError(report, options.Output, err)
+		}
+	}
+
+	if options.FromJob != "" && options.Resume != "" {
+		err := errors.New("--from-job and --resume can not be used together")
+		fmt.Println(err)
+		return reportError(report, options.Output, err)
+	}
+
+	if options.MaxParallel < 0 {
+		err := fmt.Errorf("invalid --max-parallel: %d, use a positive number", options.MaxParallel)
+		fmt.Println(err)
+		return reportError(report, options.Output, err)
+	}
+
+	if len(options.Jobs) > 0 && (options.FromJob != "" || options.Resume != "") {
+		err := errors.New("--jobs can not be used with --from-job or --resume")
+		fmt.Println(err)
+		return reportError(report, options.Output, err)
+	}
+
+	if options.Watch && (options.FromJob != "" || options.Resume != "") {
+		err := errors.New("--watch can not be used with --from-job or --resume")
+		fmt.Println(err)
+		return reportError(report, options.Output, err)
+	}
+
+	if options.Watch && (options.Detach || options.DryRun) {
+		err := errors.New("--watch can not be used with --detach or --dry-run")
+		fmt.Println(err)
+		return reportError(report, options.Output, err)
+	}
+
+	if readsStdin(filepaths) && (options.Watch || options.Detach) {
+		err := errors.New("--watch and --detach can not read the pipeline from stdin")
+		fmt.Println(err)
+		return reportError(report, options.Output, err)
+	}
+
+	if options.Watch {
+		return watch(name, filepaths, options)
 	}
 
-	pipeline, err := parse()
+	config, content, err := loadApplyConfig(filepaths, options)
 
 	if err != nil {
 		fmt.Println(err)
+		return reportError(report, options.Output, err)
+	}
+
+	pipeline, warnings, err := parse(config)
+
+	printWarnings(warnings)
+
+	if err != nil {
+		fmt.Println(err)
+		return reportError(report, options.Output, err)
+	}
+
+	if len(options.Jobs) > 0 {
+		pipeline, err = onlyJobs(pipeline, options.Jobs)
+
+		if err != nil {
+			fmt.Println(err)
+			return reportError(report, options.Output, err)
+		}
+	}
+
+	var resumed RunMetadata
+
+	if options.Resume != "" {
+		resumed, err = loadRun(options.Resume)
+
+		if err != nil {
+			err = fmt.Errorf("run %s not found: %w", options.Resume, err)
+			fmt.Println(err)
+			return reportError(report, options.Output, err)
+		}
+
+		options.FromJob = resumeStart(pipeline, resumed)
+
+		if options.FromJob == "" {
+			fmt.Printf("Run %s has no failed jobs\n", resumed.ID)
+
+			if report != nil {
+				if reportErr := writeReport(report, options.Output, RunReport{ID: resumed.ID, Pipeline: resumed.Pipeline, Status: JobStatusSuccess, Jobs: []JobReport{}}); reportErr != nil {
+					fmt.Println(reportErr)
+				}
+			}
+
+			return nil
+		}
+	}
+
+	full := pipeline.Workflow
+
+	if options.FromJob != "" {
+		pipeline, err = fromJob(pipeline, options.FromJob)
+
+		if err != nil {
+			fmt.Println(err)
+			return reportError(report, options.Output, err)
+		}
+
+		fmt.Printf("Starting from job %s\n", options.FromJob)
+	}
+
+	if options.DryRun {
+		currentRunner := Runner{}
+
+		if err := currentRunner.dryRun(pipeline); err != nil {
+			fmt.Println(err)
+			return err
+		}
+
+		return nil
+	}
+
+	if options.Detach {
+		if err := detach(); err != nil {
+			fmt.Println(err)
+			return err
+		}
+
+		return nil
+	}
+
+	if options.Resume != "" {
+		if err := restoreArtifacts(full[:len(full)-len(pipeline.Workflow)], resumed); err != nil {
+			fmt.Println(err)
+			return reportError(report, options.Output, err)
+		}
+	}
+
+	run, err := executePipeline(name, filepaths[0], content, pipeline, options.Resume)
+
+	if report != nil {
+		if reportErr := writeReport(report, options.Output, newRunReport(run, pipeline, err)); reportErr != nil {
+			fmt.Println(reportErr)
+		}
+	}
+
+	return err
+}
+
+// reportError writes the report of a pipeline that did not start, err is
+// returned as a validation error.
+func reportError(report io.Writer, output string, err error) error {
+	err = validationError(err)
+
+	if report == nil {
 		return err
 	}
 
-	currentRunner := Runner{}
+	if reportErr := writeReport(report, output, RunReport{Status: JobStatusFailed, Jobs: []JobReport{}, Error: classifyError(err)}); reportErr != nil {
+		fmt.Println(reportErr)
+	}
+
+	return err
+}
+
+// executePipeline runs the pipeline and records it, config is the document
+// saved with the run so it can be rerun.
+func executePipeline(name, configPath string, config []byte, pipeline Pipeline, rerunOf string) (RunMetadata, error) {
+	runID := os.Getenv(detachedRunEnv)
+
+	if runID == "" {
+		runID = newRunID()
+	}
+
+	fmt.Printf("Run ID: %s\n", runID)
+
+	name = runName(name, configPath)
+
+	currentRunner := Runner{runID: runID, pipelineName: name, hooks: &hookLog{}, chaos: ChaosMode, debugOnFailure: DebugOnFailure, ci: detectCI(), logDir: LogDir, configDir: configDir(configPath), attempt: runAttempt(rerunOf), startedAt: time.Now()}
+
+	if ChaosMode != nil {
+		color.Set(color.FgMagenta)
+		fmt.Printf("Chaos mode: %s\n", ChaosMode)
+		color.Unset()
+	}
+
+	err := currentRunner.run(pipeline)
 
-	if err := currentRunner.run(pipeline); err != nil {
+	run := recordRun(currentRunner, name, configPath, config, pipeline, rerunOf, err)
+	printSummary(os.Stdout, newRunReport(run, pipeline, err))
+
+	if currentRunner.logDir != "" {
+		fmt.Printf("Job logs: %s\n", filepath.Join(currentRunner.logDir, runID))
+	}
+
+	currentRunner.notifyPipeline(pipeline, run)
+
+	if ciErr := reportCI(os.Stdout, currentRunner.ci, run); ciErr != nil {
+		color.Set(color.FgYellow)
+		fmt.Printf("warning: step summary could not be written: %s\n", ciErr)
+		color.Unset()
+	}
+
+	if pipeline.GitNotes {
+		if noteErr := writeGitNote(".", run); noteErr != nil {
+			color.Set(color.FgYellow)
+			fmt.Printf("warning: git note could not be written: %s\n", noteErr)
+			color.Unset()
+		}
+	}
+
+	if err != nil {
 		fmt.Println(err.Error())
-		return err
+
+		if run.Status == JobStatusCancelled {
+			err = pin_error.New(pin_error.CodeInterrupted, "", err.Error(), err)
+		}
+
+		return run, err
 	}
 
 	color.Unset()
 
-	return nil
+	return run, nil
+}
+
+// recordRun persists the run metadata, a failure here only prints a warning
+// because the pipeline itself already finished.
+func recordRun(currentRunner Runner, name, configPath string, config []byte, pipeline Pipeline, rerunOf string, runErr error) RunMetadata {
+	run := newRunMetadata(currentRunner.runID, runName(name, configPath), configPath, pipeline)
+	run.DockerVersion = currentRunner.dockerVersion
+	run.RerunOf = rerunOf
+	run.Attempt = currentRunner.attempt
+	run.Hooks = currentRunner.hooks.all()
+	run.Healthcheck = currentRunner.healthcheck
+
+	if runErr != nil {
+		run.Status = JobStatusFailed
+	}
+
+	// the context only ends when the run was interrupted
+	if currentRunner.ctx != nil && currentRunner.ctx.Err() != nil {
+		run.Status = JobStatusCancelled
+	}
+
+	if err := saveRun(run, config); err != nil {
+		color.Set(color.FgYellow)
+		fmt.Printf("warning: run metadata could not be saved: %s\n", err)
+		color.Unset()
+	}
+
+	return run
+}
+
+// runName is the pipeline name of a run, the config file name without its
+// extension when no name is given.
+func runName(name, configPath string) string {
+	if name == "" && configPath == stdinConfigPath {
+		return "stdin"
+	}
+
+	if name == "" {
+		return strings.TrimSuffix(path.Base(configPath), path.Ext(configPath))
+	}
+
+	return name
 }
 
 func checkFileExists(filepath string) error {
+	if filepath == stdinConfigPath {
+		return nil
+	}
+
 	if _, err := os.Stat(filepath); errors.Is(err, os.ErrNotExist) {
 		return err
 	}
@@ -46,20 +363,130 @@ func checkFileExists(filepath string) error {
 	return nil
 }
 
-func readConfig(filepath string) error {
-	fileBytes, err := os.ReadFile(filepath)
+func readConfig(filepath string) (map[string]interface{}, error) {
+	if filepath == stdinConfigPath {
+		b, err := io.ReadAll(os.Stdin)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return decodeConfig(b)
+	}
+
+	f, err := os.Open(filepath)
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	viper.SetConfigType("yaml")
+	defer f.Close()
 
-	err = viper.ReadConfig(bytes.NewBuffer(fileBytes))
+	return readConfigFrom(f)
+}
+
+func readConfigFrom(r io.Reader) (map[string]interface{}, error) {
+	fileBytes, err := io.ReadAll(r)
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return decodeConfig(fileBytes)
+}
+
+// loadConfig reads and merges the pipeline files, resolving the paths of
+// each against its own directory. It also returns the document to save with
+// the run: the file itself when there is one, the merged configuration
+// otherwise.
+func loadConfig(filepaths []string) (map[string]interface{}, []byte, error) {
+	var config map[string]interface{}
+	var content []byte
+
+	stdinRead := false
+
+	for _, filepath := range filepaths {
+		var b []byte
+		var err error
+
+		if filepath == stdinConfigPath {
+			if stdinRead {
+				return nil, nil, errors.New("stdin can only be read once")
+			}
+
+			stdinRead = true
+			b, err = io.ReadAll(os.Stdin)
+		} else {
+			b, err = os.ReadFile(filepath)
+		}
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		fileConfig, err := decodeConfig(b)
+
+		if err == nil {
+			err = resolveConfigPaths(fileConfig, configDir(filepath))
+		}
+
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", filepath, err)
+		}
+
+		config = mergeConfig(config, fileConfig)
+		content = b
+	}
+
+	if len(filepaths) > 1 {
+		b, err := yaml.Marshal(config)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		content = b
+	}
+
+	return config, content, nil
+}
+
+// mergeConfig merges overlay over base: mappings are merged key by key,
+// any other value, lists included, replaces the value of base and a null
+// value removes the key.
+func mergeConfig(base, overlay map[string]interface{}) map[string]interface{} {
+	result := map[string]interface{}{}
+
+	for key, val := range base {
+		result[key] = val
+	}
+
+	for key, val := range overlay {
+		if val == nil {
+			delete(result, key)
+			continue
+		}
+
+		baseMap, baseOk := result[key].(map[string]interface{})
+		overlayMap, overlayOk := val.(map[string]interface{})
+
+		if baseOk && overlayOk {
+			result[key] = mergeConfig(baseMap, overlayMap)
+			continue
+		}
+
+		result[key] = val
+	}
+
+	return result
+}
+
+func readsStdin(filepaths []string) bool {
+	for _, filepath := range filepaths {
+		if filepath == stdinConfigPath {
+			return true
+		}
+	}
+
+	return false
 }
@@ -0,0 +1,95 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadConfigFromReader(t *testing.T) {
+	config, err := readConfigFrom(strings.NewReader("workflow:\n  - test\nTest:\n  image: alpine\n"))
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []interface{}{"test"}, config["workflow"])
+	assert.Contains(t, config, "test")
+}
+
+func TestStdinConfigIsRecordedWithTheRun(t *testing.T) {
+	r, w, _ := os.Pipe()
+
+	stdin := os.Stdin
+	os.Stdin = r
+	defer func() { os.Stdin = stdin }()
+
+	w.WriteString("workflow: []\n")
+	w.Close()
+
+	_, b, err := loadConfig([]string{stdinConfigPath})
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "workflow: []\n", string(b))
+
+	_, _, err = loadConfig([]string{stdinConfigPath, stdinConfigPath})
+
+	assert.EqualError(t, err, "stdin can only be read once")
+	assert.Equal(t, "stdin", runName("", stdinConfigPath))
+	assert.Equal(t, "generated", runName("generated", stdinConfigPath))
+	assert.Equal(t, nil, checkFileExists(stdinConfigPath))
+	assert.Equal(t, stdinConfigPath, newRunMetadata("id", "stdin", stdinConfigPath, Pipeline{}).ConfigPath)
+}
+
+func TestMergeConfig(t *testing.T) {
+	base := map[string]interface{}{
+		"workflow": []interface{}{"build", "test"},
+		"build": map[string]interface{}{
+			"image":  "golang:alpine",
+			"script": []interface{}{"go build ./..."},
+			"env":    map[string]interface{}{"CGO_ENABLED": "0", "GOFLAGS": "-mod=mod"},
+		},
+		"test": map[string]interface{}{"image": "golang:alpine"},
+	}
+
+	overlay := map[string]interface{}{
+		"workflow": []interface{}{"build"},
+		"build": map[string]interface{}{
+			"script": []interface{}{"go build -race ./..."},
+			"env":    map[string]interface{}{"GOFLAGS": nil},
+		},
+		"test": nil,
+	}
+
+	merged := mergeConfig(base, overlay)
+
+	assert.Equal(t, map[string]interface{}{
+		"workflow": []interface{}{"build"},
+		"build": map[string]interface{}{
+			"image":  "golang:alpine",
+			"script": []interface{}{"go build -race ./..."},
+			"env":    map[string]interface{}{"CGO_ENABLED": "0"},
+		},
+	}, merged)
+	assert.Contains(t, base, "test")
+}
+
+func TestLoadConfigMergesFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	basePath := filepath.Join(dir, "base.yaml")
+	overridesPath := filepath.Join(dir, "overrides.yaml")
+
+	os.WriteFile(basePath, []byte("workflow:\n  - test\nTest:\n  image: alpine\n  script:\n    - ls\n"), 0644)
+	os.WriteFile(overridesPath, []byte("test:\n  Image: alpine:3.16\n"), 0644)
+
+	config, content, err := loadConfig([]string{basePath, overridesPath})
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, map[string]interface{}{"image": "alpine:3.16", "script": []interface{}{"ls"}}, config["test"])
+
+	saved, err := decodeConfig(content)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, config, saved)
+}
@@ -0,0 +1,194 @@
+package runner
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// runArtifactsDir is the directory in the run directory that keeps a copy
+// of the artifacts of every job, the destination in the project is
+// overwritten by the next run.
+const runArtifactsDir = "artifacts"
+
+// artifactsDestination is the host directory the artifacts of a job are
+// copied to.
+func artifactsDestination(currentJob *Job) string {
+	if currentJob.Artifacts.Destination != "" {
+		return currentJob.Artifacts.Destination
+	}
+
+	return filepath.Join("artifacts", currentJob.Name)
+}
+
+// storeRunArtifacts copies the collected artifacts of a job from its
+// destination into the run history.
+func storeRunArtifacts(runID, job, destination string, files []string) error {
+	dir, err := runDir(runID)
+
+	if err != nil {
+		return err
+	}
+
+	target := filepath.Join(dir, runArtifactsDir, filepath.Base(job))
+
+	for _, file := range files {
+		if err := copyArtifact(filepath.Join(destination, filepath.FromSlash(file)), filepath.Join(target, filepath.FromSlash(file))); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// restoreRunArtifacts copies the stored artifacts of a job from the run
+// history back to destination.
+func restoreRunArtifacts(runID, job, destination string, files []string) error {
+	dir, err := runDir(runID)
+
+	if err != nil {
+		return err
+	}
+
+	source := filepath.Join(dir, runArtifactsDir, filepath.Base(job))
+
+	for _, file := range files {
+		path, err := artifactPath(file)
+
+		if err != nil {
+			return err
+		}
+
+		if err := copyArtifact(filepath.Join(source, path), filepath.Join(destination, path)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ArtifactsGet copies the artifacts a run stored into dir, every job gets a
+// directory of its own. Only the artifacts of job are copied when it is
+// given.
+func ArtifactsGet(runID, job, dir string) error {
+	count, err := artifactsGet(runID, job, dir)
+
+	if err != nil {
+		fmt.Println(err)
+		return err
+	}
+
+	color.Set(color.FgGreen)
+	fmt.Printf("%d artifacts of run %s copied to %s\n", count, runID, dir)
+	color.Unset()
+
+	return nil
+}
+
+func artifactsGet(runID, job, dir string) (int, error) {
+	run, err := loadRun(runID)
+
+	if err != nil {
+		return 0, fmt.Errorf("run %s not found: %w", runID, err)
+	}
+
+	source, err := runDir(run.ID)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if dir == "" {
+		dir = filepath.Join("artifacts", run.ID)
+	}
+
+	found := job == ""
+	count := 0
+
+	for _, snapshot := range run.Jobs {
+		if job != "" && snapshot.Name != job {
+			continue
+		}
+
+		found = true
+
+		for _, artifact := range snapshot.Artifacts {
+			path, err := artifactPath(artifact.Path)
+
+			if err != nil {
+				return count, err
+			}
+
+			from := filepath.Join(source, runArtifactsDir, filepath.Base(snapshot.Name), path)
+
+			if _, err := os.Stat(from); err != nil {
+				return count, fmt.Errorf("artifact %s of job %s is not stored with run %s", artifact.Path, snapshot.Name, run.ID)
+			}
+
+			if err := copyArtifact(from, filepath.Join(dir, filepath.Base(snapshot.Name), path)); err != nil {
+				return count, err
+			}
+
+			count++
+		}
+	}
+
+	if !found {
+		return 0, fmt.Errorf("job %s is not in run %s", job, run.ID)
+	}
+
+	if count == 0 {
+		return 0, fmt.Errorf("run %s has no artifacts", run.ID)
+	}
+
+	return count, nil
+}
+
+// artifactPath converts an artifact path of the run metadata, it must stay in
+// the directory of the job.
+func artifactPath(file string) (string, error) {
+	path := filepath.FromSlash(file)
+
+	if filepath.IsAbs(path) || strings.HasPrefix(filepath.Clean(path), "..") {
+		return "", fmt.Errorf("invalid artifact path: %s", file)
+	}
+
+	return path, nil
+}
+
+func copyArtifact(from, to string) error {
+	if err := os.MkdirAll(filepath.Dir(to), 0755); err != nil {
+		return err
+	}
+
+	src, err := os.Open(from)
+
+	if err != nil {
+		return err
+	}
+
+	defer src.Close()
+
+	info, err := src.Stat()
+
+	if err != nil {
+		return err
+	}
+
+	dst, err := os.OpenFile(to, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, info.Mode().Perm())
+
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return err
+	}
+
+	return dst.Close()
+}
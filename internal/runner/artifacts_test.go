@@ -0,0 +1,73 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArtifactsGetCopiesStoredArtifactsOfARun(t *testing.T) {
+	t.Setenv("PIN_HOME", t.TempDir())
+
+	destination := t.TempDir()
+
+	assert.NoError(t, os.MkdirAll(filepath.Join(destination, "bin"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(destination, "bin", "app"), []byte("binary"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(destination, "report.xml"), []byte("<testsuite/>"), 0644))
+
+	assert.NoError(t, storeRunArtifacts("20220515-100000-aaaaaa", "build", destination, []string{"bin/app"}))
+	assert.NoError(t, storeRunArtifacts("20220515-100000-aaaaaa", "test", destination, []string{"report.xml"}))
+
+	err := saveRun(RunMetadata{ID: "20220515-100000-aaaaaa", Jobs: []JobSnapshot{
+		{Name: "build", Artifacts: []ArtifactFile{{Path: "bin/app", Size: 6}}},
+		{Name: "test", Artifacts: []ArtifactFile{{Path: "report.xml", Size: 12}}},
+		{Name: "lint"},
+	}}, []byte("workflow: []"))
+	assert.NoError(t, err)
+
+	// the destination of the next run does not change the stored copy
+	assert.NoError(t, os.WriteFile(filepath.Join(destination, "bin", "app"), []byte("changed"), 0755))
+
+	out := t.TempDir()
+
+	count, err := artifactsGet("20220515-100000-aaaaaa", "", out)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	b, err := os.ReadFile(filepath.Join(out, "build", "bin", "app"))
+	assert.NoError(t, err)
+	assert.Equal(t, "binary", string(b))
+
+	b, err = os.ReadFile(filepath.Join(out, "test", "report.xml"))
+	assert.NoError(t, err)
+	assert.Equal(t, "<testsuite/>", string(b))
+
+	count, err = artifactsGet("20220515-100000-aaaaaa", "test", t.TempDir())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	_, err = artifactsGet("20220515-100000-aaaaaa", "lint", t.TempDir())
+
+	assert.EqualError(t, err, "run 20220515-100000-aaaaaa has no artifacts")
+
+	_, err = artifactsGet("20220515-100000-aaaaaa", "deploy", t.TempDir())
+
+	assert.EqualError(t, err, "job deploy is not in run 20220515-100000-aaaaaa")
+}
+
+func TestArtifactsGetRejectsPathsOutsideTheJobDirectory(t *testing.T) {
+	t.Setenv("PIN_HOME", t.TempDir())
+
+	err := saveRun(RunMetadata{ID: "20220515-100000-aaaaaa", Jobs: []JobSnapshot{
+		{Name: "build", Artifacts: []ArtifactFile{{Path: "../../run.json"}}},
+	}}, []byte("workflow: []"))
+	assert.NoError(t, err)
+
+	_, err = artifactsGet("20220515-100000-aaaaaa", "", t.TempDir())
+
+	assert.EqualError(t, err, "invalid artifact path: ../../run.json")
+}
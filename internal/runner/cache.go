@@ -0,0 +1,245 @@
+package runner
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/fatih/color"
+)
+
+type Cache struct {
+	Key   string
+	Paths []string
+}
+
+var checksumPattern = regexp.MustCompile(`{{\s*checksum\s+"([^"]+)"\s*}}`)
+var unsafeKeyChars = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+// stateDir is where pin keeps data between runs, PIN_HOME overrides ~/.pin.
+func stateDir() (string, error) {
+	if home := os.Getenv("PIN_HOME"); home != "" {
+		return home, nil
+	}
+
+	home, err := os.UserHomeDir()
+
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, ".pin"), nil
+}
+
+// resolveCacheKey replaces {{ checksum "file" }} placeholders with the
+// sha256 of the given project file.
+func resolveCacheKey(key string) (string, error) {
+	var checksumErr error
+
+	resolved := checksumPattern.ReplaceAllStringFunc(key, func(match string) string {
+		file := checksumPattern.FindStringSubmatch(match)[1]
+
+		content, err := os.ReadFile(file)
+
+		if err != nil {
+			checksumErr = err
+			return ""
+		}
+
+		sum := sha256.Sum256(content)
+		return hex.EncodeToString(sum[:])
+	})
+
+	if checksumErr != nil {
+		return "", checksumErr
+	}
+
+	return resolved, nil
+}
+
+func cacheDir(key string) (string, error) {
+	base, err := stateDir()
+
+	if err != nil {
+		return "", err
+	}
+
+	project, err := projectID()
+
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(base, "cache", project, unsafeKeyChars.ReplaceAllString(key, "_")), nil
+}
+
+// projectID separates the state of different projects sharing PIN_HOME.
+func projectID() (string, error) {
+	currentPath, err := os.Getwd()
+
+	if err != nil {
+		return "", err
+	}
+
+	project := sha256.Sum256([]byte(currentPath))
+
+	return hex.EncodeToString(project[:6]), nil
+}
+
+func cacheFileName(containerPath string) string {
+	sum := sha256.Sum256([]byte(containerPath))
+	return hex.EncodeToString(sum[:8]) + ".tar"
+}
+
+func (r Runner) restoreCache(currentJob Job) error {
+	key, err := resolveCacheKey(currentJob.Cache.Key)
+
+	if err != nil {
+		return err
+	}
+
+	dir, err := cacheDir(key)
+
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(dir); errors.Is(err, os.ErrNotExist) {
+		currentJob.InfoLog.Printf("Cache not found: %s", key)
+		return nil
+	}
+
+	for _, containerPath := range currentJob.Cache.Paths {
+		file, err := os.Open(filepath.Join(dir, cacheFileName(containerPath)))
+
+		if err != nil {
+			continue
+		}
+
+		parent := path.Dir(path.Clean(containerPath))
+
+		if err := r.internalExec("mkdir -p "+parent, currentJob); err != nil {
+			file.Close()
+			return err
+		}
+
+		err = r.cli.CopyToContainer(r.ctx, currentJob.Container.ID, parent, file, types.CopyToContainerOptions{})
+		file.Close()
+
+		if err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	os.Chtimes(dir, now, now)
+
+	color.Set(color.FgGreen)
+	currentJob.InfoLog.Printf("Cache restored: %s", key)
+	color.Unset()
+
+	return nil
+}
+
+func (r Runner) saveCache(currentJob Job) error {
+	key, err := resolveCacheKey(currentJob.Cache.Key)
+
+	if err != nil {
+		return err
+	}
+
+	dir, err := cacheDir(key)
+
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(dir); err == nil {
+		currentJob.InfoLog.Printf("Cache is up to date: %s", key)
+		return nil
+	}
+
+	tmpDir := dir + ".tmp"
+
+	if err := os.MkdirAll(tmpDir, 0755); err != nil {
+		return err
+	}
+
+	defer os.RemoveAll(tmpDir)
+
+	for _, containerPath := range currentJob.Cache.Paths {
+		reader, _, err := r.cli.CopyFromContainer(r.ctx, currentJob.Container.ID, path.Clean(containerPath))
+
+		if err != nil {
+			currentJob.InfoLog.Printf("Cache path skipped: %s", containerPath)
+			continue
+		}
+
+		if err := writeCacheFile(filepath.Join(tmpDir, cacheFileName(containerPath)), reader); err != nil {
+			return err
+		}
+	}
+
+	if err := os.Rename(tmpDir, dir); err != nil {
+		return fmt.Errorf("cache could not be saved: %w", err)
+	}
+
+	color.Set(color.FgGreen)
+	currentJob.InfoLog.Printf("Cache saved: %s", key)
+	color.Unset()
+
+	return nil
+}
+
+func writeCacheFile(name string, reader io.ReadCloser) error {
+	defer reader.Close()
+
+	file, err := os.Create(name)
+
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	_, err = io.Copy(file, reader)
+
+	return err
+}
+
+// cleanCache removes cache entries that were not saved or restored since
+// olderThan and returns their keys.
+func cleanCache(base string, olderThan time.Duration) ([]string, error) {
+	removed := []string{}
+	threshold := time.Now().Add(-olderThan)
+
+	entries, err := filepath.Glob(filepath.Join(base, "cache", "*", "*"))
+
+	if err != nil {
+		return removed, err
+	}
+
+	for _, entry := range entries {
+		info, err := os.Stat(entry)
+
+		if err != nil || !info.IsDir() || info.ModTime().After(threshold) {
+			continue
+		}
+
+		if err := os.RemoveAll(entry); err != nil {
+			return removed, err
+		}
+
+		removed = append(removed, filepath.Base(entry))
+	}
+
+	return removed, nil
+}
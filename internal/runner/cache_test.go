@@ -0,0 +1,65 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveCacheKeyReplacesChecksumPlaceholders(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "go.sum")
+
+	os.WriteFile(file, []byte("hello"), 0644)
+
+	key, err := resolveCacheKey(`go-{{ checksum "` + file + `" }}`)
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, "go-2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", key)
+}
+
+func TestResolveCacheKeyWithMissingFileReturnsError(t *testing.T) {
+	_, err := resolveCacheKey(`go-{{ checksum "missing.sum" }}`)
+
+	assert.NotNil(t, err)
+}
+
+func TestGetCacheRequiresKeyAndPaths(t *testing.T) {
+	cache, err := getCache(map[string]interface{}{
+		"key":   "node",
+		"paths": []interface{}{"/root/node_modules"},
+	})
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, &Cache{Key: "node", Paths: []string{"/root/node_modules"}}, cache)
+
+	_, err = getCache(map[string]interface{}{"paths": "/root/node_modules"})
+
+	assert.EqualError(t, err, "cache key not specified")
+
+	_, err = getCache(map[string]interface{}{"key": "node"})
+
+	assert.EqualError(t, err, "cache paths not specified")
+}
+
+func TestCleanCacheRemovesOnlyExpiredEntries(t *testing.T) {
+	base := t.TempDir()
+	oldEntry := filepath.Join(base, "cache", "project", "old")
+	newEntry := filepath.Join(base, "cache", "project", "new")
+
+	os.MkdirAll(oldEntry, 0755)
+	os.MkdirAll(newEntry, 0755)
+
+	past := time.Now().Add(-48 * time.Hour)
+	os.Chtimes(oldEntry, past, past)
+
+	removed, err := cleanCache(base, 24*time.Hour)
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, []string{"old"}, removed)
+	assert.NoDirExists(t, oldEntry)
+	assert.DirExists(t, newEntry)
+}
@@ -0,0 +1,64 @@
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// JobStatusCancelled is the status of a run that was interrupted.
+const JobStatusCancelled = "cancelled"
+
+// Cancel stops a detached run, the run removes its containers and records
+// itself as cancelled like an interrupted foreground run.
+func Cancel(runID string) error {
+	if err := cancel(runID); err != nil {
+		fmt.Println(err)
+		return err
+	}
+
+	color.Set(color.FgYellow)
+	fmt.Printf("Run %s is being cancelled\n", runID)
+	color.Unset()
+
+	return nil
+}
+
+func cancel(runID string) error {
+	dir, err := runDir(runID)
+
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "run.json")); err == nil {
+		return fmt.Errorf("run %s already finished", runID)
+	}
+
+	b, err := os.ReadFile(filepath.Join(dir, runPIDFile))
+
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("run %s was not started with --detach", runID)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(string(b)))
+
+	if err != nil {
+		return fmt.Errorf("invalid pid file of run %s: %w", runID, err)
+	}
+
+	if !processAlive(pid) {
+		return fmt.Errorf("run %s is not running", runID)
+	}
+
+	return terminateProcess(pid)
+}
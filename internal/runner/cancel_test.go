@@ -0,0 +1,45 @@
+package runner
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCancelTerminatesDetachedRun(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sleep")
+	}
+
+	t.Setenv("PIN_HOME", t.TempDir())
+
+	cmd := exec.Command("sleep", "30")
+	assert.Equal(t, nil, cmd.Start())
+
+	id := newRunID()
+	dir, _ := runDir(id)
+
+	os.MkdirAll(dir, 0755)
+	os.WriteFile(filepath.Join(dir, runPIDFile), []byte(strconv.Itoa(cmd.Process.Pid)), 0644)
+
+	assert.Equal(t, nil, cancel(id))
+
+	err := cmd.Wait()
+
+	assert.EqualError(t, err, "signal: terminated")
+}
+
+func TestCancelFailsForFinishedRun(t *testing.T) {
+	t.Setenv("PIN_HOME", t.TempDir())
+
+	id := newRunID()
+	saveRun(RunMetadata{ID: id}, []byte{})
+
+	assert.EqualError(t, cancel(id), "run "+id+" already finished")
+	assert.EqualError(t, cancel("20220101-000000-000000"), "run 20220101-000000-000000 was not started with --detach")
+}
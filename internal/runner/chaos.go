@@ -0,0 +1,106 @@
+package runner
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// ChaosMode injects failures into command results when it is set by the
+// cli, it is used to test how pipelines and pin itself handle flaky steps.
+var ChaosMode *Chaos
+
+// chaosExitCode is EX_TEMPFAIL, so injected failures can be told apart from
+// real ones in the logs and the run history.
+const chaosExitCode = 75
+
+type Chaos struct {
+	Probability float64
+	Seed        int64
+
+	mu       sync.Mutex
+	attempts map[string]int
+}
+
+// ParseChaos reads a "p=0.3,seed=42" spec, a random seed is picked when it
+// is not given.
+func ParseChaos(spec string) (*Chaos, error) {
+	chaos := &Chaos{Seed: time.Now().UnixNano(), attempts: map[string]int{}}
+	probability := false
+
+	for _, part := range strings.Split(spec, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+
+		switch key {
+		case "p":
+			p, err := strconv.ParseFloat(value, 64)
+
+			if err != nil || p < 0 || p > 1 {
+				return nil, fmt.Errorf("invalid chaos probability: %s", value)
+			}
+
+			chaos.Probability = p
+			probability = true
+		case "seed":
+			seed, err := strconv.ParseInt(value, 10, 64)
+
+			if err != nil {
+				return nil, fmt.Errorf("invalid chaos seed: %s", value)
+			}
+
+			chaos.Seed = seed
+		default:
+			return nil, fmt.Errorf("unknown chaos option: %s", part)
+		}
+	}
+
+	if !probability {
+		return nil, fmt.Errorf("chaos probability not specified: %s", spec)
+	}
+
+	return chaos, nil
+}
+
+// inject decides whether a command result is turned into a failure. The
+// decision only depends on the seed, the job, the command and how many times
+// it ran, so a seed reproduces the same failures with parallel jobs too.
+func (c *Chaos) inject(job, command string) bool {
+	if c == nil || c.Probability == 0 {
+		return false
+	}
+
+	key := job + "\x00" + command
+
+	c.mu.Lock()
+	attempt := c.attempts[key]
+	c.attempts[key]++
+	c.mu.Unlock()
+
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%d\x00%s\x00%d", c.Seed, key, attempt)
+
+	return float64(h.Sum64()>>11)/(1<<53) < c.Probability
+}
+
+// injectFailure replaces a successful exit code with chaosExitCode when the
+// chaos mode decides so.
+func (r Runner) injectFailure(currentJob Job, command string, exitCode int) int {
+	if exitCode != 0 || !r.chaos.inject(currentJob.Name, command) {
+		return exitCode
+	}
+
+	color.Set(color.FgMagenta)
+	currentJob.InfoLog.Printf("chaos: injected failure with exit code %d", chaosExitCode)
+	color.Unset()
+
+	return chaosExitCode
+}
+
+func (c *Chaos) String() string {
+	return fmt.Sprintf("p=%g,seed=%d", c.Probability, c.Seed)
+}
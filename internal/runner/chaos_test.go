@@ -0,0 +1,57 @@
+package runner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseChaos(t *testing.T) {
+	chaos, err := ParseChaos("p=0.3,seed=42")
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, 0.3, chaos.Probability)
+	assert.Equal(t, int64(42), chaos.Seed)
+
+	_, err = ParseChaos("seed=42")
+
+	assert.EqualError(t, err, "chaos probability not specified: seed=42")
+
+	_, err = ParseChaos("p=2")
+
+	assert.EqualError(t, err, "invalid chaos probability: 2")
+
+	_, err = ParseChaos("p=0.1,rate=3")
+
+	assert.EqualError(t, err, "unknown chaos option: rate=3")
+}
+
+func TestChaosIsDeterministicForASeed(t *testing.T) {
+	decisions := func() []bool {
+		chaos, _ := ParseChaos("p=0.5,seed=7")
+		result := []bool{}
+
+		for i := 0; i < 20; i++ {
+			result = append(result, chaos.inject("test", "go test ./..."))
+		}
+
+		return result
+	}
+
+	first := decisions()
+
+	assert.Equal(t, first, decisions())
+	assert.Contains(t, first, true)
+	assert.Contains(t, first, false)
+}
+
+func TestChaosNeverAndAlwaysFails(t *testing.T) {
+	never, _ := ParseChaos("p=0")
+	always, _ := ParseChaos("p=1")
+
+	var disabled *Chaos
+
+	assert.False(t, never.inject("test", "ls"))
+	assert.True(t, always.inject("test", "ls"))
+	assert.False(t, disabled.inject("test", "ls"))
+}
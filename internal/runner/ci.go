@@ -0,0 +1,135 @@
+package runner
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	CIGitHubActions = "github"
+	CIGitLab        = "gitlab"
+)
+
+// detectCI returns the ci service pin runs in, empty when it runs anywhere
+// else.
+func detectCI() string {
+	switch {
+	case os.Getenv("GITHUB_ACTIONS") == "true":
+		return CIGitHubActions
+	case os.Getenv("GITLAB_CI") == "true":
+		return CIGitLab
+	}
+
+	return ""
+}
+
+// ciGroups reports whether the output of every job can be folded into a
+// group, groups can not interleave so parallel jobs are never grouped.
+func ciGroups(ci string, jobs []*Job) bool {
+	if ci == "" {
+		return false
+	}
+
+	for _, job := range jobs {
+		if job.IsParallel {
+			return false
+		}
+	}
+
+	return true
+}
+
+func startCIGroup(w io.Writer, ci, job string) {
+	switch ci {
+	case CIGitHubActions:
+		fmt.Fprintf(w, "::group::%s\n", job)
+	case CIGitLab:
+		fmt.Fprintf(w, "\x1b[0Ksection_start:%d:%s\r\x1b[0K%s\n", time.Now().Unix(), ciSectionName(job), job)
+	}
+}
+
+func endCIGroup(w io.Writer, ci, job string) {
+	switch ci {
+	case CIGitHubActions:
+		fmt.Fprintln(w, "::endgroup::")
+	case CIGitLab:
+		fmt.Fprintf(w, "\x1b[0Ksection_end:%d:%s\r\x1b[0K\n", time.Now().Unix(), ciSectionName(job))
+	}
+}
+
+func ciSectionName(job string) string {
+	return "pin_" + unsafeKeyChars.ReplaceAllString(job, "_")
+}
+
+// reportCI annotates the failed jobs and appends a summary of the run to the
+// step summary on github actions, gitlab has neither.
+func reportCI(w io.Writer, ci string, run RunMetadata) error {
+	if ci != CIGitHubActions {
+		return nil
+	}
+
+	for _, job := range run.Jobs {
+		if job.Status == JobStatusFailed && job.Tolerated {
+			fmt.Fprintf(w, "::warning file=%s,title=%s::%s\n", escapeCIProperty(run.ConfigPath), escapeCIProperty("pin job "+job.Name+" failed, tolerated by minSuccess"), escapeCIData(job.Error))
+		} else if job.Status == JobStatusFailed {
+			fmt.Fprintf(w, "::error file=%s,title=%s::%s\n", escapeCIProperty(run.ConfigPath), escapeCIProperty("pin job "+job.Name+" failed"), escapeCIData(job.Error))
+		}
+	}
+
+	file := os.Getenv("GITHUB_STEP_SUMMARY")
+
+	if file == "" {
+		return nil
+	}
+
+	f, err := os.OpenFile(file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	_, err = io.WriteString(f, stepSummary(run))
+
+	return err
+}
+
+func stepSummary(run RunMetadata) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "### pin %s: %s\n\n", run.Pipeline, run.Status)
+	fmt.Fprintf(&b, "Run `%s`\n\n", run.ID)
+	b.WriteString("| Job | Status | Duration |\n| --- | --- | --- |\n")
+
+	for _, job := range run.Jobs {
+		status := job.Status
+
+		if job.Status == JobStatusSuccess {
+			status = "✅ " + status
+		} else if job.Status == JobStatusFailed && job.Tolerated {
+			status = "⚠️ " + status + " (tolerated)"
+		} else if job.Status == JobStatusFailed {
+			status = "❌ " + status
+		}
+
+		fmt.Fprintf(&b, "| %s | %s | %s |\n", job.Name, status, jobDuration(job).Round(time.Millisecond))
+	}
+
+	b.WriteString("\n")
+
+	return b.String()
+}
+
+// escapeCIData escapes the message of a workflow command.
+func escapeCIData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeCIProperty escapes a property value of a workflow command.
+func escapeCIProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
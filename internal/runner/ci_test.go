@@ -0,0 +1,77 @@
+package runner
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectCI(t *testing.T) {
+	t.Setenv("GITHUB_ACTIONS", "")
+	t.Setenv("GITLAB_CI", "")
+
+	assert.Equal(t, "", detectCI())
+
+	t.Setenv("GITLAB_CI", "true")
+	assert.Equal(t, CIGitLab, detectCI())
+
+	t.Setenv("GITHUB_ACTIONS", "true")
+	assert.Equal(t, CIGitHubActions, detectCI())
+}
+
+func TestCIGroupsAreNotUsedWithParallelJobs(t *testing.T) {
+	jobs := []*Job{{Name: "build"}, {Name: "test"}}
+
+	assert.True(t, ciGroups(CIGitHubActions, jobs))
+	assert.False(t, ciGroups("", jobs))
+
+	jobs[1].IsParallel = true
+
+	assert.False(t, ciGroups(CIGitHubActions, jobs))
+}
+
+func TestCIGroups(t *testing.T) {
+	var out bytes.Buffer
+
+	startCIGroup(&out, CIGitHubActions, "build")
+	endCIGroup(&out, CIGitHubActions, "build")
+
+	assert.Equal(t, "::group::build\n::endgroup::\n", out.String())
+
+	out.Reset()
+
+	startCIGroup(&out, CIGitLab, "unit tests")
+
+	assert.Regexp(t, "^\x1b\\[0Ksection_start:[0-9]+:pin_unit_tests\r\x1b\\[0Kunit tests\n$", out.String())
+}
+
+func TestReportCIAnnotatesFailuresAndWritesStepSummary(t *testing.T) {
+	summary := filepath.Join(t.TempDir(), "summary.md")
+	t.Setenv("GITHUB_STEP_SUMMARY", summary)
+
+	start := time.Date(2022, 5, 15, 10, 0, 0, 0, time.UTC)
+
+	run := RunMetadata{
+		ID:         "20220515-100000-a1b2c3",
+		Pipeline:   "ci",
+		ConfigPath: "pipeline.yaml",
+		Status:     JobStatusFailed,
+		Jobs: []JobSnapshot{
+			{Name: "build", Status: JobStatusSuccess, StartedAt: start, FinishedAt: start.Add(2 * time.Second)},
+			{Name: "test", Status: JobStatusFailed, Error: "exit code 1\n3 tests failed", StartedAt: start, FinishedAt: start.Add(time.Second)},
+		},
+	}
+
+	var out bytes.Buffer
+
+	assert.Equal(t, nil, reportCI(&out, CIGitHubActions, run))
+	assert.Equal(t, "::error file=pipeline.yaml,title=pin job test failed::exit code 1%0A3 tests failed\n", out.String())
+
+	content, _ := os.ReadFile(summary)
+
+	assert.Equal(t, "### pin ci: failed\n\nRun `20220515-100000-a1b2c3`\n\n| Job | Status | Duration |\n| --- | --- | --- |\n| build | ✅ success | 2s |\n| test | ❌ failed | 1s |\n\n", string(content))
+}
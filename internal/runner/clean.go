@@ -0,0 +1,73 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/muhammedikinci/pin/internal/container_manager"
+)
+
+// Clean removes pin networks, workspace volumes and cache entries older than
+// the retention.
+func Clean(olderThan time.Duration) error {
+	infoLog := log.New(os.Stdout, glyph(glyphJob)+" clean ", 0)
+
+	cli, err := newDockerClient()
+
+	if err != nil {
+		fmt.Println(err)
+		return err
+	}
+
+	containerManager := container_manager.NewContainerManager(cli, infoLog)
+
+	networks, err := containerManager.PruneNetworks(context.Background(), olderThan, nil)
+
+	if err != nil {
+		fmt.Println(err)
+		return err
+	}
+
+	for _, name := range networks {
+		infoLog.Printf("Network removed: %s", name)
+	}
+
+	volumes, err := containerManager.PruneVolumes(context.Background(), workspaceVolumePrefix, olderThan)
+
+	if err != nil {
+		fmt.Println(err)
+		return err
+	}
+
+	for _, name := range volumes {
+		infoLog.Printf("Workspace volume removed: %s", name)
+	}
+
+	base, err := stateDir()
+
+	if err != nil {
+		fmt.Println(err)
+		return err
+	}
+
+	caches, err := cleanCache(base, olderThan)
+
+	if err != nil {
+		fmt.Println(err)
+		return err
+	}
+
+	for _, key := range caches {
+		infoLog.Printf("Cache removed: %s", key)
+	}
+
+	color.Set(color.FgGreen)
+	infoLog.Printf("Removed %d networks, %d workspace volumes and %d caches", len(networks), len(volumes), len(caches))
+	color.Unset()
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	volumetypes "github.com/docker/docker/api/types/volume"
+	"github.com/golang/mock/gomock"
+	"github.com/muhammedikinci/pin/internal/interfaces"
+	"github.com/muhammedikinci/pin/internal/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCleanRemovesOldWorkspaceVolumesAndCaches(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	home := t.TempDir()
+	t.Setenv("PIN_HOME", home)
+
+	old := time.Now().Add(-10 * 24 * time.Hour)
+
+	oldCache := filepath.Join(home, "cache", "project", "go-1")
+	newCache := filepath.Join(home, "cache", "project", "go-2")
+	os.MkdirAll(oldCache, 0755)
+	os.MkdirAll(newCache, 0755)
+	os.Chtimes(oldCache, old, old)
+
+	mockCli := mocks.NewMockClient(ctrl)
+
+	mockCli.EXPECT().NetworkList(gomock.Any(), gomock.Any()).Return([]types.NetworkResource{}, nil)
+	mockCli.EXPECT().VolumeList(gomock.Any(), gomock.Any()).Return(volumetypes.VolumeListOKBody{Volumes: []*types.Volume{
+		{Name: workspaceVolumePrefix + "project_build", CreatedAt: old.Format(time.RFC3339)},
+		{Name: workspaceVolumePrefix + "project_test", CreatedAt: time.Now().Format(time.RFC3339)},
+	}}, nil)
+	mockCli.EXPECT().VolumeRemove(gomock.Any(), workspaceVolumePrefix+"project_build", false).Return(nil)
+
+	defer func(f func() (interfaces.Client, error)) { newDockerClient = f }(newDockerClient)
+
+	newDockerClient = func() (interfaces.Client, error) {
+		return mockCli, nil
+	}
+
+	assert.NoError(t, Clean(7*24*time.Hour))
+	assert.NoDirExists(t, oldCache)
+	assert.DirExists(t, newCache)
+}
@@ -0,0 +1,229 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+const (
+	JobChangeNewlyFailing = "newly failing"
+	JobChangeFixed        = "fixed"
+	JobChangeAdded        = "added"
+	JobChangeRemoved      = "removed"
+)
+
+type RunComparison struct {
+	A    string          `json:"a"`
+	B    string          `json:"b"`
+	Jobs []JobComparison `json:"jobs"`
+}
+
+type JobComparison struct {
+	Name          string        `json:"name"`
+	StatusA       string        `json:"statusA,omitempty"`
+	StatusB       string        `json:"statusB,omitempty"`
+	Change        string        `json:"change,omitempty"`
+	DurationA     time.Duration `json:"durationA"`
+	DurationB     time.Duration `json:"durationB"`
+	DurationDelta time.Duration `json:"durationDelta"`
+	DigestA       string        `json:"digestA,omitempty"`
+	DigestB       string        `json:"digestB,omitempty"`
+	DigestChanged bool          `json:"digestChanged"`
+	ArtifactSizeA int64         `json:"artifactSizeA"`
+	ArtifactSizeB int64         `json:"artifactSizeB"`
+}
+
+// Compare prints the differences between two recorded runs: job duration
+// deltas, newly failing and fixed jobs, image digest and artifact size
+// changes.
+func Compare(a, b, output string) error {
+	if output != "text" && output != "json" {
+		err := fmt.Errorf("unsupported output format: %s", output)
+		fmt.Println(err)
+		return err
+	}
+
+	runA, err := loadRun(a)
+
+	if err != nil {
+		err = fmt.Errorf("run %s not found: %w", a, err)
+		fmt.Println(err)
+		return err
+	}
+
+	runB, err := loadRun(b)
+
+	if err != nil {
+		err = fmt.Errorf("run %s not found: %w", b, err)
+		fmt.Println(err)
+		return err
+	}
+
+	comparison := compareRuns(runA, runB)
+
+	if output == "json" {
+		b, err := json.MarshalIndent(comparison, "", "  ")
+
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(string(b))
+		return nil
+	}
+
+	printComparison(comparison)
+
+	return nil
+}
+
+// compareRuns matches the jobs of both runs by name, jobs of run b keep
+// their order and removed jobs are listed last.
+func compareRuns(a, b RunMetadata) RunComparison {
+	comparison := RunComparison{A: a.ID, B: b.ID, Jobs: []JobComparison{}}
+
+	jobsA := map[string]JobSnapshot{}
+
+	for _, job := range a.Jobs {
+		jobsA[job.Name] = job
+	}
+
+	seen := map[string]bool{}
+
+	for _, jobB := range b.Jobs {
+		seen[jobB.Name] = true
+
+		jobA, ok := jobsA[jobB.Name]
+
+		if !ok {
+			comparison.Jobs = append(comparison.Jobs, JobComparison{
+				Name:          jobB.Name,
+				StatusB:       jobB.Status,
+				Change:        JobChangeAdded,
+				DurationB:     jobDuration(jobB),
+				DigestB:       jobB.ImageDigest,
+				ArtifactSizeB: artifactSize(jobB),
+			})
+
+			continue
+		}
+
+		comparison.Jobs = append(comparison.Jobs, compareJobs(jobA, jobB))
+	}
+
+	for _, jobA := range a.Jobs {
+		if seen[jobA.Name] {
+			continue
+		}
+
+		comparison.Jobs = append(comparison.Jobs, JobComparison{
+			Name:          jobA.Name,
+			StatusA:       jobA.Status,
+			Change:        JobChangeRemoved,
+			DurationA:     jobDuration(jobA),
+			DigestA:       jobA.ImageDigest,
+			ArtifactSizeA: artifactSize(jobA),
+		})
+	}
+
+	return comparison
+}
+
+func compareJobs(a, b JobSnapshot) JobComparison {
+	job := JobComparison{
+		Name:          b.Name,
+		StatusA:       a.Status,
+		StatusB:       b.Status,
+		DurationA:     jobDuration(a),
+		DurationB:     jobDuration(b),
+		DigestA:       a.ImageDigest,
+		DigestB:       b.ImageDigest,
+		DigestChanged: a.ImageDigest != "" && b.ImageDigest != "" && a.ImageDigest != b.ImageDigest,
+		ArtifactSizeA: artifactSize(a),
+		ArtifactSizeB: artifactSize(b),
+	}
+
+	job.DurationDelta = job.DurationB - job.DurationA
+
+	if a.Status != JobStatusFailed && b.Status == JobStatusFailed {
+		job.Change = JobChangeNewlyFailing
+	} else if a.Status == JobStatusFailed && b.Status != JobStatusFailed {
+		job.Change = JobChangeFixed
+	}
+
+	return job
+}
+
+func jobDuration(job JobSnapshot) time.Duration {
+	if job.StartedAt.IsZero() || job.FinishedAt.Before(job.StartedAt) {
+		return 0
+	}
+
+	return job.FinishedAt.Sub(job.StartedAt)
+}
+
+func artifactSize(job JobSnapshot) int64 {
+	var size int64
+
+	for _, artifact := range job.Artifacts {
+		size += artifact.Size
+	}
+
+	return size
+}
+
+func printComparison(comparison RunComparison) {
+	fmt.Printf("Comparing %s -> %s\n", comparison.A, comparison.B)
+
+	for _, job := range comparison.Jobs {
+		switch job.Change {
+		case JobChangeAdded:
+			color.Set(color.FgGreen)
+			fmt.Printf("+ job %s (%s)\n", job.Name, job.StatusB)
+			color.Unset()
+
+			continue
+		case JobChangeRemoved:
+			color.Set(color.FgRed)
+			fmt.Printf("- job %s (%s)\n", job.Name, job.StatusA)
+			color.Unset()
+
+			continue
+		case JobChangeNewlyFailing:
+			color.Set(color.FgRed)
+			fmt.Printf("~ job %s: %s -> %s (%s)\n", job.Name, job.StatusA, job.StatusB, job.Change)
+			color.Unset()
+		case JobChangeFixed:
+			color.Set(color.FgGreen)
+			fmt.Printf("~ job %s: %s -> %s (%s)\n", job.Name, job.StatusA, job.StatusB, job.Change)
+			color.Unset()
+		default:
+			fmt.Printf("  job %s: %s\n", job.Name, job.StatusB)
+		}
+
+		fmt.Printf("    duration: %s -> %s (%s)\n", job.DurationA.Round(time.Millisecond), job.DurationB.Round(time.Millisecond), signedDuration(job.DurationDelta))
+
+		if job.DigestChanged {
+			color.Set(color.FgYellow)
+			fmt.Printf("    image digest: %s -> %s\n", job.DigestA, job.DigestB)
+			color.Unset()
+		}
+
+		if job.ArtifactSizeA != job.ArtifactSizeB {
+			fmt.Printf("    artifacts: %s -> %s\n", formatBytes(uint64(job.ArtifactSizeA)), formatBytes(uint64(job.ArtifactSizeB)))
+		}
+	}
+}
+
+func signedDuration(d time.Duration) string {
+	d = d.Round(time.Millisecond)
+
+	if d >= 0 {
+		return "+" + d.String()
+	}
+
+	return d.String()
+}
@@ -0,0 +1,59 @@
+package runner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareRunsReportsStatusDurationDigestAndArtifactChanges(t *testing.T) {
+	start := time.Date(2022, 5, 15, 10, 0, 0, 0, time.UTC)
+
+	a := RunMetadata{
+		ID: "a",
+		Jobs: []JobSnapshot{
+			{Name: "build", Status: JobStatusSuccess, ImageDigest: "golang@sha256:1", StartedAt: start, FinishedAt: start.Add(10 * time.Second), Artifacts: []ArtifactFile{{Path: "app", Size: 100}}},
+			{Name: "test", Status: JobStatusFailed, StartedAt: start, FinishedAt: start.Add(5 * time.Second)},
+			{Name: "lint", Status: JobStatusSuccess},
+		},
+	}
+
+	b := RunMetadata{
+		ID: "b",
+		Jobs: []JobSnapshot{
+			{Name: "build", Status: JobStatusFailed, ImageDigest: "golang@sha256:2", StartedAt: start, FinishedAt: start.Add(12 * time.Second), Artifacts: []ArtifactFile{{Path: "app", Size: 150}, {Path: "app.sum", Size: 10}}},
+			{Name: "test", Status: JobStatusSuccess, StartedAt: start, FinishedAt: start.Add(3 * time.Second)},
+			{Name: "deploy", Status: JobStatusSuccess},
+		},
+	}
+
+	comparison := compareRuns(a, b)
+
+	assert.Equal(t, 4, len(comparison.Jobs))
+
+	build := comparison.Jobs[0]
+	assert.Equal(t, JobChangeNewlyFailing, build.Change)
+	assert.Equal(t, 2*time.Second, build.DurationDelta)
+	assert.Equal(t, true, build.DigestChanged)
+	assert.Equal(t, int64(100), build.ArtifactSizeA)
+	assert.Equal(t, int64(160), build.ArtifactSizeB)
+
+	test := comparison.Jobs[1]
+	assert.Equal(t, JobChangeFixed, test.Change)
+	assert.Equal(t, -2*time.Second, test.DurationDelta)
+	assert.Equal(t, false, test.DigestChanged)
+
+	assert.Equal(t, "deploy", comparison.Jobs[2].Name)
+	assert.Equal(t, JobChangeAdded, comparison.Jobs[2].Change)
+	assert.Equal(t, "lint", comparison.Jobs[3].Name)
+	assert.Equal(t, JobChangeRemoved, comparison.Jobs[3].Change)
+}
+
+func TestCompareReturnsErrorForUnknownRun(t *testing.T) {
+	t.Setenv("PIN_HOME", t.TempDir())
+
+	err := Compare("missing-a", "missing-b", "text")
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,118 @@
+package runner
+
+import (
+	"os"
+	"sort"
+)
+
+// CompleteRunIDs returns the ids of the recorded runs, newest first, for
+// shell completion.
+func CompleteRunIDs() []string {
+	dir, err := runsDir()
+
+	if err != nil {
+		return nil
+	}
+
+	entries, err := os.ReadDir(dir)
+
+	if err != nil {
+		return nil
+	}
+
+	ids := []string{}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			ids = append(ids, entry.Name())
+		}
+	}
+
+	// run ids start with their start time
+	sort.Sort(sort.Reverse(sort.StringSlice(ids)))
+
+	return ids
+}
+
+// CompleteRunJobs returns the job names of a recorded run.
+func CompleteRunJobs(runID string) []string {
+	run, err := loadRun(runID)
+
+	if err != nil {
+		return nil
+	}
+
+	names := []string{}
+
+	for _, job := range run.Jobs {
+		names = append(names, job.Name)
+	}
+
+	return names
+}
+
+// CompleteJobNames returns the workflow jobs of a pipeline file,
+// pipeline.yaml in the current directory when no file is given.
+func CompleteJobNames(filepath string) []string {
+	pipeline, err := completionPipeline(filepath)
+
+	if err != nil {
+		return nil
+	}
+
+	names := []string{}
+
+	for _, job := range pipeline.Workflow {
+		names = append(names, job.Name)
+	}
+
+	return names
+}
+
+// CompleteJobTags returns the tags used by the jobs of a pipeline file,
+// pipeline.yaml in the current directory when no file is given.
+func CompleteJobTags(filepath string) []string {
+	pipeline, err := completionPipeline(filepath)
+
+	if err != nil {
+		return nil
+	}
+
+	seen := map[string]bool{}
+	tags := []string{}
+
+	for _, job := range pipeline.Workflow {
+		for _, tag := range job.Tags {
+			if !seen[tag] {
+				seen[tag] = true
+				tags = append(tags, tag)
+			}
+		}
+	}
+
+	sort.Strings(tags)
+
+	return tags
+}
+
+func completionPipeline(filepath string) (Pipeline, error) {
+	if filepath == "" {
+		filepath = initPipelineFile
+	}
+
+	b, err := os.ReadFile(filepath)
+
+	if err != nil {
+		return Pipeline{}, err
+	}
+
+	config, err := decodeConfig(b)
+
+	if err != nil {
+		return Pipeline{}, err
+	}
+
+	pipeline, _, err := parse(config)
+
+	return pipeline, err
+}
@@ -0,0 +1,46 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompleteRunIDsListsNewestRunFirst(t *testing.T) {
+	t.Setenv("PIN_HOME", t.TempDir())
+
+	for _, id := range []string{"20220515-100000-aaaaaa", "20220516-100000-bbbbbb"} {
+		err := saveRun(RunMetadata{ID: id, Jobs: []JobSnapshot{{Name: "build"}, {Name: "test"}}}, []byte("workflow: []"))
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, []string{"20220516-100000-bbbbbb", "20220515-100000-aaaaaa"}, CompleteRunIDs())
+	assert.Equal(t, []string{"build", "test"}, CompleteRunJobs("20220516-100000-bbbbbb"))
+	assert.Empty(t, CompleteRunJobs("missing"))
+}
+
+func TestCompleteJobTagsAndNamesReadPipelineFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pipeline.yaml")
+
+	err := os.WriteFile(path, []byte(`workflow:
+  - build
+  - test
+
+build:
+  image: golang:alpine
+  tags: [go, build]
+  script: go build ./...
+
+test:
+  image: golang:alpine
+  tags: [go]
+  script: go test ./...
+`), 0644)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"build", "go"}, CompleteJobTags(path))
+	assert.Equal(t, []string{"build", "test"}, CompleteJobNames(path))
+	assert.Empty(t, CompleteJobTags(filepath.Join(t.TempDir(), "missing.yaml")))
+}
@@ -0,0 +1,57 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/muhammedikinci/pin/internal/terminal"
+)
+
+// DebugOnFailure keeps the container of a failed command for debugging
+// instead of removing it right away, it is set by the cli.
+var DebugOnFailure bool
+
+// debugFailedContainer attaches a shell to the container of a failed command
+// when pin runs in a terminal, the container is removed once the shell
+// exits. Otherwise it prints how to attach one and reports that the
+// container is kept.
+func (r Runner) debugFailedContainer(currentJob Job) bool {
+	if currentJob.ScriptOutput != nil {
+		currentJob.ScriptOutput.Flush()
+	}
+
+	flushJobOutput(currentJob.Output)
+
+	if !terminal.IsTerminal(int(os.Stdin.Fd())) {
+		color.Set(color.FgYellow)
+		currentJob.InfoLog.Printf("Container kept for debugging, attach a shell with: docker exec -it %s sh", currentJob.Container.ID)
+		currentJob.InfoLog.Printf("Remove it with: docker rm -f %s", currentJob.Container.ID)
+		color.Unset()
+
+		return true
+	}
+
+	// the shell owns the terminal, the output of parallel jobs and their
+	// debug shells wait until it exits. The logger writes under the same
+	// lock so the messages are printed directly.
+	stdoutMu.Lock()
+	defer stdoutMu.Unlock()
+
+	prefix := jobPrefix(&currentJob)
+
+	color.Set(color.FgYellow)
+	fmt.Printf("%sCommand failed, attaching a shell to the container, exit it to continue\n", prefix)
+	color.Unset()
+
+	exitCode, err := r.attachShell(&currentJob, "sh")
+
+	if err != nil {
+		fmt.Printf("%sDebug shell failed: %s\n", prefix, err)
+		return false
+	}
+
+	fmt.Printf("%sDebug shell exited with code %d\n", prefix, exitCode)
+
+	return false
+}
@@ -0,0 +1,53 @@
+package runner
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/muhammedikinci/pin/internal/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDebugOnFailureKeepsTheContainerWithoutTerminal(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	stdin, err := os.Open(os.DevNull)
+	assert.NoError(t, err)
+
+	defer stdin.Close()
+	defer func(f *os.File) { os.Stdin = f }(os.Stdin)
+
+	os.Stdin = stdin
+
+	var logs bytes.Buffer
+
+	// no StopContainer or RemoveContainer calls are expected
+	job := Job{
+		Name:             "test",
+		ContainerManager: mocks.NewMockContainerManager(ctrl),
+		InfoLog:          log.New(&logs, "", 0),
+	}
+	job.Container.ID = "abc"
+
+	failure := errors.New("command execution failed")
+	r := Runner{debugOnFailure: true}
+
+	assert.Equal(t, failure, r.removeFailedContainer(job, failure))
+	assert.Contains(t, logs.String(), "docker exec -it abc sh")
+}
+
+func TestDebugOnFailureCanNotBeUsedWithOutput(t *testing.T) {
+	defer func() { DebugOnFailure = false }()
+
+	DebugOnFailure = true
+
+	err := Apply("", []string{"pipeline.yaml"}, ApplyOptions{Output: "json"})
+
+	assert.EqualError(t, err, "VALIDATION: --debug-on-failure can not be used with --detach or --output")
+}
@@ -0,0 +1,120 @@
+package runner
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Deprecation marks a config field that is replaced by another one. The old
+// field keeps working until RemovedIn, the parser reads it as Replacement
+// and lint --fix renames it.
+type Deprecation struct {
+	Field       string
+	Replacement string
+	RemovedIn   string
+	// Pipeline is true for top level fields, otherwise the field is a job
+	// field.
+	Pipeline bool
+}
+
+// deprecatedFields lists the deprecated fields, add an entry here instead of
+// removing a field.
+var deprecatedFields = []Deprecation{}
+
+func (d Deprecation) message() string {
+	return fmt.Sprintf("%s is deprecated and will be removed in %s, use %s instead", d.Field, d.RemovedIn, d.Replacement)
+}
+
+func (d Deprecation) warning(job string) Warning {
+	return Warning{
+		Job:         job,
+		Field:       strings.ToLower(d.Field),
+		Message:     d.message(),
+		Deprecated:  true,
+		Replacement: d.Replacement,
+		RemovedIn:   d.RemovedIn,
+	}
+}
+
+func deprecation(field string, pipeline bool) (Deprecation, bool) {
+	for _, d := range deprecatedFields {
+		if d.Pipeline == pipeline && strings.EqualFold(d.Field, field) {
+			return d, true
+		}
+	}
+
+	return Deprecation{}, false
+}
+
+// migrateJobFields copies deprecated job fields to their replacements when
+// the replacement is not set, the job map keys are lower-cased.
+func migrateJobFields(configMap map[string]interface{}) {
+	for _, d := range deprecatedFields {
+		if d.Pipeline {
+			continue
+		}
+
+		old, replacement := strings.ToLower(d.Field), strings.ToLower(d.Replacement)
+
+		if value, ok := configMap[old]; ok {
+			if _, ok := configMap[replacement]; !ok {
+				configMap[replacement] = value
+			}
+		}
+	}
+}
+
+func migratePipelineFields(config map[string]interface{}) {
+	for _, d := range deprecatedFields {
+		if !d.Pipeline {
+			continue
+		}
+
+		old, replacement := strings.ToLower(d.Field), strings.ToLower(d.Replacement)
+
+		if value, ok := config[old]; ok {
+			if _, ok := config[replacement]; !ok {
+				config[replacement] = value
+			}
+		}
+	}
+}
+
+// fixDeprecatedFields renames deprecated keys in the document, a key is left
+// alone when its replacement is already there.
+func fixDeprecatedFields(root *yaml.Node) []string {
+	changes := []string{}
+
+	if root.Kind != yaml.DocumentNode || len(root.Content) == 0 || root.Content[0].Kind != yaml.MappingNode {
+		return changes
+	}
+
+	changes = append(changes, renameDeprecatedKeys(root.Content[0], "", true)...)
+
+	for _, job := range jobNodes(root) {
+		changes = append(changes, renameDeprecatedKeys(job.mapping, job.name+": ", false)...)
+	}
+
+	return changes
+}
+
+func renameDeprecatedKeys(mapping *yaml.Node, prefix string, pipeline bool) []string {
+	changes := []string{}
+
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		key := mapping.Content[i]
+
+		d, ok := deprecation(key.Value, pipeline)
+
+		if !ok || mappingValue(mapping, d.Replacement) != nil {
+			continue
+		}
+
+		changes = append(changes, fmt.Sprintf("%s%s renamed to %s", prefix, key.Value, d.Replacement))
+		key.Value = d.Replacement
+	}
+
+	return changes
+}
@@ -0,0 +1,74 @@
+package runner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const deprecatedPipeline = `workflow:
+  - build
+
+timestamps: true
+
+build:
+  image: golang:alpine3.15
+  ignore:
+    - node_modules
+  copyFiles: true
+  script:
+    - go build ./...
+`
+
+func withDeprecations(t *testing.T, deprecations []Deprecation) {
+	previous := deprecatedFields
+	deprecatedFields = deprecations
+
+	t.Cleanup(func() { deprecatedFields = previous })
+}
+
+func TestDeprecatedFieldsWarnAndKeepWorking(t *testing.T) {
+	withDeprecations(t, []Deprecation{
+		{Field: "timestamps", Replacement: "logsWithTime", RemovedIn: "v2.0.0", Pipeline: true},
+		{Field: "ignore", Replacement: "copyIgnore", RemovedIn: "v2.0.0"},
+	})
+
+	config, _ := decodeConfig([]byte(deprecatedPipeline))
+
+	pipeline, warnings, err := parse(config)
+
+	assert.Equal(t, err, nil)
+	assert.True(t, pipeline.LogsWithTime)
+	assert.Equal(t, []string{"node_modules"}, pipeline.Workflow[0].CopyIgnore)
+	assert.Equal(t, []Warning{
+		{
+			Field:       "timestamps",
+			Message:     "timestamps is deprecated and will be removed in v2.0.0, use logsWithTime instead",
+			Deprecated:  true,
+			Replacement: "logsWithTime",
+			RemovedIn:   "v2.0.0",
+		},
+		{
+			Job:         "build",
+			Field:       "ignore",
+			Message:     "ignore is deprecated and will be removed in v2.0.0, use copyIgnore instead",
+			Deprecated:  true,
+			Replacement: "copyIgnore",
+			RemovedIn:   "v2.0.0",
+		},
+	}, warnings)
+}
+
+func TestLintFixRenamesDeprecatedFields(t *testing.T) {
+	withDeprecations(t, []Deprecation{
+		{Field: "timestamps", Replacement: "logsWithTime", RemovedIn: "v2.0.0", Pipeline: true},
+		{Field: "ignore", Replacement: "copyIgnore", RemovedIn: "v2.0.0"},
+	})
+
+	fixed, changes, err := fixPipeline([]byte(deprecatedPipeline))
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, []string{"timestamps renamed to logsWithTime", "build: ignore renamed to copyIgnore"}, changes)
+	assert.Contains(t, string(fixed), "logsWithTime: true")
+	assert.Contains(t, string(fixed), "copyIgnore:")
+}
@@ -0,0 +1,162 @@
+package runner
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// detachedRunEnv passes the run id to the background process of a detached
+// apply, so the id printed to the user is the one the run is stored with.
+const detachedRunEnv = "PIN_RUN_ID"
+
+const (
+	runOutputFile = "output.log"
+	runPIDFile    = "pid"
+)
+
+var attachPollInterval = 500 * time.Millisecond
+
+// detach starts the same apply command again as a background process in its
+// own session, its output is written into the run directory for pin attach.
+func detach() error {
+	runID := newRunID()
+
+	dir, err := runDir(runID)
+
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	output, err := os.Create(filepath.Join(dir, runOutputFile))
+
+	if err != nil {
+		return err
+	}
+
+	defer output.Close()
+
+	executable, err := os.Executable()
+
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command(executable, detachedArgs(os.Args[1:])...)
+	cmd.Env = append(os.Environ(), detachedRunEnv+"="+runID)
+	cmd.Stdout = output
+	cmd.Stderr = output
+	cmd.SysProcAttr = detachedProcAttr()
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, runPIDFile), []byte(strconv.Itoa(cmd.Process.Pid)), 0644); err != nil {
+		return err
+	}
+
+	cmd.Process.Release()
+
+	fmt.Printf("Run ID: %s\n", runID)
+	fmt.Printf("Running in the background, use `pin attach %s` to follow the output\n", runID)
+
+	return nil
+}
+
+// detachedArgs removes the detach flag so the background process runs the
+// pipeline itself.
+func detachedArgs(args []string) []string {
+	result := []string{}
+
+	for _, arg := range args {
+		if arg == "--detach" || arg == "--detach=true" {
+			continue
+		}
+
+		result = append(result, arg)
+	}
+
+	return result
+}
+
+// Attach prints the output of a detached run and follows it until the run
+// finishes, interrupting attach leaves the run going.
+func Attach(runID string) error {
+	if err := attach(runID, os.Stdout); err != nil {
+		fmt.Println(err)
+		return err
+	}
+
+	return nil
+}
+
+func attach(runID string, w io.Writer) error {
+	return copyRunOutput(runID, w, true)
+}
+
+// copyRunOutput writes the recorded output of a detached run, with follow
+// until the run finishes.
+func copyRunOutput(runID string, w io.Writer, follow bool) error {
+	dir, err := runDir(runID)
+
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(filepath.Join(dir, runOutputFile))
+
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("run %s was not started with --detach", runID)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	reader := bufio.NewReader(f)
+
+	for {
+		if _, err := io.Copy(w, reader); err != nil || !follow {
+			return err
+		}
+
+		// run.json is written when the pipeline finished, the output is
+		// drained once more because it may have grown since the last copy
+		if finished(dir) {
+			_, err := io.Copy(w, reader)
+			return err
+		}
+
+		time.Sleep(attachPollInterval)
+	}
+}
+
+func finished(dir string) bool {
+	if _, err := os.Stat(filepath.Join(dir, "run.json")); err == nil {
+		return true
+	}
+
+	b, err := os.ReadFile(filepath.Join(dir, runPIDFile))
+
+	if err != nil {
+		return false
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(string(b)))
+
+	return err == nil && !processAlive(pid)
+}
@@ -0,0 +1,52 @@
+package runner
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetachedArgsRemovesDetachFlag(t *testing.T) {
+	args := detachedArgs([]string{"apply", "--detach", "-f", "pipeline.yaml", "--detach=true"})
+
+	assert.Equal(t, []string{"apply", "-f", "pipeline.yaml"}, args)
+}
+
+func TestAttachFollowsOutputUntilRunFinishes(t *testing.T) {
+	t.Setenv("PIN_HOME", t.TempDir())
+
+	attachPollInterval = 10 * time.Millisecond
+
+	id := newRunID()
+	dir, _ := runDir(id)
+
+	os.MkdirAll(dir, 0755)
+	os.WriteFile(filepath.Join(dir, runOutputFile), []byte("build: started\n"), 0644)
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+
+		f, _ := os.OpenFile(filepath.Join(dir, runOutputFile), os.O_APPEND|os.O_WRONLY, 0644)
+		f.WriteString("build: done\n")
+		f.Close()
+
+		saveRun(RunMetadata{ID: id}, []byte{})
+	}()
+
+	var out bytes.Buffer
+
+	assert.Equal(t, nil, attach(id, &out))
+	assert.Equal(t, "build: started\nbuild: done\n", out.String())
+}
+
+func TestAttachFailsForRunWithoutOutput(t *testing.T) {
+	t.Setenv("PIN_HOME", t.TempDir())
+
+	err := attach("20220101-000000-000000", &bytes.Buffer{})
+
+	assert.EqualError(t, err, "run 20220101-000000-000000 was not started with --detach")
+}
@@ -0,0 +1,35 @@
+//go:build !windows
+
+package runner
+
+import (
+	"os"
+	"syscall"
+)
+
+// detachedProcAttr starts the process in a new session, so it does not get
+// the hangup signal when the terminal of the user goes away.
+func detachedProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{Setsid: true}
+}
+
+func processAlive(pid int) bool {
+	process, err := os.FindProcess(pid)
+
+	if err != nil {
+		return false
+	}
+
+	return process.Signal(syscall.Signal(0)) == nil
+}
+
+// terminateProcess sends SIGTERM, the run cancels its context on it.
+func terminateProcess(pid int) error {
+	process, err := os.FindProcess(pid)
+
+	if err != nil {
+		return err
+	}
+
+	return process.Signal(syscall.SIGTERM)
+}
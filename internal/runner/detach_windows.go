@@ -0,0 +1,38 @@
+//go:build windows
+
+package runner
+
+import (
+	"os"
+	"syscall"
+)
+
+const createNewProcessGroup = 0x00000200
+
+func detachedProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{CreationFlags: createNewProcessGroup}
+}
+
+func processAlive(pid int) bool {
+	process, err := os.FindProcess(pid)
+
+	if err != nil {
+		return false
+	}
+
+	process.Release()
+
+	return true
+}
+
+// terminateProcess kills the process, windows can not deliver SIGTERM to
+// another process so its containers are left for pin prune.
+func terminateProcess(pid int) error {
+	process, err := os.FindProcess(pid)
+
+	if err != nil {
+		return err
+	}
+
+	return process.Kill()
+}
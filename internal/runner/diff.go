@@ -0,0 +1,286 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+type PipelineDiff struct {
+	WorkflowChanged bool      `json:"workflowChanged"`
+	AddedJobs       []string  `json:"addedJobs"`
+	RemovedJobs     []string  `json:"removedJobs"`
+	ChangedJobs     []JobDiff `json:"changedJobs"`
+}
+
+type JobDiff struct {
+	Name    string        `json:"name"`
+	Changes []FieldChange `json:"changes"`
+}
+
+type FieldChange struct {
+	Field   string   `json:"field"`
+	Old     string   `json:"old,omitempty"`
+	New     string   `json:"new,omitempty"`
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+}
+
+func Diff(oldFilepath, newFilepath, output string) error {
+	if output != "text" && output != "json" {
+		err := fmt.Errorf("unsupported output format: %s", output)
+		fmt.Println(err)
+		return err
+	}
+
+	oldPipeline, err := loadPipeline(oldFilepath)
+
+	if err != nil {
+		fmt.Println(err)
+		return err
+	}
+
+	newPipeline, err := loadPipeline(newFilepath)
+
+	if err != nil {
+		fmt.Println(err)
+		return err
+	}
+
+	diff := diffPipelines(oldPipeline, newPipeline)
+
+	if output == "json" {
+		b, err := json.MarshalIndent(diff, "", "  ")
+
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(string(b))
+		return nil
+	}
+
+	printDiff(diff)
+
+	return nil
+}
+
+func loadPipeline(filepath string) (Pipeline, error) {
+	if err := checkFileExists(filepath); err != nil {
+		return Pipeline{}, err
+	}
+
+	config, err := readConfig(filepath)
+
+	if err != nil {
+		return Pipeline{}, err
+	}
+
+	pipeline, _, err := parse(config)
+
+	return pipeline, err
+}
+
+func diffPipelines(oldPipeline, newPipeline Pipeline) PipelineDiff {
+	diff := PipelineDiff{
+		AddedJobs:   []string{},
+		RemovedJobs: []string{},
+		ChangedJobs: []JobDiff{},
+	}
+
+	oldJobs := map[string]*Job{}
+	oldNames := []string{}
+
+	for _, job := range oldPipeline.Workflow {
+		oldJobs[job.Name] = job
+		oldNames = append(oldNames, job.Name)
+	}
+
+	newNames := []string{}
+
+	for _, job := range newPipeline.Workflow {
+		newNames = append(newNames, job.Name)
+
+		oldJob, ok := oldJobs[job.Name]
+
+		if !ok {
+			diff.AddedJobs = append(diff.AddedJobs, job.Name)
+			continue
+		}
+
+		if changes := diffJobs(oldJob, job); len(changes) > 0 {
+			diff.ChangedJobs = append(diff.ChangedJobs, JobDiff{Name: job.Name, Changes: changes})
+		}
+	}
+
+	_, removed := diffStrings(oldNames, newNames)
+	diff.RemovedJobs = append(diff.RemovedJobs, removed...)
+	diff.WorkflowChanged = !reflect.DeepEqual(oldNames, newNames)
+
+	return diff
+}
+
+func diffJobs(oldJob, newJob *Job) []FieldChange {
+	changes := []FieldChange{}
+
+	scalars := []struct {
+		field    string
+		old, new interface{}
+	}{
+		{"description", oldJob.Description, newJob.Description},
+		{"image", oldJob.Image, newJob.Image},
+		{"dockerfile", oldJob.Dockerfile, newJob.Dockerfile},
+		{"target", oldJob.Target, newJob.Target},
+		{"workdir", oldJob.WorkDir, newJob.WorkDir},
+		{"copyFiles", oldJob.CopyFiles, newJob.CopyFiles},
+		{"mountFiles", oldJob.MountFiles, newJob.MountFiles},
+		{"soloExecution", oldJob.SoloExecution, newJob.SoloExecution},
+		{"parallel", oldJob.IsParallel, newJob.IsParallel},
+		{"minSuccess", oldJob.MinSuccess, newJob.MinSuccess},
+		{"privileged", oldJob.Privileged, newJob.Privileged},
+		{"detach", oldJob.Detach, newJob.Detach},
+	}
+
+	for _, s := range scalars {
+		if s.old != s.new {
+			changes = append(changes, FieldChange{
+				Field: s.field,
+				Old:   fmt.Sprint(s.old),
+				New:   fmt.Sprint(s.new),
+			})
+		}
+	}
+
+	lists := []struct {
+		field    string
+		old, new []string
+	}{
+		{"tags", oldJob.Tags, newJob.Tags},
+		{"imageFallbacks", oldJob.ImageFallbacks, newJob.ImageFallbacks},
+		{"expects", oldJob.Expects, newJob.Expects},
+		{"script", oldJob.Script, newJob.Script},
+		{"env", oldJob.Env, newJob.Env},
+		{"envPassthrough", oldJob.EnvPassthrough, newJob.EnvPassthrough},
+		{"port", portStrings(oldJob.Port), portStrings(newJob.Port)},
+		{"copyIgnore", oldJob.CopyIgnore, newJob.CopyIgnore},
+		{"copyInclude", oldJob.CopyInclude, newJob.CopyInclude},
+		{"capAdd", oldJob.CapAdd, newJob.CapAdd},
+		{"capDrop", oldJob.CapDrop, newJob.CapDrop},
+	}
+
+	for _, l := range lists {
+		if reflect.DeepEqual(l.old, l.new) {
+			continue
+		}
+
+		added, removed := diffStrings(l.old, l.new)
+
+		changes = append(changes, FieldChange{
+			Field:   l.field,
+			Added:   added,
+			Removed: removed,
+		})
+	}
+
+	return changes
+}
+
+// diffStrings returns the values only found in new and the values only
+// found in old, keeping their original order.
+func diffStrings(old, new []string) ([]string, []string) {
+	added := []string{}
+	removed := []string{}
+
+	oldSet := map[string]bool{}
+	newSet := map[string]bool{}
+
+	for _, v := range old {
+		oldSet[v] = true
+	}
+
+	for _, v := range new {
+		newSet[v] = true
+
+		if !oldSet[v] {
+			added = append(added, v)
+		}
+	}
+
+	for _, v := range old {
+		if !newSet[v] {
+			removed = append(removed, v)
+		}
+	}
+
+	return added, removed
+}
+
+func portStrings(ports []Port) []string {
+	arr := make([]string, len(ports))
+
+	for i, port := range ports {
+		arr[i] = port.Out + ":" + port.In
+	}
+
+	return arr
+}
+
+func printDiff(diff PipelineDiff) {
+	if !diff.WorkflowChanged && len(diff.ChangedJobs) == 0 {
+		fmt.Println("No semantic changes")
+		return
+	}
+
+	if diff.WorkflowChanged && len(diff.AddedJobs) == 0 && len(diff.RemovedJobs) == 0 {
+		color.Set(color.FgYellow)
+		fmt.Println("~ workflow order changed")
+		color.Unset()
+	}
+
+	for _, name := range diff.AddedJobs {
+		color.Set(color.FgGreen)
+		fmt.Printf("+ job %s\n", name)
+		color.Unset()
+	}
+
+	for _, name := range diff.RemovedJobs {
+		color.Set(color.FgRed)
+		fmt.Printf("- job %s\n", name)
+		color.Unset()
+	}
+
+	for _, job := range diff.ChangedJobs {
+		color.Set(color.FgYellow)
+		fmt.Printf("~ job %s\n", job.Name)
+		color.Unset()
+
+		for _, change := range job.Changes {
+			if change.Added == nil && change.Removed == nil {
+				fmt.Printf("    %s: %s -> %s\n", change.Field, change.Old, change.New)
+				continue
+			}
+
+			if len(change.Added) == 0 && len(change.Removed) == 0 {
+				fmt.Printf("    %s: order changed\n", change.Field)
+				continue
+			}
+
+			fmt.Printf("    %s:\n", change.Field)
+
+			color.Set(color.FgRed)
+			for _, v := range change.Removed {
+				fmt.Printf("      - %s\n", strings.TrimSpace(v))
+			}
+			color.Unset()
+
+			color.Set(color.FgGreen)
+			for _, v := range change.Added {
+				fmt.Printf("      + %s\n", strings.TrimSpace(v))
+			}
+			color.Unset()
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package runner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffPipelinesReportsAddedRemovedAndChangedJobs(t *testing.T) {
+	oldPipeline := Pipeline{
+		Workflow: []*Job{
+			{Name: "build", Image: "golang:1.17", Script: []string{"go build"}},
+			{Name: "lint", Image: "golangci/golangci-lint"},
+		},
+	}
+
+	newPipeline := Pipeline{
+		Workflow: []*Job{
+			{Name: "build", Image: "golang:1.18", Script: []string{"go build ./..."}, Port: []Port{{Out: "8080", In: "80"}}},
+			{Name: "deploy", Image: "alpine"},
+		},
+	}
+
+	diff := diffPipelines(oldPipeline, newPipeline)
+
+	assert.Equal(t, true, diff.WorkflowChanged)
+	assert.Equal(t, []string{"deploy"}, diff.AddedJobs)
+	assert.Equal(t, []string{"lint"}, diff.RemovedJobs)
+	assert.Equal(t, 1, len(diff.ChangedJobs))
+
+	changes := diff.ChangedJobs[0].Changes
+
+	assert.Equal(t, FieldChange{Field: "image", Old: "golang:1.17", New: "golang:1.18"}, changes[0])
+	assert.Equal(t, FieldChange{Field: "script", Added: []string{"go build ./..."}, Removed: []string{"go build"}}, changes[1])
+	assert.Equal(t, FieldChange{Field: "port", Added: []string{"8080:80"}, Removed: []string{}}, changes[2])
+}
+
+func TestDiffPipelinesWithSamePipelineReturnsNoChanges(t *testing.T) {
+	pipeline := Pipeline{
+		Workflow: []*Job{
+			{Name: "build", Image: "golang:1.18", Script: []string{"go build"}},
+		},
+	}
+
+	diff := diffPipelines(pipeline, pipeline)
+
+	assert.Equal(t, false, diff.WorkflowChanged)
+	assert.Equal(t, 0, len(diff.AddedJobs))
+	assert.Equal(t, 0, len(diff.RemovedJobs))
+	assert.Equal(t, 0, len(diff.ChangedJobs))
+}
@@ -0,0 +1,157 @@
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/docker/docker/client"
+	"github.com/muhammedikinci/pin/internal/interfaces"
+	"github.com/muhammedikinci/pin/internal/retry_client"
+	"github.com/muhammedikinci/pin/internal/timeout_client"
+)
+
+// Docker configures how the docker daemon is called, a job can override the
+// fields of the pipeline.
+type Docker struct {
+	Retry   *DockerRetry
+	Timeout *time.Duration
+}
+
+// DockerRetry retries container create, start and exec calls that failed
+// with a transient daemon error, Attempts counts the first call too.
+type DockerRetry struct {
+	Attempts int
+	Delay    time.Duration
+}
+
+func getDocker(docker interface{}) (*Docker, error) {
+	if docker == nil {
+		return nil, nil
+	}
+
+	dockerMap := getStringMap(docker)
+
+	if len(dockerMap) == 0 {
+		return nil, errors.New("docker must be a mapping")
+	}
+
+	retry, err := getDockerRetry(dockerMap["retry"])
+
+	if err != nil {
+		return nil, err
+	}
+
+	timeout, err := getDuration(dockerMap["timeout"])
+
+	if err != nil {
+		return nil, err
+	}
+
+	if timeout != nil && *timeout <= 0 {
+		return nil, fmt.Errorf("invalid timeout: %v", dockerMap["timeout"])
+	}
+
+	return &Docker{Retry: retry, Timeout: timeout}, nil
+}
+
+// mergeDocker returns the pipeline configuration with the fields the job
+// sets replaced.
+func mergeDocker(pipeline, job *Docker) *Docker {
+	merged := Docker{}
+
+	if pipeline != nil {
+		merged = *pipeline
+	}
+
+	if job == nil {
+		return &merged
+	}
+
+	if job.Retry != nil {
+		merged.Retry = job.Retry
+	}
+
+	if job.Timeout != nil {
+		merged.Timeout = job.Timeout
+	}
+
+	return &merged
+}
+
+func getDockerRetry(retry interface{}) (*DockerRetry, error) {
+	if retry == nil || retry == false {
+		return nil, nil
+	}
+
+	config := &DockerRetry{Attempts: 3, Delay: time.Second}
+
+	if retry == true {
+		return config, nil
+	}
+
+	if attempts, ok := retry.(int); ok {
+		config.Attempts = attempts
+	} else if retryMap := getStringMap(retry); len(retryMap) > 0 {
+		if attempts, ok := retryMap["attempts"]; ok {
+			if config.Attempts, ok = attempts.(int); !ok {
+				return nil, fmt.Errorf("invalid retry attempts: %v", attempts)
+			}
+		}
+
+		delay, err := getDuration(retryMap["delay"])
+
+		if err != nil {
+			return nil, err
+		}
+
+		if delay != nil {
+			config.Delay = *delay
+		}
+	} else {
+		return nil, fmt.Errorf("invalid retry: %v", retry)
+	}
+
+	if config.Attempts < 1 {
+		return nil, fmt.Errorf("invalid retry attempts: %d", config.Attempts)
+	}
+
+	return config, nil
+}
+
+// newDockerClient is only called once a job that runs in a container is
+// about to run, so config commands and pipelines of plugin jobs work on
+// machines without docker.
+var newDockerClient = func() (interfaces.Client, error) {
+	return client.NewClientWithOpts()
+}
+
+// needsDocker reports whether any job runs in a container, plugin jobs run
+// on the host.
+func needsDocker(jobs []*Job) bool {
+	for _, job := range jobs {
+		if job.Uses == "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// dockerClient wraps the client with the configured timeout and retry
+// layers, every retried call gets its own deadline.
+func (r Runner) dockerClient(cli interfaces.Client, docker *Docker) interfaces.Client {
+	if docker == nil {
+		return cli
+	}
+
+	if docker.Timeout != nil {
+		cli = timeout_client.NewTimeoutClient(cli, *docker.Timeout)
+	}
+
+	if docker.Retry != nil {
+		cli = retry_client.NewRetryClient(cli, docker.Retry.Attempts, docker.Retry.Delay, r.pipelineLogger())
+	}
+
+	return cli
+}
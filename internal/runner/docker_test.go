@@ -0,0 +1,50 @@
+package runner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDockerRetry(t *testing.T) {
+	retry, err := getDockerRetry(true)
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, &DockerRetry{Attempts: 3, Delay: time.Second}, retry)
+
+	retry, err = getDockerRetry(map[string]interface{}{"attempts": 5, "delay": "200ms"})
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, &DockerRetry{Attempts: 5, Delay: 200 * time.Millisecond}, retry)
+
+	retry, err = getDockerRetry(false)
+
+	assert.Equal(t, err, nil)
+	assert.Nil(t, retry)
+
+	_, err = getDockerRetry(0)
+
+	assert.EqualError(t, err, "invalid retry attempts: 0")
+}
+
+func TestGetDockerTimeoutAndJobOverride(t *testing.T) {
+	pipeline, err := getDocker(map[string]interface{}{"timeout": "2m", "retry": true})
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, 2*time.Minute, *pipeline.Timeout)
+
+	job, err := getDocker(map[string]interface{}{"timeout": 30})
+
+	assert.Equal(t, err, nil)
+
+	merged := mergeDocker(pipeline, job)
+
+	assert.Equal(t, 30*time.Second, *merged.Timeout)
+	assert.Equal(t, pipeline.Retry, merged.Retry)
+	assert.Equal(t, 2*time.Minute, *pipeline.Timeout)
+
+	_, err = getDocker(map[string]interface{}{"timeout": "-1s"})
+
+	assert.EqualError(t, err, "invalid timeout: -1s")
+}
@@ -0,0 +1,94 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/docker/docker/client"
+	"github.com/fatih/color"
+	"github.com/muhammedikinci/pin/internal/container_manager"
+)
+
+// Down stops and removes the containers that jobs with detach left running
+// in a run, containers that are already gone are skipped.
+func Down(runID string) error {
+	if err := down(runID); err != nil {
+		fmt.Println(err)
+		return err
+	}
+
+	return nil
+}
+
+func down(runID string) error {
+	run, err := loadRun(runID)
+
+	if err != nil {
+		return fmt.Errorf("run %s not found: %w", runID, err)
+	}
+
+	detached := false
+
+	for _, job := range run.Jobs {
+		if job.DetachedContainer != "" {
+			detached = true
+		}
+	}
+
+	if !detached {
+		fmt.Printf("Run %s has no detached containers\n", runID)
+		return nil
+	}
+
+	cli, err := newDockerClient()
+
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	removed := 0
+
+	for i := range run.Jobs {
+		job := &run.Jobs[i]
+
+		if job.DetachedContainer == "" {
+			continue
+		}
+
+		infoLog := log.New(os.Stdout, fmt.Sprintf("%s %s ", glyph(glyphJob), job.Name), 0)
+		containerManager := container_manager.NewContainerManager(cli, infoLog)
+
+		err := containerManager.StopContainer(ctx, job.DetachedContainer, nil)
+
+		if err == nil {
+			err = containerManager.RemoveContainer(ctx, job.DetachedContainer, false)
+		}
+
+		if err != nil && !client.IsErrNotFound(err) {
+			return err
+		}
+
+		if err != nil {
+			infoLog.Println("Container is already removed")
+		} else {
+			removed++
+		}
+
+		job.DetachedContainer = ""
+
+		// the run is saved after every container so a failing one does not
+		// leave the removed ones recorded
+		if err := writeRunMetadata(run); err != nil {
+			return err
+		}
+	}
+
+	color.Set(color.FgGreen)
+	fmt.Printf("Run %s is down, %d containers removed\n", runID, removed)
+	color.Unset()
+
+	return nil
+}
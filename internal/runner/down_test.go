@@ -0,0 +1,70 @@
+package runner
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/docker/docker/errdefs"
+	"github.com/golang/mock/gomock"
+	"github.com/muhammedikinci/pin/internal/interfaces"
+	"github.com/muhammedikinci/pin/internal/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewJobSnapshotRecordsDetachedContainer(t *testing.T) {
+	job := &Job{Name: "web", Detach: true, Status: JobStatusSuccess}
+	job.Container.ID = "abc"
+
+	assert.Equal(t, "abc", newJobSnapshot(job).DetachedContainer)
+
+	job.Status = JobStatusFailed
+
+	assert.Equal(t, "", newJobSnapshot(job).DetachedContainer)
+}
+
+func TestDownRemovesDetachedContainersAndClearsThem(t *testing.T) {
+	t.Setenv("PIN_HOME", t.TempDir())
+
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	mockClient := mocks.NewMockClient(ctrl)
+
+	defer func(f func() (interfaces.Client, error)) { newDockerClient = f }(newDockerClient)
+
+	newDockerClient = func() (interfaces.Client, error) {
+		return mockClient, nil
+	}
+
+	gomock.InOrder(
+		mockClient.EXPECT().ContainerStop(gomock.Any(), "web-container", nil).Return(nil),
+		mockClient.EXPECT().ContainerRemove(gomock.Any(), "web-container", gomock.Any()).Return(nil),
+		mockClient.EXPECT().ContainerStop(gomock.Any(), "api-container", nil).Return(errdefs.NotFound(errors.New("no such container"))),
+	)
+
+	err := saveRun(RunMetadata{ID: "20220515-100000-aaaaaa", Jobs: []JobSnapshot{
+		{Name: "build"},
+		{Name: "web", DetachedContainer: "web-container"},
+		{Name: "api", DetachedContainer: "api-container"},
+	}}, []byte("workflow: []"))
+	assert.NoError(t, err)
+
+	assert.NoError(t, down("20220515-100000-aaaaaa"))
+
+	run, err := loadRun("20220515-100000-aaaaaa")
+	assert.NoError(t, err)
+
+	for _, job := range run.Jobs {
+		assert.Equal(t, "", job.DetachedContainer)
+	}
+
+	// nothing is left to stop, docker is not needed
+	assert.NoError(t, down("20220515-100000-aaaaaa"))
+}
+
+func TestDownReturnsErrorForUnknownRun(t *testing.T) {
+	t.Setenv("PIN_HOME", t.TempDir())
+
+	assert.Error(t, down("missing"))
+}
@@ -0,0 +1,313 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+type preflightCheck struct {
+	Job     string
+	Message string
+	Err     error
+}
+
+// dryRun prints the execution plan and a readiness checklist without
+// creating any container, it fails when one of the checks fails.
+func (r *Runner) dryRun(pipeline Pipeline) error {
+	r.ctx = context.Background()
+
+	printPlan(pipeline)
+
+	checks := preflightChecks(pipeline)
+
+	if !needsDocker(pipeline.Workflow) {
+		return printPreflightChecks(checks)
+	}
+
+	cli, err := newDockerClient()
+
+	if err == nil {
+		r.cli = cli
+		_, err = r.cli.ServerVersion(r.ctx)
+	}
+
+	if err != nil {
+		checks = append(checks, preflightCheck{Message: "docker daemon is reachable", Err: err})
+	} else {
+		checks = append(checks, preflightCheck{Message: "docker daemon is reachable"})
+		checks = append(checks, r.imageChecks(pipeline)...)
+	}
+
+	return printPreflightChecks(checks)
+}
+
+func printPlan(pipeline Pipeline) {
+	currentPath, _ := projectDir()
+
+	fmt.Println("Execution plan:")
+
+	for _, line := range planLines(pipeline, currentPath) {
+		fmt.Println(line)
+	}
+}
+
+// planLines describes what every job would do: its image, services, ports,
+// environment with secrets masked, mounts, the files copied into it, its
+// script and what is kept after it.
+func planLines(pipeline Pipeline, currentPath string) []string {
+	lines := []string{}
+
+	if pipeline.MaxParallel > 0 {
+		lines = append(lines, fmt.Sprintf("At most %d jobs run at the same time", pipeline.MaxParallel))
+	}
+
+	if pipeline.FailFast {
+		lines = append(lines, "A failed job cancels the running jobs (failFast)")
+	}
+
+	for i, job := range pipeline.Workflow {
+		mode := "sequential"
+
+		if job.IsParallel {
+			mode = "parallel"
+		}
+
+		if job.Uses != "" {
+			lines = append(lines, fmt.Sprintf("%d. %s (plugin %s, %s)", i+1, job.Name, job.Uses, mode))
+		} else {
+			lines = append(lines, fmt.Sprintf("%d. %s (%s, %s)", i+1, job.Name, job.Image, mode))
+		}
+
+		if job.Description != "" {
+			lines = append(lines, "   "+job.Description)
+		}
+
+		if conditions := jobConditions(job); len(conditions) > 0 {
+			lines = append(lines, "   skipped when unchanged: "+strings.Join(conditions, ", "))
+		}
+
+		for _, service := range job.Services {
+			lines = append(lines, fmt.Sprintf("   service %s (%s)", service.Name, service.Image))
+		}
+
+		for _, port := range job.Port {
+			lines = append(lines, fmt.Sprintf("   port %s:%s", port.Out, port.In))
+		}
+
+		for _, v := range maskEnv(jobEnv(job)) {
+			lines = append(lines, "   env "+v)
+		}
+
+		if job.Uses != "" {
+			continue
+		}
+
+		for _, volume := range job.Volumes {
+			lines = append(lines, "   volume "+volume)
+		}
+
+		if line := copyPlan(job, currentPath); line != "" {
+			lines = append(lines, "   "+line)
+		}
+
+		for _, cmd := range job.Script {
+			lines = append(lines, "   $ "+cmd)
+		}
+
+		if job.Reports != nil {
+			lines = append(lines, "   junit reports "+strings.Join(job.Reports.JUnit, ", "))
+		}
+
+		if job.Artifacts != nil {
+			lines = append(lines, fmt.Sprintf("   artifacts %s -> %s", strings.Join(job.Artifacts.Paths, ", "), artifactsDestination(job)))
+		}
+
+		if job.Cache != nil {
+			key, err := resolveCacheKey(job.Cache.Key)
+
+			if err != nil {
+				key = job.Cache.Key
+			}
+
+			lines = append(lines, fmt.Sprintf("   cache %s (key %s)", strings.Join(job.Cache.Paths, ", "), key))
+		}
+
+		if job.Detach {
+			lines = append(lines, "   container left running, stopped by pin down")
+		}
+	}
+
+	if pipeline.SuccessCriteria != nil && pipeline.SuccessCriteria.Healthcheck != nil {
+		check := pipeline.SuccessCriteria.Healthcheck
+		lines = append(lines, fmt.Sprintf("Success criteria: %s returns %d", check.URL, check.Status))
+	}
+
+	return lines
+}
+
+// copyPlan describes how the project gets into the container, the files
+// are listed with copyIgnore and copyInclude applied like a real copy.
+func copyPlan(job *Job, currentPath string) string {
+	if job.MountFiles {
+		mode := "read-write"
+
+		if job.MountReadOnly {
+			mode = "read-only"
+		}
+
+		return fmt.Sprintf("mount %s at %s (%s)", currentPath, job.WorkDir, mode)
+	}
+
+	if !job.CopyFiles {
+		return ""
+	}
+
+	files, err := workspaceFiles(currentPath, job.CopyIgnore, job.CopyInclude)
+
+	if err != nil {
+		return fmt.Sprintf("copy files to %s (files could not be listed: %s)", job.WorkDir, err)
+	}
+
+	return fmt.Sprintf("copy %d files to %s", len(files), job.WorkDir)
+}
+
+// preflightChecks runs the checks that do not need the docker daemon.
+func preflightChecks(pipeline Pipeline) []preflightCheck {
+	checks := []preflightCheck{}
+
+	for _, job := range pipeline.Workflow {
+		if job.Uses != "" {
+			checks = append(checks, preflightCheck{
+				Job:     job.Name,
+				Message: fmt.Sprintf("plugin %s exists", job.Uses),
+				Err:     checkFileExists(job.Uses),
+			})
+		}
+
+		if job.Dockerfile != "" {
+			checks = append(checks, preflightCheck{
+				Job:     job.Name,
+				Message: fmt.Sprintf("dockerfile %s exists", job.Dockerfile),
+				Err:     checkFileExists(job.Dockerfile),
+			})
+		}
+
+		for _, port := range job.Port {
+			checks = append(checks, preflightCheck{
+				Job:     job.Name,
+				Message: fmt.Sprintf("host port %s is available", port.Out),
+				Err:     checkHostPort(port.Out),
+			})
+		}
+
+		for _, volume := range job.Volumes {
+			source := strings.Split(volume, ":")[0]
+
+			if !strings.HasPrefix(source, "/") {
+				continue
+			}
+
+			checks = append(checks, preflightCheck{
+				Job:     job.Name,
+				Message: fmt.Sprintf("volume source %s exists", source),
+				Err:     checkFileExists(source),
+			})
+		}
+
+		if job.Cache != nil {
+			_, err := resolveCacheKey(job.Cache.Key)
+
+			checks = append(checks, preflightCheck{
+				Job:     job.Name,
+				Message: "cache key files exist",
+				Err:     err,
+			})
+		}
+	}
+
+	return checks
+}
+
+func checkHostPort(port string) error {
+	listener, err := net.Listen("tcp", ":"+port)
+
+	if err != nil {
+		return fmt.Errorf("port %s is in use", port)
+	}
+
+	return listener.Close()
+}
+
+// imageChecks looks for every job and service image locally first, then
+// asks the registry whether it can be pulled.
+func (r Runner) imageChecks(pipeline Pipeline) []preflightCheck {
+	checks := []preflightCheck{}
+
+	for _, job := range pipeline.Workflow {
+		images := []string{}
+
+		if job.Dockerfile == "" && job.Uses == "" {
+			images = append(images, job.Image)
+		}
+
+		for _, service := range job.Services {
+			images = append(images, service.Image)
+		}
+
+		for _, image := range images {
+			checks = append(checks, r.imageCheck(job.Name, image))
+		}
+	}
+
+	return checks
+}
+
+func (r Runner) imageCheck(job, image string) preflightCheck {
+	if _, _, err := r.cli.ImageInspectWithRaw(r.ctx, image); err == nil {
+		return preflightCheck{Job: job, Message: fmt.Sprintf("image %s is available locally", image)}
+	}
+
+	_, err := r.cli.DistributionInspect(r.ctx, image, "")
+
+	return preflightCheck{Job: job, Message: fmt.Sprintf("image %s can be pulled", image), Err: err}
+}
+
+func printPreflightChecks(checks []preflightCheck) error {
+	failed := 0
+
+	fmt.Println("\nPre-flight checks:")
+
+	for _, check := range checks {
+		prefix := ""
+
+		if check.Job != "" {
+			prefix = check.Job + ": "
+		}
+
+		if check.Err != nil {
+			failed++
+
+			color.Set(color.FgRed)
+			fmt.Printf("%s %s%s (%s)\n", glyph(glyphFailure), prefix, check.Message, check.Err)
+			color.Unset()
+
+			continue
+		}
+
+		color.Set(color.FgGreen)
+		fmt.Printf("%s %s%s\n", glyph(glyphSuccess), prefix, check.Message)
+		color.Unset()
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d pre-flight checks failed", failed)
+	}
+
+	fmt.Println("\nReady to run")
+
+	return nil
+}
@@ -0,0 +1,107 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/registry"
+	"github.com/golang/mock/gomock"
+	"github.com/muhammedikinci/pin/internal/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckHostPortReportsPortsInUse(t *testing.T) {
+	listener, err := net.Listen("tcp", ":0")
+	assert.NoError(t, err)
+
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+
+	assert.Error(t, checkHostPort(port))
+
+	listener.Close()
+
+	assert.NoError(t, checkHostPort(port))
+}
+
+func TestPreflightChecksReportMissingFiles(t *testing.T) {
+	pipeline := Pipeline{Workflow: []*Job{{
+		Name:    "test",
+		Volumes: []string{"/pin-missing-source:/data", "named:/cache"},
+		Cache:   &Cache{Key: `deps-{{ checksum "pin-missing.lock" }}`, Paths: []string{"/cache"}},
+	}}}
+
+	checks := preflightChecks(pipeline)
+
+	assert.Len(t, checks, 2)
+	assert.Error(t, checks[0].Err)
+	assert.Error(t, checks[1].Err)
+	assert.Error(t, printPreflightChecks(checks))
+}
+
+func TestImageCheckFallsBackToTheRegistry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	mockCli := mocks.NewMockClient(ctrl)
+
+	mockCli.EXPECT().ImageInspectWithRaw(gomock.Any(), "local:1").Return(types.ImageInspect{}, nil, nil)
+	mockCli.EXPECT().ImageInspectWithRaw(gomock.Any(), "remote:1").Return(types.ImageInspect{}, nil, errors.New("not found"))
+	mockCli.EXPECT().DistributionInspect(gomock.Any(), "remote:1", "").Return(registry.DistributionInspect{}, nil)
+	mockCli.EXPECT().ImageInspectWithRaw(gomock.Any(), "missing:1").Return(types.ImageInspect{}, nil, errors.New("not found"))
+	mockCli.EXPECT().DistributionInspect(gomock.Any(), "missing:1", "").Return(registry.DistributionInspect{}, errors.New("manifest unknown"))
+
+	r := Runner{ctx: context.Background(), cli: mockCli}
+
+	assert.NoError(t, r.imageCheck("test", "local:1").Err)
+	assert.NoError(t, r.imageCheck("test", "remote:1").Err)
+	assert.Error(t, r.imageCheck("test", "missing:1").Err)
+}
+
+func TestPlanLinesDescribeWhatWouldRun(t *testing.T) {
+	dir := t.TempDir()
+
+	os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main"), 0644)
+	os.WriteFile(filepath.Join(dir, "debug.log"), []byte("log"), 0644)
+
+	t.Setenv("PIN_PLAN_REGION", "eu-west-1")
+
+	pipeline := Pipeline{Workflow: []*Job{{
+		Name:           "build",
+		Image:          "golang:alpine",
+		WorkDir:        "/app",
+		CopyFiles:      true,
+		CopyIgnore:     []string{`.*\.log`},
+		Env:            []string{"API_TOKEN=secret"},
+		EnvPassthrough: []string{"PIN_PLAN_*"},
+		Port:           []Port{{Out: "8080", In: "80"}},
+		Volumes:        []string{"go-cache:/root/.cache"},
+		Script:         []string{"go build ./..."},
+		Artifacts:      &Artifacts{Paths: []string{"bin"}},
+		ResultCache:    true,
+	}, {
+		Name:       "deploy",
+		Image:      "alpine",
+		WorkDir:    "/app",
+		MountFiles: true,
+	}}}
+
+	assert.Equal(t, []string{
+		"1. build (golang:alpine, sequential)",
+		"   skipped when unchanged: resultCache",
+		"   port 8080:80",
+		"   env PIN_PLAN_REGION=eu-west-1",
+		"   env API_TOKEN=***",
+		"   volume go-cache:/root/.cache",
+		"   copy 1 files to /app",
+		"   $ go build ./...",
+		"   artifacts bin -> " + filepath.Join("artifacts", "build"),
+		"2. deploy (alpine, sequential)",
+		"   mount " + dir + " at /app (read-write)",
+	}, planLines(pipeline, dir))
+}
@@ -0,0 +1,198 @@
+package runner
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/muhammedikinci/pin/internal/glob"
+)
+
+// getEnv reads the envFile entries in order and then the env list, a later
+// definition of a variable overrides the earlier one, so env wins over
+// envFile.
+func getEnv(env interface{}, envFile interface{}) ([]string, error) {
+	if m := getStringMap(env); len(m) > 0 {
+		return []string{}, errors.New("env must be a list of KEY=VALUE entries")
+	}
+
+	merged := []string{}
+
+	files, err := getStringArray("envFile", envFile)
+
+	if err != nil {
+		return []string{}, err
+	}
+
+	for _, file := range files {
+		vars, err := readEnvFile(file)
+
+		if err != nil {
+			return []string{}, err
+		}
+
+		merged = mergeEnv(merged, vars)
+	}
+
+	list, err := getStringArray("env", env)
+
+	if err != nil {
+		return []string{}, err
+	}
+
+	vars := []string{}
+
+	for _, v := range list {
+		if !strings.Contains(v, "=") {
+			return []string{}, fmt.Errorf("invalid env entry: %s", v)
+		}
+
+		vars = append(vars, v)
+	}
+
+	return mergeEnv(merged, vars), nil
+}
+
+func readEnvFile(file string) ([]string, error) {
+	f, err := os.Open(file)
+
+	if err != nil {
+		return []string{}, err
+	}
+
+	defer f.Close()
+
+	vars, err := parseEnvFile(bufio.NewScanner(f))
+
+	if err != nil {
+		return []string{}, fmt.Errorf("%s: %w", file, err)
+	}
+
+	return vars, nil
+}
+
+// parseEnvFile understands the common dotenv format: KEY=VALUE lines, blank
+// lines, # comments, an optional export prefix and quoted values.
+func parseEnvFile(scanner *bufio.Scanner) ([]string, error) {
+	vars := []string{}
+	lineNumber := 0
+
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		line = strings.TrimPrefix(line, "export ")
+
+		key, value, found := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+
+		if !found || key == "" || strings.ContainsAny(key, " \t") {
+			return []string{}, fmt.Errorf("invalid line %d", lineNumber)
+		}
+
+		value = strings.TrimSpace(value)
+
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			if value[0] == '"' {
+				value = strings.ReplaceAll(value[1:len(value)-1], `\n`, "\n")
+			} else {
+				value = value[1 : len(value)-1]
+			}
+		} else if i := strings.Index(value, " #"); i >= 0 {
+			value = strings.TrimSpace(value[:i])
+		}
+
+		vars = append(vars, key+"="+value)
+	}
+
+	return vars, scanner.Err()
+}
+
+// mergeEnv overrides the variables of base with the ones in overrides,
+// keeping the position of the first definition.
+func mergeEnv(base, overrides []string) []string {
+	merged := append([]string{}, base...)
+	index := map[string]int{}
+
+	for i, v := range merged {
+		name, _, _ := strings.Cut(v, "=")
+		index[name] = i
+	}
+
+	for _, v := range overrides {
+		name, _, _ := strings.Cut(v, "=")
+
+		if i, ok := index[name]; ok {
+			merged[i] = v
+			continue
+		}
+
+		index[name] = len(merged)
+		merged = append(merged, v)
+	}
+
+	return merged
+}
+
+// passthroughEnv returns the host variables whose names match one of the
+// envPassthrough patterns, in the order of the host environment.
+func passthroughEnv(patterns []string, hostEnv []string) []string {
+	vars := []string{}
+
+	if len(patterns) == 0 {
+		return vars
+	}
+
+	for _, v := range hostEnv {
+		name, _, found := strings.Cut(v, "=")
+
+		if !found || name == "" {
+			continue
+		}
+
+		for _, pattern := range patterns {
+			if glob.Match(pattern, name) {
+				vars = append(vars, v)
+				break
+			}
+		}
+	}
+
+	return vars
+}
+
+// metadataEnv describes the run to the job, the variables are added after the
+// variables of the job so scripts can rely on them. They are left out of
+// jobEnv because they change with every run.
+func (r Runner) metadataEnv(currentJob *Job, workDir string) []string {
+	jobStartedAt := currentJob.StartedAt
+
+	if jobStartedAt.IsZero() {
+		jobStartedAt = time.Now()
+	}
+
+	return []string{
+		"PIN_RUN_ID=" + r.runID,
+		"PIN_PIPELINE_NAME=" + r.pipelineName,
+		"PIN_JOB_NAME=" + currentJob.Name,
+		"PIN_ATTEMPT=" + strconv.Itoa(r.attempt),
+		"PIN_WORKDIR=" + workDir,
+		"PIN_RUN_STARTED_AT=" + r.startedAt.UTC().Format(time.RFC3339),
+		"PIN_JOB_STARTED_AT=" + jobStartedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+// jobEnv is the environment of the job container: the passed through host
+// variables overridden by the variables of the job. It is resolved when the
+// job runs so host values are not recorded with the run.
+func jobEnv(currentJob *Job) []string {
+	return mergeEnv(passthroughEnv(currentJob.EnvPassthrough, os.Environ()), currentJob.Env)
+}
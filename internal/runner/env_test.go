@@ -0,0 +1,150 @@
+package runner
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseEnvFile(t *testing.T) {
+	content := `# database
+DB_HOST=localhost
+export DB_PORT=5432
+DB_NAME="pin test"
+GREETING='hello # world'
+DEBUG=true # inline comment
+
+EMPTY=
+`
+
+	vars, err := parseEnvFile(bufio.NewScanner(strings.NewReader(content)))
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, []string{
+		"DB_HOST=localhost",
+		"DB_PORT=5432",
+		"DB_NAME=pin test",
+		"GREETING=hello # world",
+		"DEBUG=true",
+		"EMPTY=",
+	}, vars)
+
+	_, err = parseEnvFile(bufio.NewScanner(strings.NewReader("NOT A VARIABLE")))
+
+	assert.EqualError(t, err, "invalid line 1")
+}
+
+func TestGetEnvOverridesEnvFileWithEnv(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, ".env")
+	second := filepath.Join(dir, ".env.ci")
+
+	os.WriteFile(first, []byte("A=1\nB=1\n"), 0644)
+	os.WriteFile(second, []byte("B=2\nC=2\n"), 0644)
+
+	env, err := getEnv([]interface{}{"C=3", "D=3"}, []interface{}{first, second})
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, []string{"A=1", "B=2", "C=3", "D=3"}, env)
+
+	_, err = getEnv(nil, filepath.Join(dir, "missing"))
+
+	assert.NotNil(t, err)
+
+	_, err = getEnv([]interface{}{"NOVALUE"}, nil)
+
+	assert.EqualError(t, err, "invalid env entry: NOVALUE")
+}
+
+func TestPassthroughEnvMatchesPatterns(t *testing.T) {
+	hostEnv := []string{"AWS_REGION=eu-west-1", "GITHUB_TOKEN=abc", "HOME=/root", "AWS_PROFILE=ci", "GITHUB_TOKEN_EXTRA=x"}
+
+	assert.Equal(t, []string{"AWS_REGION=eu-west-1", "GITHUB_TOKEN=abc", "AWS_PROFILE=ci"}, passthroughEnv([]string{"AWS_*", "GITHUB_TOKEN"}, hostEnv))
+	assert.Equal(t, []string{}, passthroughEnv(nil, hostEnv))
+}
+
+func TestJobEnvOverridesPassthroughWithEnv(t *testing.T) {
+	t.Setenv("PIN_TEST_REGION", "eu-west-1")
+	t.Setenv("PIN_TEST_PROFILE", "ci")
+
+	job := &Job{EnvPassthrough: []string{"PIN_TEST_*"}, Env: []string{"PIN_TEST_PROFILE=local", "CGO_ENABLED=0"}}
+
+	env := jobEnv(job)
+
+	assert.Contains(t, env, "PIN_TEST_REGION=eu-west-1")
+	assert.Contains(t, env, "PIN_TEST_PROFILE=local")
+	assert.Contains(t, env, "CGO_ENABLED=0")
+	assert.NotContains(t, env, "PIN_TEST_PROFILE=ci")
+}
+
+func TestPipelineEnvIsOverriddenByJobsAndCliEnv(t *testing.T) {
+	dir := t.TempDir()
+
+	os.WriteFile(filepath.Join(dir, ".env"), []byte("STAGE=dev\nREGION=eu\nLEVEL=file\n"), 0644)
+	os.WriteFile(filepath.Join(dir, ".env.job"), []byte("LEVEL=job-file\n"), 0644)
+
+	config, err := decodeConfig([]byte(`envFile: .env
+env:
+  - STAGE=ci
+  - LEVEL=pipeline
+workflow:
+  - build
+  - deploy
+build:
+  image: golang
+  envFile: .env.job
+deploy:
+  image: alpine
+  env:
+    - LEVEL=job
+`))
+	assert.NoError(t, err)
+
+	assert.NoError(t, resolveConfigPaths(config, dir))
+	assert.NoError(t, applyEnv(config, []string{"REGION=us"}))
+
+	pipeline, _, err := parse(config)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"STAGE=ci", "REGION=eu", "LEVEL=pipeline"}, pipeline.Env)
+	assert.Equal(t, []string{"STAGE=ci", "REGION=us", "LEVEL=job-file"}, pipeline.Workflow[0].Env)
+	assert.Equal(t, []string{"STAGE=ci", "REGION=us", "LEVEL=job"}, pipeline.Workflow[1].Env)
+}
+
+func TestMetadataEnvDescribesTheRun(t *testing.T) {
+	startedAt := time.Date(2022, 5, 15, 10, 15, 0, 0, time.UTC)
+
+	r := Runner{runID: "20220515-101500-a1b2c3", pipelineName: "release", attempt: 2, startedAt: startedAt}
+	job := &Job{Name: "build", StartedAt: startedAt.Add(time.Minute)}
+
+	assert.Equal(t, []string{
+		"PIN_RUN_ID=20220515-101500-a1b2c3",
+		"PIN_PIPELINE_NAME=release",
+		"PIN_JOB_NAME=build",
+		"PIN_ATTEMPT=2",
+		"PIN_WORKDIR=/app",
+		"PIN_RUN_STARTED_AT=2022-05-15T10:15:00Z",
+		"PIN_JOB_STARTED_AT=2022-05-15T10:16:00Z",
+	}, r.metadataEnv(job, "/app"))
+}
+
+func TestRunAttemptFollowsTheRerunChain(t *testing.T) {
+	t.Setenv("PIN_HOME", t.TempDir())
+
+	assert.Equal(t, 1, runAttempt(""))
+
+	// runs recorded before attempts were counted
+	assert.NoError(t, saveRun(RunMetadata{ID: "20220515-100000-aaaaaa"}, []byte("workflow: []")))
+	assert.NoError(t, saveRun(RunMetadata{ID: "20220515-110000-bbbbbb", RerunOf: "20220515-100000-aaaaaa"}, []byte("workflow: []")))
+
+	assert.Equal(t, 3, runAttempt("20220515-110000-bbbbbb"))
+
+	assert.NoError(t, saveRun(RunMetadata{ID: "20220515-120000-cccccc", RerunOf: "20220515-110000-bbbbbb", Attempt: 3}, []byte("workflow: []")))
+
+	assert.Equal(t, 4, runAttempt("20220515-120000-cccccc"))
+}
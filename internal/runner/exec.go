@@ -0,0 +1,227 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/fatih/color"
+	"github.com/muhammedikinci/pin/internal/container_manager"
+	"github.com/muhammedikinci/pin/internal/image_manager"
+	"github.com/muhammedikinci/pin/internal/terminal"
+)
+
+type ExecOptions struct {
+	// Shell is the command attached to the terminal, sh when empty.
+	Shell string
+	// Set overrides values of the pipeline, see applySet.
+	Set []string
+	// Env adds variables to every job, see applyEnv.
+	Env []string
+}
+
+// Exec starts the container of a job the way apply does, with its image,
+// env, services and project files, and attaches an interactive shell to it
+// instead of running the script. The container is removed when the shell
+// exits.
+func Exec(jobName string, filepaths []string, options ExecOptions) error {
+	if err := execJob(jobName, filepaths, options); err != nil {
+		fmt.Println(err)
+		return err
+	}
+
+	return nil
+}
+
+func execJob(jobName string, filepaths []string, options ExecOptions) error {
+	if len(filepaths) == 0 {
+		return validationError(errors.New("pipeline file not specified"))
+	}
+
+	// stdin is the terminal of the shell
+	if readsStdin(filepaths) {
+		return validationError(errors.New("pin exec can not read the pipeline from stdin"))
+	}
+
+	for _, filepath := range filepaths {
+		if err := checkFileExists(filepath); err != nil {
+			return validationError(err)
+		}
+	}
+
+	config, _, err := loadApplyConfig(filepaths, ApplyOptions{Set: options.Set, Env: options.Env})
+
+	if err != nil {
+		return validationError(err)
+	}
+
+	pipeline, warnings, err := parse(config)
+
+	printWarnings(warnings)
+
+	if err != nil {
+		return validationError(err)
+	}
+
+	currentJob, err := execTarget(pipeline, jobName)
+
+	if err != nil {
+		return validationError(err)
+	}
+
+	if !terminal.IsTerminal(int(os.Stdin.Fd())) {
+		return validationError(errors.New("pin exec needs an interactive terminal"))
+	}
+
+	shell := options.Shell
+
+	if shell == "" {
+		shell = "sh"
+	}
+
+	cli, err := newDockerClient()
+
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	r := Runner{
+		ctx:          ctx,
+		dockerCli:    cli,
+		docker:       pipeline.Docker,
+		runID:        newRunID(),
+		pipelineName: runName("", filepaths[0]),
+		configDir:    configDir(filepaths[0]),
+		attempt:      1,
+		startedAt:    time.Now(),
+	}
+
+	r.cli = r.dockerClient(cli, mergeDocker(pipeline.Docker, currentJob.Docker))
+
+	currentJob.Output = os.Stdout
+	currentJob.InfoLog = log.New(os.Stdout, fmt.Sprintf("%s %s ", glyph(glyphJob), currentJob.Name), 0)
+	currentJob.ImageManager = image_manager.NewImageManager(r.cli, currentJob.InfoLog)
+	currentJob.ContainerManager = container_manager.NewContainerManager(r.cli, currentJob.InfoLog)
+
+	if err := r.prepareImage(currentJob); err != nil {
+		return err
+	}
+
+	if len(currentJob.Services) > 0 {
+		defer r.stopServices(currentJob)
+
+		if err := r.startServices(currentJob); err != nil {
+			return err
+		}
+	}
+
+	// the container is removed even when starting it failed half way
+	defer func() {
+		removeContainerOnCancel(currentJob, currentJob.Container.ID)
+	}()
+
+	if err := r.startJobContainer(currentJob); err != nil {
+		return err
+	}
+
+	if currentJob.Cache != nil {
+		if err := r.restoreCache(*currentJob); err != nil {
+			return err
+		}
+	}
+
+	color.Set(color.FgGreen)
+	currentJob.InfoLog.Printf("Attaching %s, exit the shell to remove the container", shell)
+	color.Unset()
+
+	exitCode, err := r.attachShell(currentJob, shell)
+
+	if err != nil {
+		return err
+	}
+
+	currentJob.InfoLog.Printf("Shell exited with code %d", exitCode)
+
+	return nil
+}
+
+// execTarget returns the job of the workflow to start a shell in, plugin
+// jobs run on the host and have no container.
+func execTarget(pipeline Pipeline, jobName string) (*Job, error) {
+	for _, job := range pipeline.Workflow {
+		if job.Name != jobName {
+			continue
+		}
+
+		if job.Uses != "" {
+			return nil, fmt.Errorf("%s runs the plugin %s on the host, it has no container", jobName, job.Uses)
+		}
+
+		return job, nil
+	}
+
+	return nil, fmt.Errorf("job %s is not in the workflow", jobName)
+}
+
+// attachShell runs shell in the job container with the terminal in raw
+// mode, so keys like ctrl-c reach the shell instead of pin.
+func (r Runner) attachShell(currentJob *Job, shell string) (int, error) {
+	exec, err := r.cli.ContainerExecCreate(r.ctx, currentJob.Container.ID, types.ExecConfig{
+		AttachStdin:  true,
+		AttachStdout: true,
+		AttachStderr: true,
+		Tty:          true,
+		Cmd:          []string{shell},
+		WorkingDir:   currentJob.WorkDir,
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	res, err := r.cli.ContainerExecAttach(r.ctx, exec.ID, types.ExecStartCheck{Tty: true})
+
+	if err != nil {
+		return 0, err
+	}
+
+	defer res.Close()
+
+	fd := int(os.Stdin.Fd())
+	state, err := terminal.MakeRaw(fd)
+
+	if err != nil {
+		return 0, err
+	}
+
+	defer terminal.Restore(fd, state)
+
+	if width, height, err := terminal.Size(int(os.Stdout.Fd())); err == nil {
+		r.cli.ContainerExecResize(r.ctx, exec.ID, types.ResizeOptions{Width: uint(width), Height: uint(height)})
+	}
+
+	go func() {
+		io.Copy(res.Conn, os.Stdin)
+		res.CloseWrite()
+	}()
+
+	io.Copy(os.Stdout, res.Reader)
+
+	status, err := r.cli.ContainerExecInspect(r.ctx, exec.ID)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return status.ExitCode, nil
+}
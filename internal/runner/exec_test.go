@@ -0,0 +1,34 @@
+package runner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecTargetFindsContainerJobs(t *testing.T) {
+	pipeline := Pipeline{Workflow: []*Job{
+		{Name: "build", Image: "golang:alpine"},
+		{Name: "notify", Uses: "slack"},
+	}}
+
+	job, err := execTarget(pipeline, "build")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "golang:alpine", job.Image)
+
+	_, err = execTarget(pipeline, "notify")
+
+	assert.EqualError(t, err, "notify runs the plugin slack on the host, it has no container")
+
+	_, err = execTarget(pipeline, "deploy")
+
+	assert.EqualError(t, err, "job deploy is not in the workflow")
+}
+
+func TestExecRejectsPipelineFromStdin(t *testing.T) {
+	err := execJob("build", []string{"-"}, ExecOptions{})
+
+	assert.EqualError(t, err, "VALIDATION: pin exec can not read the pipeline from stdin")
+	assert.Equal(t, 2, ExitCode(err))
+}
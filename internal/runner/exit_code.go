@@ -0,0 +1,56 @@
+package runner
+
+import (
+	"context"
+	"errors"
+
+	"github.com/docker/docker/client"
+	"github.com/muhammedikinci/pin/internal/pin_error"
+)
+
+// classifyError returns the PinError of err, errors that do not carry one
+// get the code of the failure they describe.
+func classifyError(err error) *pin_error.PinError {
+	if err == nil {
+		return nil
+	}
+
+	var pinErr *pin_error.PinError
+	var cmdErr *commandError
+
+	switch {
+	case errors.As(err, &pinErr):
+		return pinErr
+	case errors.As(err, &cmdErr):
+		return pin_error.New(pin_error.CodeScriptFailed, "", err.Error(), err)
+	case client.IsErrConnectionFailed(err):
+		return pin_error.New(pin_error.CodeDockerConnection, "", err.Error(), err)
+	case errors.Is(err, context.DeadlineExceeded):
+		return pin_error.New(pin_error.CodeTimeout, "", err.Error(), err)
+	case errors.Is(err, context.Canceled):
+		return pin_error.New(pin_error.CodeInterrupted, "", err.Error(), err)
+	}
+
+	return pin_error.From(err)
+}
+
+// ExitCode is the process exit code for the error Apply, Rerun or Attach
+// returned, 0 when there is none.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	return pin_error.ExitCode(classifyError(err).Code)
+}
+
+// validationError marks errors that stop a pipeline before it starts.
+func validationError(err error) error {
+	var pinErr *pin_error.PinError
+
+	if err == nil || errors.As(err, &pinErr) {
+		return err
+	}
+
+	return pin_error.New(pin_error.CodeValidation, "", err.Error(), err)
+}
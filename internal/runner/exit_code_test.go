@@ -0,0 +1,32 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/client"
+	"github.com/muhammedikinci/pin/internal/pin_error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExitCodeFollowsTheErrorCode(t *testing.T) {
+	assert.Equal(t, 0, ExitCode(nil))
+	assert.Equal(t, 1, ExitCode(errors.New("boom")))
+	assert.Equal(t, 2, ExitCode(validationError(errors.New("image not specified"))))
+	assert.Equal(t, 3, ExitCode(fmt.Errorf("pulling: %w", client.ErrorConnectionFailed("unix:///var/run/docker.sock"))))
+	assert.Equal(t, 4, ExitCode(&commandError{exitCode: 1}))
+	assert.Equal(t, 5, ExitCode(pin_error.Timeout("ContainerStart", time.Second, nil)))
+	assert.Equal(t, 5, ExitCode(context.DeadlineExceeded))
+	assert.Equal(t, 130, ExitCode(pin_error.New(pin_error.CodeInterrupted, "", "context canceled", context.Canceled)))
+	assert.Equal(t, 1, ExitCode(pin_error.CopyFailed("CopyToContainer", "copying project files", 3, errors.New("EOF"))))
+}
+
+func TestValidationErrorKeepsExistingCodes(t *testing.T) {
+	err := pin_error.Timeout("ImagePull", time.Second, nil)
+
+	assert.Equal(t, err, validationError(err))
+	assert.Nil(t, validationError(nil))
+}
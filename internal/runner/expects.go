@@ -0,0 +1,64 @@
+package runner
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// checkExpects fails the job when one of the outputs it expects is missing
+// from the container after the script, relative paths are resolved against
+// the work directory.
+func (r Runner) checkExpects(currentJob *Job) error {
+	if len(currentJob.Expects) == 0 {
+		return nil
+	}
+
+	script := ""
+
+	for _, expect := range currentJob.Expects {
+		script += "[ -e " + shellQuote(expectPath(currentJob.WorkDir, expect)) + " ] || echo " + shellQuote(expect) + "\n"
+	}
+
+	output, exitCode, err := r.execOutput(script, currentJob)
+
+	if err != nil {
+		return err
+	}
+
+	if exitCode != 0 {
+		return fmt.Errorf("expected outputs could not be checked: %s", strings.TrimSpace(output))
+	}
+
+	missing := []string{}
+
+	for _, line := range strings.Split(output, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			missing = append(missing, line)
+		}
+	}
+
+	if len(missing) > 0 {
+		color.Set(color.FgRed)
+		currentJob.InfoLog.Printf("Expected outputs missing: %s", strings.Join(missing, ", "))
+		color.Unset()
+
+		return fmt.Errorf("expected outputs missing: %s", strings.Join(missing, ", "))
+	}
+
+	color.Set(color.FgGreen)
+	currentJob.InfoLog.Printf("Expected outputs found: %d", len(currentJob.Expects))
+	color.Unset()
+
+	return nil
+}
+
+func expectPath(workDir, expect string) string {
+	if path.IsAbs(expect) {
+		return expect
+	}
+
+	return path.Join(workDir, expect)
+}
@@ -0,0 +1,61 @@
+package runner
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"net"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/golang/mock/gomock"
+	"github.com/muhammedikinci/pin/internal/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckExpectsReportsMissingOutputs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	mockCli := mocks.NewMockClient(ctrl)
+
+	var output bytes.Buffer
+
+	stdcopy.NewStdWriter(&output, stdcopy.Stdout).Write([]byte("dist/app.sha256\n"))
+
+	conn, _ := net.Pipe()
+
+	mockCli.EXPECT().
+		ContainerExecCreate(gomock.Any(), "container", gomock.Any()).
+		DoAndReturn(func(ctx context.Context, container string, config types.ExecConfig) (types.IDResponse, error) {
+			assert.Equal(t, "[ -e '/app/dist/app.bin' ] || echo 'dist/app.bin'\n[ -e '/app/dist/app.sha256' ] || echo 'dist/app.sha256'\n", config.Cmd[2])
+
+			return types.IDResponse{ID: "exec"}, nil
+		})
+	mockCli.EXPECT().
+		ContainerExecAttach(gomock.Any(), "exec", gomock.Any()).
+		Return(types.HijackedResponse{Conn: conn, Reader: bufio.NewReader(&output)}, nil)
+	mockCli.EXPECT().
+		ContainerExecInspect(gomock.Any(), "exec").
+		Return(types.ContainerExecInspect{ExitCode: 0}, nil)
+
+	job := &Job{
+		WorkDir: "/app",
+		Expects: []string{"dist/app.bin", "dist/app.sha256"},
+		InfoLog: log.New(io.Discard, "", 0),
+	}
+	job.Container.ID = "container"
+
+	r := Runner{ctx: context.Background(), cli: mockCli}
+
+	assert.EqualError(t, r.checkExpects(job), "expected outputs missing: dist/app.sha256")
+}
+
+func TestExpectPathResolvesRelativeToWorkDir(t *testing.T) {
+	assert.Equal(t, "/app/dist/app.bin", expectPath("/app", "dist/app.bin"))
+	assert.Equal(t, "/tmp/report.xml", expectPath("/app", "/tmp/report.xml"))
+}
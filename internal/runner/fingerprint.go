@@ -0,0 +1,138 @@
+package runner
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/muhammedikinci/pin/internal/glob"
+)
+
+type SkipIfUnchanged struct {
+	Inputs []string
+}
+
+// inputFingerprint hashes the job definition together with the names and
+// contents of every project file matching the inputs.
+func inputFingerprint(currentJob *Job, currentPath string) (string, error) {
+	h := sha256.New()
+
+	io.WriteString(h, currentJob.Image+"\n")
+	io.WriteString(h, strings.Join(currentJob.Script, "\n")+"\n")
+
+	files := map[string]bool{}
+
+	for _, input := range currentJob.SkipIfUnchanged.Inputs {
+		input = strings.TrimPrefix(filepath.ToSlash(input), "./")
+		root := filepath.Join(currentPath, filepath.FromSlash(glob.Root(input)))
+
+		err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				if errors.Is(err, os.ErrNotExist) {
+					return nil
+				}
+
+				return err
+			}
+
+			if !info.Mode().IsRegular() {
+				return nil
+			}
+
+			rel, err := filepath.Rel(currentPath, path)
+
+			if err != nil {
+				return err
+			}
+
+			rel = filepath.ToSlash(rel)
+
+			if glob.Match(input, rel) {
+				files[rel] = true
+			}
+
+			return nil
+		})
+
+		if err != nil {
+			return "", err
+		}
+	}
+
+	names := make([]string, 0, len(files))
+
+	for name := range files {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		f, err := os.Open(filepath.Join(currentPath, filepath.FromSlash(name)))
+
+		if err != nil {
+			return "", err
+		}
+
+		io.WriteString(h, name+"\n")
+		_, err = io.Copy(h, f)
+		f.Close()
+
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func fingerprintFile(jobName string) (string, error) {
+	base, err := stateDir()
+
+	if err != nil {
+		return "", err
+	}
+
+	project, err := projectID()
+
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(base, "fingerprints", project, unsafeKeyChars.ReplaceAllString(jobName, "_")), nil
+}
+
+func lastFingerprint(jobName string) string {
+	file, err := fingerprintFile(jobName)
+
+	if err != nil {
+		return ""
+	}
+
+	b, err := os.ReadFile(file)
+
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(b))
+}
+
+func saveFingerprint(jobName, fingerprint string) error {
+	file, err := fingerprintFile(jobName)
+
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(file, []byte(fingerprint+"\n"), 0644)
+}
@@ -0,0 +1,46 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInputFingerprintChangesOnlyWithMatchingInputs(t *testing.T) {
+	dir := t.TempDir()
+
+	os.MkdirAll(filepath.Join(dir, "src", "pkg"), 0755)
+	os.WriteFile(filepath.Join(dir, "src", "pkg", "main.go"), []byte("package main"), 0644)
+	os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module test"), 0644)
+	os.WriteFile(filepath.Join(dir, "README.md"), []byte("readme"), 0644)
+
+	job := &Job{
+		Image:           "golang:alpine3.15",
+		Script:          []string{"go build"},
+		SkipIfUnchanged: &SkipIfUnchanged{Inputs: []string{"src/**", "go.mod"}},
+	}
+
+	first, err := inputFingerprint(job, dir)
+
+	assert.Equal(t, err, nil)
+
+	os.WriteFile(filepath.Join(dir, "README.md"), []byte("changed"), 0644)
+
+	second, _ := inputFingerprint(job, dir)
+
+	assert.Equal(t, first, second)
+
+	os.WriteFile(filepath.Join(dir, "src", "pkg", "main.go"), []byte("package main\n"), 0644)
+
+	third, _ := inputFingerprint(job, dir)
+
+	assert.NotEqual(t, first, third)
+
+	job.Script = []string{"go build ./..."}
+
+	fourth, _ := inputFingerprint(job, dir)
+
+	assert.NotEqual(t, third, fourth)
+}
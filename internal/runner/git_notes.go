@@ -0,0 +1,43 @@
+package runner
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// gitNotesRef is where run results are noted, git log --show-notes=pin shows
+// them.
+const gitNotesRef = "pin"
+
+// writeGitNote appends the result of the run to the git note of the commit
+// checked out in dir, earlier runs of the same commit are kept.
+func writeGitNote(dir string, run RunMetadata) error {
+	cmd := exec.Command("git", "notes", "--ref="+gitNotesRef, "append", "-m", gitNoteMessage(run), "HEAD")
+	cmd.Dir = dir
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %s", err, strings.TrimSpace(string(output)))
+	}
+
+	return nil
+}
+
+func gitNoteMessage(run RunMetadata) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "pin %s: %s (run %s, %s)\n", run.Pipeline, run.Status, run.ID, run.StartedAt.Format(time.RFC3339))
+
+	for _, job := range run.Jobs {
+		fmt.Fprintf(&b, "  %s: %s", job.Name, job.Status)
+
+		if d := jobDuration(job); d > 0 {
+			fmt.Fprintf(&b, " in %s", d.Round(time.Millisecond))
+		}
+
+		b.WriteString("\n")
+	}
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
@@ -0,0 +1,67 @@
+package runner
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteGitNoteAppendsEveryRun(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+
+	git := func(args ...string) string {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=pin", "-c", "user.email=pin@example.com"}, args...)...)
+		cmd.Dir = dir
+		output, err := cmd.CombinedOutput()
+		assert.Equal(t, nil, err, string(output))
+		return string(output)
+	}
+
+	git("init", "-q")
+	git("commit", "-q", "--allow-empty", "-m", "initial")
+
+	start := time.Date(2022, 5, 15, 10, 0, 0, 0, time.UTC)
+
+	run := RunMetadata{
+		ID:        "20220515-100000-a1b2c3",
+		Pipeline:  "ci",
+		Status:    JobStatusSuccess,
+		StartedAt: start,
+		Jobs: []JobSnapshot{
+			{Name: "build", Status: JobStatusSuccess, StartedAt: start, FinishedAt: start.Add(2 * time.Second)},
+			{Name: "deploy", Status: JobStatusSkipped},
+		},
+	}
+
+	t.Setenv("GIT_AUTHOR_NAME", "pin")
+	t.Setenv("GIT_AUTHOR_EMAIL", "pin@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "pin")
+	t.Setenv("GIT_COMMITTER_EMAIL", "pin@example.com")
+
+	assert.Equal(t, nil, writeGitNote(dir, run))
+
+	run.ID = "20220515-110000-d4e5f6"
+	run.Status = JobStatusFailed
+
+	assert.Equal(t, nil, writeGitNote(dir, run))
+
+	note := git("notes", "--ref=pin", "show", "HEAD")
+
+	assert.Equal(t, strings.Join([]string{
+		"pin ci: success (run 20220515-100000-a1b2c3, 2022-05-15T10:00:00Z)",
+		"  build: success in 2s",
+		"  deploy: skipped",
+		"",
+		"pin ci: failed (run 20220515-110000-d4e5f6, 2022-05-15T10:00:00Z)",
+		"  build: success in 2s",
+		"  deploy: skipped",
+		"",
+	}, "\n"), note)
+}
@@ -0,0 +1,126 @@
+package runner
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// getMinSuccess parses the share of a parallel group that has to succeed, as
+// a percentage such as 80%.
+func getMinSuccess(minSuccess interface{}) (int, error) {
+	if minSuccess == nil {
+		return 0, nil
+	}
+
+	s, _ := minSuccess.(string)
+
+	percent, err := strconv.Atoi(strings.TrimSuffix(s, "%"))
+
+	if !strings.HasSuffix(s, "%") || err != nil || percent < 1 || percent > 100 {
+		return 0, fmt.Errorf("invalid minSuccess: %v, use a percentage such as 80%%", minSuccess)
+	}
+
+	return percent, nil
+}
+
+// parallelGroups returns the runs of consecutive parallel jobs.
+func parallelGroups(jobs []*Job) [][]*Job {
+	groups := [][]*Job{}
+
+	for i, job := range jobs {
+		if !job.IsParallel {
+			continue
+		}
+
+		if i == 0 || !jobs[i-1].IsParallel {
+			groups = append(groups, []*Job{})
+		}
+
+		groups[len(groups)-1] = append(groups[len(groups)-1], job)
+	}
+
+	return groups
+}
+
+// checkMinSuccess makes sure the jobs of a parallel group do not set
+// different thresholds, a threshold set on one job applies to the group.
+func checkMinSuccess(jobs []*Job) error {
+	for _, group := range parallelGroups(jobs) {
+		minSuccess := 0
+
+		for _, job := range group {
+			if job.MinSuccess == 0 {
+				continue
+			}
+
+			if minSuccess != 0 && job.MinSuccess != minSuccess {
+				return fmt.Errorf("%s: jobs of a parallel group must use the same minSuccess", job.Name)
+			}
+
+			minSuccess = job.MinSuccess
+		}
+	}
+
+	return nil
+}
+
+func groupMinSuccess(group []*Job) int {
+	for _, job := range group {
+		if job.MinSuccess != 0 {
+			return job.MinSuccess
+		}
+	}
+
+	return 0
+}
+
+// groupSucceeded reports whether enough jobs of a finished group succeeded
+// and how many did. Skipped and cancelled jobs do not count as successes.
+func groupSucceeded(group []*Job) (bool, int) {
+	succeeded := 0
+
+	for _, job := range group {
+		switch job.Status {
+		case JobStatusSuccess, JobStatusCached, JobStatusCacheHit:
+			succeeded++
+		}
+	}
+
+	return succeeded*100 >= groupMinSuccess(group)*len(group), succeeded
+}
+
+// tolerateGroupFailures marks the failed jobs of the parallel groups that
+// met their minSuccess, they are reported as warnings and do not fail the
+// run.
+func tolerateGroupFailures(jobs []*Job, infoLog *log.Logger) {
+	for _, group := range parallelGroups(jobs) {
+		minSuccess := groupMinSuccess(group)
+
+		if minSuccess == 0 {
+			continue
+		}
+
+		ok, succeeded := groupSucceeded(group)
+
+		if !ok || succeeded == len(group) {
+			continue
+		}
+
+		failed := []string{}
+
+		for _, job := range group {
+			if job.Status == JobStatusFailed {
+				job.Tolerated = true
+				failed = append(failed, job.Name)
+			}
+		}
+
+		color.Set(color.FgYellow)
+		infoLog.Printf("warning: %d/%d parallel jobs succeeded, minSuccess %d%% is met, failed: %s", succeeded, len(group), minSuccess, strings.Join(failed, ", "))
+		color.Unset()
+	}
+}
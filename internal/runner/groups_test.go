@@ -0,0 +1,134 @@
+package runner
+
+import (
+	"errors"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetMinSuccessAcceptsPercentages(t *testing.T) {
+	minSuccess, err := getMinSuccess("80%")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 80, minSuccess)
+
+	minSuccess, err = getMinSuccess(nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, minSuccess)
+
+	for _, invalid := range []interface{}{"80", 80, "0%", "101%", "most%"} {
+		_, err = getMinSuccess(invalid)
+
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestParallelJobsWithDifferentMinSuccessReturnError(t *testing.T) {
+	config, _ := decodeConfig([]byte(`workflow:
+  - chrome
+  - firefox
+chrome:
+  image: node
+  parallel: true
+  minSuccess: 80%
+  script:
+    - npm test
+firefox:
+  image: node
+  parallel: true
+  minSuccess: 50%
+  script:
+    - npm test
+`))
+
+	_, _, err := parse(config)
+
+	assert.EqualError(t, err, "firefox: jobs of a parallel group must use the same minSuccess")
+}
+
+func TestLinkJobsGroupsConsecutiveParallelJobs(t *testing.T) {
+	build := &Job{Name: "build"}
+	chrome := &Job{Name: "chrome", IsParallel: true}
+	firefox := &Job{Name: "firefox", IsParallel: true}
+	deploy := &Job{Name: "deploy"}
+
+	linkJobs([]*Job{build, chrome, firefox, deploy})
+
+	assert.Nil(t, build.Group)
+	assert.Equal(t, []*Job{chrome, firefox}, chrome.Group)
+	assert.Equal(t, []*Job{chrome, firefox}, firefox.Group)
+	assert.Nil(t, deploy.Group)
+}
+
+func finishedGroup(statuses ...string) []*Job {
+	jobs := []*Job{}
+
+	for i, status := range statuses {
+		job := &Job{Name: string(rune('a' + i)), IsParallel: true, MinSuccess: 60, Status: status}
+
+		if status == JobStatusFailed {
+			job.Err = errors.New(job.Name + " failed")
+		}
+
+		jobs = append(jobs, job)
+	}
+
+	linkJobs(jobs)
+
+	return jobs
+}
+
+func TestGroupMeetingMinSuccessDoesNotFailTheNextJob(t *testing.T) {
+	group := finishedGroup(JobStatusSuccess, JobStatusFailed, JobStatusSuccess)
+	next := &Job{Name: "next"}
+
+	linkJobs(append(group, next))
+
+	assert.NoError(t, needsError(next))
+
+	tolerateGroupFailures(group, log.New(&quietOutput{}, "", 0))
+
+	assert.False(t, group[0].Tolerated)
+	assert.True(t, group[1].Tolerated)
+}
+
+func TestGroupMissingMinSuccessFailsTheNextJob(t *testing.T) {
+	group := finishedGroup(JobStatusFailed, JobStatusFailed, JobStatusSuccess)
+	next := &Job{Name: "next"}
+
+	linkJobs(append(group, next))
+
+	assert.EqualError(t, needsError(next), "a failed")
+
+	tolerateGroupFailures(group, log.New(&quietOutput{}, "", 0))
+
+	assert.False(t, group[0].Tolerated)
+	assert.False(t, group[1].Tolerated)
+}
+
+func TestToleratedJobsDoNotFailTheRun(t *testing.T) {
+	group := finishedGroup(JobStatusSuccess, JobStatusFailed, JobStatusSuccess)
+
+	tolerateGroupFailures(group, log.New(&quietOutput{}, "", 0))
+
+	run := newRunMetadata("id", "test", "pipeline.yaml", Pipeline{Workflow: group})
+
+	assert.Equal(t, JobStatusSuccess, run.Status)
+	assert.True(t, run.Jobs[1].Tolerated)
+	assert.Contains(t, stepSummary(run), "| b | ⚠️ failed (tolerated) |")
+}
+
+func TestSkippedAndCancelledJobsDoNotCountAsSuccesses(t *testing.T) {
+	ok, succeeded := groupSucceeded(finishedGroup(JobStatusSkipped, JobStatusFailed, JobStatusCancelled))
+
+	assert.False(t, ok)
+	assert.Equal(t, 0, succeeded)
+
+	ok, succeeded = groupSucceeded(finishedGroup(JobStatusCached, JobStatusFailed, JobStatusCacheHit))
+
+	assert.True(t, ok)
+	assert.Equal(t, 2, succeeded)
+}
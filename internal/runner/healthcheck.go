@@ -0,0 +1,123 @@
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/fatih/color"
+)
+
+// getContainerHealthcheck reads the healthcheck of a job or a service, test
+// is a shell command or an exec form list like in a compose file.
+func getContainerHealthcheck(healthcheck interface{}) (*container.HealthConfig, error) {
+	if healthcheck == nil {
+		return nil, nil
+	}
+
+	healthcheckMap := getStringMap(healthcheck)
+
+	if len(healthcheckMap) == 0 {
+		return nil, errors.New("healthcheck must be a map")
+	}
+
+	test, err := getStringArray("healthcheck test", healthcheckMap["test"])
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(test) == 0 {
+		return nil, errors.New("healthcheck test not specified")
+	}
+
+	if reflect.ValueOf(healthcheckMap["test"]).Kind() == reflect.String {
+		test = []string{"CMD-SHELL", test[0]}
+	} else if test[0] != "CMD" && test[0] != "CMD-SHELL" && test[0] != "NONE" {
+		test = append([]string{"CMD"}, test...)
+	}
+
+	config := &container.HealthConfig{Test: test}
+
+	for key, target := range map[string]*time.Duration{
+		"interval":    &config.Interval,
+		"timeout":     &config.Timeout,
+		"startperiod": &config.StartPeriod,
+	} {
+		d, err := getDuration(healthcheckMap[key])
+
+		if err != nil {
+			return nil, fmt.Errorf("healthcheck %s: %w", key, err)
+		}
+
+		if d != nil {
+			*target = *d
+		}
+	}
+
+	if retries, ok := healthcheckMap["retries"]; ok {
+		if config.Retries, ok = retries.(int); !ok || config.Retries < 0 {
+			return nil, fmt.Errorf("invalid healthcheck retries: %v", retries)
+		}
+	}
+
+	return config, nil
+}
+
+// readyTimeout is how long to wait for a container to become healthy, a
+// healthcheck that needs longer than serviceReadyTimeout to report gets the
+// time it needs to become unhealthy.
+func readyTimeout(healthcheck *container.HealthConfig) time.Duration {
+	if healthcheck == nil {
+		return serviceReadyTimeout
+	}
+
+	interval := healthcheck.Interval
+	timeout := healthcheck.Timeout
+	retries := healthcheck.Retries
+
+	// the defaults of the docker daemon
+	if interval == 0 {
+		interval = 30 * time.Second
+	}
+
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+
+	if retries == 0 {
+		retries = 3
+	}
+
+	needed := healthcheck.StartPeriod + time.Duration(retries+1)*(interval+timeout)
+
+	if needed > serviceReadyTimeout {
+		return needed
+	}
+
+	return serviceReadyTimeout
+}
+
+// waitForJobHealthcheck blocks until the job container reports healthy, the
+// script runs only then.
+func (r Runner) waitForJobHealthcheck(currentJob *Job) error {
+	if currentJob.Healthcheck == nil {
+		return nil
+	}
+
+	color.Set(color.FgBlue)
+	currentJob.InfoLog.Println("Waiting for the container to be healthy")
+	color.Unset()
+
+	if err := currentJob.ContainerManager.WaitForContainer(r.ctx, currentJob.Container.ID, readyTimeout(currentJob.Healthcheck)); err != nil {
+		return err
+	}
+
+	color.Set(color.FgGreen)
+	currentJob.InfoLog.Println("Container is healthy")
+	color.Unset()
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/golang/mock/gomock"
+	"github.com/muhammedikinci/pin/internal/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetContainerHealthcheck(t *testing.T) {
+	healthcheck, err := getContainerHealthcheck(map[string]interface{}{
+		"test":        "pg_isready -U postgres",
+		"interval":    "2s",
+		"startperiod": "10s",
+		"retries":     5,
+	})
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, &container.HealthConfig{
+		Test:        []string{"CMD-SHELL", "pg_isready -U postgres"},
+		Interval:    2 * time.Second,
+		StartPeriod: 10 * time.Second,
+		Retries:     5,
+	}, healthcheck)
+
+	healthcheck, err = getContainerHealthcheck(map[string]interface{}{
+		"test": []interface{}{"redis-cli", "ping"},
+	})
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, []string{"CMD", "redis-cli", "ping"}, healthcheck.Test)
+
+	_, err = getContainerHealthcheck(map[string]interface{}{"interval": "2s"})
+
+	assert.EqualError(t, err, "healthcheck test not specified")
+}
+
+func TestReadyTimeoutCoversTheHealthcheck(t *testing.T) {
+	assert.Equal(t, serviceReadyTimeout, readyTimeout(nil))
+	assert.Equal(t, serviceReadyTimeout, readyTimeout(&container.HealthConfig{Interval: time.Second, Timeout: time.Second}))
+	assert.Equal(t, 4*time.Minute, readyTimeout(&container.HealthConfig{}))
+}
+
+func TestGetServicesWithHealthcheck(t *testing.T) {
+	services, err := getServices([]interface{}{
+		map[string]interface{}{
+			"image":       "postgres:14",
+			"healthcheck": map[string]interface{}{"test": "pg_isready"},
+		},
+	})
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, []string{"CMD-SHELL", "pg_isready"}, services[0].Healthcheck.Test)
+}
+
+func TestStartJobContainerRemovesTheUnhealthyContainer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	unhealthy := errors.New("container abc is unhealthy")
+
+	cli := mocks.NewMockClient(ctrl)
+	containerManager := mocks.NewMockContainerManager(ctrl)
+	imageManager := mocks.NewMockImageManager(ctrl)
+
+	containerManager.EXPECT().StartContainer(ctx, gomock.Any()).Return(container.ContainerCreateCreatedBody{ID: "abc"}, nil)
+	imageManager.EXPECT().ImageDigest(ctx, "alpine").Return("", errors.New("no digest"))
+	cli.EXPECT().ContainerInspect(ctx, "abc").Return(types.ContainerJSON{}, errors.New("no inspect"))
+	cli.EXPECT().ContainerStart(ctx, "abc", types.ContainerStartOptions{}).Return(nil)
+	containerManager.EXPECT().WaitForContainer(ctx, "abc", gomock.Any()).Return(unhealthy)
+
+	// the container must be removed, it keeps the service network in use
+	gomock.InOrder(
+		cli.EXPECT().ContainerKill(ctx, "abc", "KILL").Return(nil),
+		containerManager.EXPECT().StopContainer(ctx, "abc", nil).Return(nil),
+		containerManager.EXPECT().RemoveContainer(ctx, "abc", false).Return(nil),
+	)
+
+	job := Job{
+		Name:             "test",
+		Image:            "alpine",
+		Healthcheck:      &container.HealthConfig{Test: []string{"CMD", "true"}},
+		ContainerManager: containerManager,
+		ImageManager:     imageManager,
+		InfoLog:          log.New(io.Discard, "", 0),
+	}
+
+	r := Runner{ctx: ctx, cli: cli}
+
+	assert.Equal(t, unhealthy, r.startJobContainer(&job))
+}
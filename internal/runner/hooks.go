@@ -0,0 +1,195 @@
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/fatih/color"
+)
+
+const (
+	HookPrePipeline  = "prePipeline"
+	HookPostPipeline = "postPipeline"
+	HookPreJob       = "preJob"
+	HookPostJob      = "postJob"
+)
+
+var hookNames = []string{HookPrePipeline, HookPostPipeline, HookPreJob, HookPostJob}
+
+// HostHook is a list of commands that run on the host around the pipeline or
+// every job, a failing fatal hook fails the pipeline or the job.
+type HostHook struct {
+	Script []string
+	Fatal  bool
+}
+
+// HookResult is the captured output of one hook command, kept in the run
+// metadata.
+type HookResult struct {
+	Hook     string `json:"hook"`
+	Job      string `json:"job,omitempty"`
+	Command  string `json:"command"`
+	Output   string `json:"output"`
+	ExitCode int    `json:"exitCode"`
+}
+
+type hookLog struct {
+	mu      sync.Mutex
+	results []HookResult
+}
+
+func (h *hookLog) add(result HookResult) {
+	if h == nil {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.results = append(h.results, result)
+}
+
+func (h *hookLog) all() []HookResult {
+	if h == nil {
+		return []HookResult{}
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return append([]HookResult{}, h.results...)
+}
+
+// getHostHooks accepts a command list or a block with script and fatal for
+// every hook, hooks are fatal unless fatal is false.
+func getHostHooks(hostHooks interface{}) (map[string]HostHook, error) {
+	hooks := map[string]HostHook{}
+
+	if hostHooks == nil {
+		return hooks, nil
+	}
+
+	hooksMap := getStringMap(hostHooks)
+
+	for key := range hooksMap {
+		known := false
+
+		for _, name := range hookNames {
+			if key == strings.ToLower(name) {
+				known = true
+			}
+		}
+
+		if !known {
+			return hooks, fmt.Errorf("unknown host hook: %s", key)
+		}
+	}
+
+	for _, name := range hookNames {
+		val, ok := hooksMap[strings.ToLower(name)]
+
+		if !ok {
+			continue
+		}
+
+		hook := HostHook{Fatal: true}
+		script := val
+		var err error
+
+		if hookMap := getStringMap(val); len(hookMap) > 0 {
+			script = hookMap["script"]
+
+			if hook.Fatal, err = getBool(name+" fatal", hookMap["fatal"], true); err != nil {
+				return hooks, err
+			}
+		}
+
+		if hook.Script, err = getStringArray(name+" script", script); err != nil {
+			return hooks, err
+		}
+
+		if len(hook.Script) == 0 {
+			return hooks, fmt.Errorf("%s: script not specified", name)
+		}
+
+		hooks[name] = hook
+	}
+
+	return hooks, nil
+}
+
+// runHostHook runs the commands of a hook in order and stops at the first
+// failing one, the error is only returned for fatal hooks.
+func (r Runner) runHostHook(name string, hooks map[string]HostHook, job string, infoLog *log.Logger) error {
+	hook, ok := hooks[name]
+
+	if !ok {
+		return nil
+	}
+
+	env := []string{"PIN_RUN_ID=" + r.runID, "PIN_HOOK=" + name}
+
+	if job != "" {
+		env = append(env, "PIN_JOB="+job)
+	}
+
+	for _, command := range hook.Script {
+		infoLog.Printf("Host hook %s: %s", name, command)
+
+		out, err := hostCommand(command, env).CombinedOutput()
+
+		// the output is kept in the run history, secrets are masked before
+		output := r.secrets.mask(string(out))
+		result := HookResult{Hook: name, Job: job, Command: r.secrets.mask(command), Output: output}
+
+		if err != nil {
+			result.ExitCode = -1
+
+			var exitErr interface{ ExitCode() int }
+
+			if errors.As(err, &exitErr) {
+				result.ExitCode = exitErr.ExitCode()
+			}
+		}
+
+		r.hooks.add(result)
+
+		// the logger writes whole lines, with the prefix of the job, to the
+		// job log and through quiet mode
+		if output != "" {
+			for _, line := range strings.Split(strings.TrimSuffix(output, "\n"), "\n") {
+				infoLog.Println(line)
+			}
+		}
+
+		if err == nil {
+			continue
+		}
+
+		if hook.Fatal {
+			color.Set(color.FgRed)
+			infoLog.Printf("Host hook %s failed: %s", name, err)
+			color.Unset()
+
+			return fmt.Errorf("host hook %s failed: %w", name, err)
+		}
+
+		color.Set(color.FgYellow)
+		infoLog.Printf("warning: host hook %s failed: %s", name, err)
+		color.Unset()
+
+		return nil
+	}
+
+	return nil
+}
+
+// pipelineLogger logs what belongs to the whole run, masking the secrets of
+// its jobs.
+func (r Runner) pipelineLogger() *log.Logger {
+	return log.New(r.secrets.writer(os.Stdout), glyph(glyphJob)+" pipeline ", 0)
+}
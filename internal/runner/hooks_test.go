@@ -0,0 +1,79 @@
+package runner
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetHostHooksAcceptsListsAndBlocks(t *testing.T) {
+	hooks, err := getHostHooks(map[string]interface{}{
+		"prepipeline": []interface{}{"./vpn-up.sh"},
+		"postjob": map[interface{}]interface{}{
+			"script": "./cleanup.sh",
+			"fatal":  false,
+		},
+	})
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, map[string]HostHook{
+		HookPrePipeline: {Script: []string{"./vpn-up.sh"}, Fatal: true},
+		HookPostJob:     {Script: []string{"./cleanup.sh"}, Fatal: false},
+	}, hooks)
+
+	_, err = getHostHooks(map[string]interface{}{"beforeall": "echo"})
+
+	assert.EqualError(t, err, "unknown host hook: beforeall")
+}
+
+func TestRunHostHookRecordsOutputAndHonoursFatal(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the hooks use sh")
+	}
+
+	r := Runner{runID: "run-1", hooks: &hookLog{}}
+	infoLog := log.New(io.Discard, "", 0)
+
+	hooks := map[string]HostHook{
+		HookPreJob:  {Script: []string{"echo $PIN_JOB", "exit 3", "echo unreachable"}, Fatal: true},
+		HookPostJob: {Script: []string{"exit 1"}, Fatal: false},
+	}
+
+	err := r.runHostHook(HookPreJob, hooks, "build", infoLog)
+
+	assert.NotNil(t, err)
+	assert.Nil(t, r.runHostHook(HookPostJob, hooks, "build", infoLog))
+	assert.Nil(t, r.runHostHook(HookPrePipeline, hooks, "", infoLog))
+
+	assert.Equal(t, []HookResult{
+		{Hook: HookPreJob, Job: "build", Command: "echo $PIN_JOB", Output: "build\n"},
+		{Hook: HookPreJob, Job: "build", Command: "exit 3", ExitCode: 3},
+		{Hook: HookPostJob, Job: "build", Command: "exit 1", ExitCode: 1},
+	}, r.hooks.all())
+}
+
+func TestRunHostHookMasksSecretsInOutputAndHistory(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the hooks use sh")
+	}
+
+	r := Runner{runID: "run-1", hooks: &hookLog{}, secrets: newSecretRegistry()}
+	r.secrets.add([]string{"API_TOKEN=s3cr3t-value"})
+
+	var logs bytes.Buffer
+	infoLog := log.New(r.secrets.writer(&logs), "build ", 0)
+
+	hooks := map[string]HostHook{
+		HookPreJob: {Script: []string{"echo token s3cr3t-value; echo done"}, Fatal: true},
+	}
+
+	assert.Nil(t, r.runHostHook(HookPreJob, hooks, "build", infoLog))
+
+	assert.NotContains(t, logs.String(), "s3cr3t-value")
+	assert.Contains(t, logs.String(), "build token "+secretMask+"\nbuild done\n")
+	assert.Equal(t, "token "+secretMask+"\ndone\n", r.hooks.all()[0].Output)
+}
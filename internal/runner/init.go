@@ -0,0 +1,199 @@
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+const initPipelineFile = "pipeline.yaml"
+
+type InitOptions struct {
+	// Dockerfile also generates a Dockerfile that the job builds its image
+	// from instead of pulling one.
+	Dockerfile bool
+	// Force overwrites existing files.
+	Force bool
+}
+
+type projectTemplate struct {
+	// markers are the files that identify the project type.
+	markers    []string
+	image      string
+	copyIgnore []string
+	script     []string
+	dockerfile string
+}
+
+var projectTemplates = map[string]projectTemplate{
+	"go": {
+		markers: []string{"go.mod"},
+		image:   "golang:alpine",
+		script: []string{
+			"go mod download",
+			"go build ./...",
+			"go test ./...",
+		},
+		dockerfile: `FROM golang:alpine
+
+WORKDIR /app
+
+COPY go.mod go.sum* ./
+RUN go mod download
+`,
+	},
+	"node": {
+		markers:    []string{"package.json"},
+		image:      "node:lts-alpine",
+		copyIgnore: []string{"node_modules"},
+		script: []string{
+			"npm ci",
+			"npm test",
+		},
+		dockerfile: `FROM node:lts-alpine
+
+WORKDIR /app
+
+COPY package.json package-lock.json* ./
+RUN npm ci
+`,
+	},
+	"python": {
+		markers:    []string{"requirements.txt", "pyproject.toml", "setup.py"},
+		image:      "python:3-slim",
+		copyIgnore: []string{"\\.venv", "__pycache__"},
+		script: []string{
+			"pip install -r requirements.txt",
+			"python -m pytest",
+		},
+		dockerfile: `FROM python:3-slim
+
+WORKDIR /app
+
+COPY requirements.txt ./
+RUN pip install -r requirements.txt pytest
+`,
+	},
+}
+
+// Init generates a starter pipeline.yaml in the current directory, the
+// project type is detected from its files when no template is given.
+func Init(template string, options InitOptions) error {
+	if err := initProject(".", template, options); err != nil {
+		fmt.Println(err)
+		return err
+	}
+
+	return nil
+}
+
+func initProject(dir, template string, options InitOptions) error {
+	if template == "" {
+		template = detectTemplate(dir)
+
+		if template == "" {
+			return fmt.Errorf("project type could not be detected, choose a template: %s", strings.Join(templateNames(), ", "))
+		}
+
+		fmt.Printf("Detected %s project\n", template)
+	}
+
+	t, ok := projectTemplates[template]
+
+	if !ok {
+		return fmt.Errorf("unknown template %q, choose one of: %s", template, strings.Join(templateNames(), ", "))
+	}
+
+	files := map[string]string{initPipelineFile: t.pipeline(options.Dockerfile)}
+
+	if options.Dockerfile {
+		files["Dockerfile"] = t.dockerfile
+	}
+
+	for name := range files {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil && !options.Force {
+			return fmt.Errorf("%s already exists, use --force to overwrite it", name)
+		} else if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+
+	for _, name := range []string{initPipelineFile, "Dockerfile"} {
+		content, ok := files[name]
+
+		if !ok {
+			continue
+		}
+
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			return err
+		}
+
+		color.Set(color.FgGreen)
+		fmt.Printf("%s %s created\n", glyph(glyphSuccess), name)
+		color.Unset()
+	}
+
+	return nil
+}
+
+// detectTemplate returns the first template, in name order, whose marker
+// file exists in dir.
+func detectTemplate(dir string) string {
+	for _, name := range templateNames() {
+		for _, marker := range projectTemplates[name].markers {
+			if _, err := os.Stat(filepath.Join(dir, marker)); err == nil {
+				return name
+			}
+		}
+	}
+
+	return ""
+}
+
+func templateNames() []string {
+	names := []string{}
+
+	for name := range projectTemplates {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+func (t projectTemplate) pipeline(dockerfile bool) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "version: %d\n\nworkflow:\n  - test\n\ntest:\n", latestSchemaVersion)
+
+	if dockerfile {
+		b.WriteString("  dockerfile: Dockerfile\n")
+	} else {
+		fmt.Fprintf(&b, "  image: %s\n", t.image)
+	}
+
+	b.WriteString("  copyFiles: true\n")
+
+	if len(t.copyIgnore) > 0 {
+		b.WriteString("  copyIgnore:\n")
+
+		for _, pattern := range t.copyIgnore {
+			fmt.Fprintf(&b, "    - %q\n", pattern)
+		}
+	}
+
+	b.WriteString("  script:\n")
+
+	for _, cmd := range t.script {
+		fmt.Fprintf(&b, "    - %s\n", cmd)
+	}
+
+	return b.String()
+}
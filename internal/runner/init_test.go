@@ -0,0 +1,71 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInitProjectDetectsTemplateAndGeneratesValidPipeline(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "package.json"), []byte("{}"), 0644)
+
+	err := initProject(dir, "", InitOptions{})
+
+	assert.Equal(t, err, nil)
+
+	content, err := os.ReadFile(filepath.Join(dir, initPipelineFile))
+
+	assert.Equal(t, err, nil)
+	assert.Contains(t, string(content), "image: node:lts-alpine")
+
+	findings, err := ValidateBytes(content)
+
+	assert.Equal(t, err, nil)
+	assert.Empty(t, findings)
+
+	_, err = os.Stat(filepath.Join(dir, "Dockerfile"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestInitProjectWithDockerfile(t *testing.T) {
+	dir := t.TempDir()
+
+	err := initProject(dir, "go", InitOptions{Dockerfile: true})
+
+	assert.Equal(t, err, nil)
+
+	content, _ := os.ReadFile(filepath.Join(dir, initPipelineFile))
+	assert.Contains(t, string(content), "dockerfile: Dockerfile")
+
+	// the dockerfile is checked next to the pipeline file
+	config, err := decodeConfig(content)
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, nil, resolveConfigPaths(config, dir))
+	assert.Empty(t, validateConfig(config))
+
+	dockerfile, _ := os.ReadFile(filepath.Join(dir, "Dockerfile"))
+	assert.Contains(t, string(dockerfile), "FROM golang:alpine")
+}
+
+func TestInitProjectDoesNotOverwriteWithoutForce(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, initPipelineFile), []byte("workflow: []\n"), 0644)
+
+	err := initProject(dir, "python", InitOptions{})
+
+	assert.EqualError(t, err, "pipeline.yaml already exists, use --force to overwrite it")
+
+	err = initProject(dir, "python", InitOptions{Force: true})
+
+	assert.Equal(t, err, nil)
+}
+
+func TestInitProjectFailsWhenTypeIsNotDetected(t *testing.T) {
+	err := initProject(t.TempDir(), "", InitOptions{})
+
+	assert.EqualError(t, err, "project type could not be detected, choose a template: go, node, python")
+}
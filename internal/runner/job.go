@@ -1,26 +1,89 @@
 package runner
 
 import (
+	"io"
 	"log"
+	"time"
 
 	"github.com/docker/docker/api/types/container"
+	"github.com/fatih/color"
 	"github.com/muhammedikinci/pin/internal/interfaces"
 )
 
 type Job struct {
 	Name             string
+	Description      string
+	Tags             []string
 	Image            string
+	ImageFallbacks   []string
+	PrimaryImage     string
+	Dockerfile       string
+	BuildArgs        map[string]*string
+	Target           string
+	ImageTag         string
+	Env              []string
+	EnvPassthrough   []string
+	Stdin            *string
 	Script           []string
 	WorkDir          string
 	CopyFiles        bool
+	MountFiles       bool
+	MountReadOnly    bool
 	SoloExecution    bool
+	SessionMode      string
 	Port             []Port
 	CopyIgnore       []string
+	CopyInclude      []string
+	Compression      string
+	CopyStrategy     string
 	IsParallel       bool
-	Previous         *Job
-	ErrorChannel     chan error
+	MinSuccess       int
+	Tolerated        bool
+	Services         []Service
+	Network          string
+	Volumes          []string
+	Privileged       bool
+	CapAdd           []string
+	CapDrop          []string
+	ExtraHosts       []string
+	DNS              []string
+	DNSSearch        []string
+	Hostname         string
+	Labels           map[string]string
+	OnSuccess        *Notification
+	OnFailure        *Notification
+	Cache            *Cache
+	Artifacts        *Artifacts
+	ArtifactFiles    []ArtifactFile
+	Reports          *Reports
+	TestReport       *TestReport
+	Timings          *JobTimings
+	Expects          []string
+	Healthcheck      *container.HealthConfig
+	SkipIfUnchanged  *SkipIfUnchanged
+	ResultCache      bool
+	Detach           bool
+	Uses             string
+	With             map[string]interface{}
+	Outputs          map[string]string
+	CacheHit         bool
+	Cached           bool
+	Status           string
+	Err              error
+	StartedAt        time.Time
+	FinishedAt       time.Time
+	ImageDigest      string
+	ContainerEnv     []string
+	StopGracePeriod  *time.Duration
+	Docker           *Docker
+	Needs            []*Job
+	Group            []*Job
 	Container        container.ContainerCreateCreatedBody
+	ResourceUsage    interfaces.ResourceUsage
 	InfoLog          *log.Logger
+	Output           io.Writer
+	PrefixColor      color.Attribute
+	ScriptOutput     *lineWriter
 	ImageManager     interfaces.ImageManager
 	ContainerManager interfaces.ContainerManager
 	ShellCommander   interfaces.ShellCommander
@@ -30,3 +93,21 @@ type Port struct {
 	Out string
 	In  string
 }
+
+type Service struct {
+	Name        string
+	Image       string
+	Env         []string
+	Healthcheck *container.HealthConfig
+	Container   container.ContainerCreateCreatedBody
+}
+
+type Artifacts struct {
+	Paths       []string
+	Destination string
+}
+
+type ArtifactFile struct {
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+}
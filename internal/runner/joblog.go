@@ -0,0 +1,68 @@
+package runner
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/fatih/color"
+)
+
+// LogDir is where the output of every job is also written to, as
+// <run id>/<job>.log, it is set by the cli and empty writes no log files.
+var LogDir string
+
+// jobLogWriter writes the output of a job to the console and to its log
+// file, a log file that can not be written never hides the console output.
+type jobLogWriter struct {
+	console io.Writer
+	file    io.Writer
+}
+
+func (w jobLogWriter) Write(p []byte) (int, error) {
+	n, err := w.console.Write(p)
+
+	w.file.Write(p)
+
+	return n, err
+}
+
+func jobLogPath(logDir, runID, job string) string {
+	return filepath.Join(logDir, runID, job+".log")
+}
+
+// teeJobLog writes the logs and the script output of the job to a log file
+// under the log directory too, the returned file is closed when the job
+// ended. Without a log directory it returns nil.
+func (r Runner) teeJobLog(currentJob *Job) *os.File {
+	if r.logDir == "" {
+		return nil
+	}
+
+	path := jobLogPath(r.logDir, r.runID, currentJob.Name)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		warnJobLog(currentJob, err)
+		return nil
+	}
+
+	file, err := os.Create(path)
+
+	if err != nil {
+		warnJobLog(currentJob, err)
+		return nil
+	}
+
+	w := r.secrets.writer(jobLogWriter{console: currentJob.Output, file: file})
+
+	currentJob.InfoLog.SetOutput(w)
+	currentJob.ScriptOutput = newScriptOutput(w, currentJob)
+
+	return file
+}
+
+func warnJobLog(currentJob *Job, err error) {
+	color.Set(color.FgYellow)
+	currentJob.InfoLog.Printf("warning: job log could not be created: %s", err)
+	color.Unset()
+}
@@ -0,0 +1,71 @@
+package runner
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/muhammedikinci/pin/internal/interfaces"
+	"github.com/stretchr/testify/assert"
+)
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestJobLogWriterKeepsTheConsoleOutput(t *testing.T) {
+	var console bytes.Buffer
+
+	n, err := jobLogWriter{console: &console, file: failingWriter{}}.Write([]byte("building\n"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 9, n)
+	assert.Equal(t, "building\n", console.String())
+}
+
+func TestJobOutputIsWrittenToTheLogDir(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugin is a shell script")
+	}
+
+	defer func(f func() (interfaces.Client, error)) { newDockerClient = f }(newDockerClient)
+
+	newDockerClient = func() (interfaces.Client, error) {
+		t.Fatal("docker client created for a pipeline of plugin jobs")
+		return nil, nil
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "deploy")
+	os.WriteFile(path, []byte("#!/bin/sh\necho '{\"type\":\"log\",\"message\":\"deployed\"}'\necho 'uploading' >&2\n"), 0755)
+
+	failing := filepath.Join(dir, "check")
+	os.WriteFile(failing, []byte("#!/bin/sh\nexit 1\n"), 0755)
+
+	logDir := filepath.Join(dir, "logs")
+	deploy := &Job{Name: "deploy", Uses: path}
+	check := &Job{Name: "check", Uses: failing}
+	skipped := &Job{Name: "notify", Uses: path, Needs: []*Job{check}}
+
+	r := Runner{runID: "20220515-101500-a1b2c3", hooks: &hookLog{}, logDir: logDir}
+
+	assert.Error(t, r.run(Pipeline{Workflow: []*Job{deploy, check, skipped}}))
+
+	b, err := os.ReadFile(jobLogPath(logDir, "20220515-101500-a1b2c3", "deploy"))
+
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), "Running plugin "+path)
+	assert.Contains(t, string(b), "deployed")
+	assert.Contains(t, string(b), "uploading")
+	assert.Contains(t, string(b), "Job ended")
+	assert.FileExists(t, jobLogPath(logDir, "20220515-101500-a1b2c3", "check"))
+
+	// jobs that did not run get no log file
+	assert.Equal(t, JobStatusSkipped, skipped.Status)
+	assert.NoFileExists(t, jobLogPath(logDir, "20220515-101500-a1b2c3", "notify"))
+}
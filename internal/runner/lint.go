@@ -0,0 +1,354 @@
+package runner
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"gopkg.in/yaml.v3"
+)
+
+// lintFix rewrites a mechanical issue in the pipeline document and returns
+// a description of every change it made.
+type lintFix func(root *yaml.Node) []string
+
+var lintFixes = []lintFix{
+	fixDeprecatedFields,
+	fixPorts,
+}
+
+// Lint prints the warnings of a pipeline file, with fix the mechanical
+// issues are rewritten after showing a diff and asking for confirmation.
+func Lint(filepath string, fix, yes bool) error {
+	return lint(filepath, fix, yes, os.Stdin)
+}
+
+func lint(filepath string, fix, yes bool, input io.Reader) error {
+	if err := checkFileExists(filepath); err != nil {
+		fmt.Println(err)
+		return err
+	}
+
+	content, err := os.ReadFile(filepath)
+
+	if err != nil {
+		fmt.Println(err)
+		return err
+	}
+
+	config, err := decodeConfig(content)
+
+	if err == nil {
+		err = resolveConfigPaths(config, configDir(filepath))
+	}
+
+	if err != nil {
+		fmt.Println(err)
+		return err
+	}
+
+	findings := validateConfig(config)
+
+	var parseErr error
+	warnings := []Warning{}
+
+	for _, finding := range findings {
+		if finding.Severity == FindingError {
+			parseErr = errors.New(finding.Message)
+			continue
+		}
+
+		warnings = append(warnings, Warning{Job: finding.Job, Field: finding.Field, Message: finding.Message})
+	}
+
+	printWarnings(warnings)
+
+	if parseErr != nil {
+		color.Set(color.FgRed)
+		fmt.Printf("error: %s\n", parseErr)
+		color.Unset()
+	}
+
+	if fix {
+		if err := fixPipelineFile(filepath, yes, input); err != nil {
+			fmt.Println(err)
+			return err
+		}
+	}
+
+	if config["version"] == nil {
+		color.Set(color.FgBlue)
+		fmt.Printf("hint: add `version: %d` to reject unknown and deprecated fields instead of warning about them\n", latestSchemaVersion)
+		color.Unset()
+	}
+
+	if parseErr != nil {
+		return parseErr
+	}
+
+	if len(warnings) > 0 {
+		return fmt.Errorf("%d issues found", len(warnings))
+	}
+
+	color.Set(color.FgGreen)
+	fmt.Printf("%s %s has no issues\n", glyph(glyphSuccess), filepath)
+	color.Unset()
+
+	return nil
+}
+
+func fixPipelineFile(filepath string, yes bool, input io.Reader) error {
+	original, err := os.ReadFile(filepath)
+
+	if err != nil {
+		return err
+	}
+
+	fixed, changes, err := fixPipeline(original)
+
+	if err != nil {
+		return err
+	}
+
+	if len(changes) == 0 {
+		fmt.Println("No fixable issues found")
+		return nil
+	}
+
+	for _, change := range changes {
+		fmt.Printf("fix: %s\n", change)
+	}
+
+	fmt.Println()
+	printLineDiff(string(original), string(fixed))
+
+	if !yes && !confirm("Apply these changes?", input) {
+		fmt.Println("Changes not applied")
+		return nil
+	}
+
+	info, err := os.Stat(filepath)
+
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(filepath, fixed, info.Mode()); err != nil {
+		return err
+	}
+
+	color.Set(color.FgGreen)
+	fmt.Printf("%s %s updated\n", glyph(glyphSuccess), filepath)
+	color.Unset()
+
+	return nil
+}
+
+// fixPipeline applies every lint fix to the document, comments are kept
+// because the document is edited as a yaml node tree.
+func fixPipeline(content []byte) ([]byte, []string, error) {
+	var root yaml.Node
+
+	if err := yaml.Unmarshal(content, &root); err != nil {
+		return nil, nil, err
+	}
+
+	changes := []string{}
+
+	for _, fix := range lintFixes {
+		changes = append(changes, fix(&root)...)
+	}
+
+	if len(changes) == 0 {
+		return content, changes, nil
+	}
+
+	var buf bytes.Buffer
+
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(&root); err != nil {
+		return nil, nil, err
+	}
+
+	encoder.Close()
+
+	return buf.Bytes(), changes, nil
+}
+
+type jobNode struct {
+	name    string
+	mapping *yaml.Node
+}
+
+// jobNodes returns the mapping of every job in the order of the document.
+func jobNodes(root *yaml.Node) []jobNode {
+	jobs := []jobNode{}
+
+	if root.Kind != yaml.DocumentNode || len(root.Content) == 0 || root.Content[0].Kind != yaml.MappingNode {
+		return jobs
+	}
+
+	doc := root.Content[0]
+
+	for i := 0; i+1 < len(doc.Content); i += 2 {
+		if doc.Content[i+1].Kind == yaml.MappingNode && !knownPipelineFields[strings.ToLower(doc.Content[i].Value)] {
+			jobs = append(jobs, jobNode{name: doc.Content[i].Value, mapping: doc.Content[i+1]})
+		}
+	}
+
+	return jobs
+}
+
+// mappingValue finds a key of a mapping case-insensitively, like the parser does.
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if strings.EqualFold(mapping.Content[i].Value, key) {
+			return mapping.Content[i+1]
+		}
+	}
+
+	return nil
+}
+
+// fixPorts normalizes port definitions to quoted "host:container" strings,
+// a single port is published on the same host port.
+func fixPorts(root *yaml.Node) []string {
+	changes := []string{}
+
+	for _, job := range jobNodes(root) {
+		port := mappingValue(job.mapping, "port")
+
+		if port == nil {
+			continue
+		}
+
+		values := []*yaml.Node{port}
+
+		if port.Kind == yaml.SequenceNode {
+			values = port.Content
+		}
+
+		for _, value := range values {
+			original := value.Value
+
+			// "8082 : 8080" is parsed by yaml as a mapping of 8082 to 8080
+			if value.Kind == yaml.MappingNode && len(value.Content) == 2 {
+				original = value.Content[0].Value + " : " + value.Content[1].Value
+				value.Kind = yaml.ScalarNode
+				value.Value = original
+				value.Content = nil
+				value.Style = 0
+			}
+
+			if value.Kind != yaml.ScalarNode {
+				continue
+			}
+
+			parts := strings.Split(value.Value, ":")
+
+			for i := range parts {
+				parts[i] = strings.TrimSpace(parts[i])
+			}
+
+			if len(parts) == 1 {
+				parts = append(parts, parts[0])
+			}
+
+			normalized := strings.Join(parts, ":")
+
+			if normalized == value.Value && value.Style == yaml.DoubleQuotedStyle {
+				continue
+			}
+
+			changes = append(changes, fmt.Sprintf("%s: port %s -> %q", job.name, original, normalized))
+
+			value.Value = normalized
+			value.Tag = "!!str"
+			value.Style = yaml.DoubleQuotedStyle
+		}
+	}
+
+	return changes
+}
+
+func confirm(question string, input io.Reader) bool {
+	fmt.Printf("%s [y/N] ", question)
+
+	answer, _ := bufio.NewReader(input).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+
+	return answer == "y" || answer == "yes"
+}
+
+// printLineDiff prints a line based diff of two texts, removed lines in red
+// and added lines in green.
+func printLineDiff(old, new string) {
+	for _, line := range lineDiff(strings.Split(old, "\n"), strings.Split(new, "\n")) {
+		switch line[0] {
+		case '-':
+			color.Set(color.FgRed)
+		case '+':
+			color.Set(color.FgGreen)
+		}
+
+		fmt.Println(line)
+		color.Unset()
+	}
+}
+
+// lineDiff returns the lines of both texts prefixed with "-", "+" or " "
+// using their longest common subsequence.
+func lineDiff(old, new []string) []string {
+	lcs := make([][]int, len(old)+1)
+
+	for i := range lcs {
+		lcs[i] = make([]int, len(new)+1)
+	}
+
+	for i := len(old) - 1; i >= 0; i-- {
+		for j := len(new) - 1; j >= 0; j-- {
+			if old[i] == new[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	lines := []string{}
+	i, j := 0, 0
+
+	for i < len(old) && j < len(new) {
+		switch {
+		case old[i] == new[j]:
+			lines = append(lines, " "+old[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, "-"+old[i])
+			i++
+		default:
+			lines = append(lines, "+"+new[j])
+			j++
+		}
+	}
+
+	for ; i < len(old); i++ {
+		lines = append(lines, "-"+old[i])
+	}
+
+	for ; j < len(new); j++ {
+		lines = append(lines, "+"+new[j])
+	}
+
+	return lines
+}
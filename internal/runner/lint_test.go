@@ -0,0 +1,66 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const unfixedPipeline = `workflow:
+  - run
+
+# the api server
+run:
+  image: golang:alpine3.15
+  script:
+    - go run .
+  port:
+    - 8082 : 8080
+    - 9090
+`
+
+func TestFixPipelineNormalizesPortsAndKeepsComments(t *testing.T) {
+	fixed, changes, err := fixPipeline([]byte(unfixedPipeline))
+
+	assert.Equal(t, err, nil)
+	assert.Len(t, changes, 2)
+	assert.Contains(t, string(fixed), `- "8082:8080"`)
+	assert.Contains(t, string(fixed), `- "9090:9090"`)
+	assert.Contains(t, string(fixed), "# the api server")
+
+	again, changes, err := fixPipeline(fixed)
+
+	assert.Equal(t, err, nil)
+	assert.Empty(t, changes)
+	assert.Equal(t, fixed, again)
+}
+
+func TestLintFixRewritesFileOnlyAfterConfirmation(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "pipeline.yaml")
+	os.WriteFile(file, []byte(unfixedPipeline), 0644)
+
+	lint(file, true, false, strings.NewReader("n\n"))
+
+	content, _ := os.ReadFile(file)
+	assert.Equal(t, unfixedPipeline, string(content))
+
+	lint(file, true, false, strings.NewReader("y\n"))
+
+	content, _ = os.ReadFile(file)
+	assert.Contains(t, string(content), `"9090:9090"`)
+
+	config, err := readConfig(file)
+	assert.Equal(t, err, nil)
+
+	_, _, err = parse(config)
+	assert.Equal(t, err, nil)
+}
+
+func TestLineDiff(t *testing.T) {
+	lines := lineDiff([]string{"a", "b", "c"}, []string{"a", "x", "c"})
+
+	assert.Equal(t, []string{" a", "-b", "+x", " c"}, lines)
+}
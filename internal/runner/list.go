@@ -0,0 +1,124 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+// List prints the jobs of a pipeline in workflow order with how they run,
+// their images, conditions, retry settings, tags and descriptions, only the
+// jobs carrying the given tag when it is not empty.
+func List(filepath, tag string) error {
+	pipeline, err := loadPipeline(filepath)
+
+	if err != nil {
+		fmt.Println(err)
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "#\tJOB\tMODE\tAFTER\tIMAGE\tCONDITIONS\tRETRY\tTAGS\tDESCRIPTION")
+
+	for _, row := range listRows(pipeline, tag) {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+
+	return w.Flush()
+}
+
+func listRows(pipeline Pipeline, tag string) [][]string {
+	order := map[*Job]int{}
+
+	for i, job := range pipeline.Workflow {
+		order[job] = i + 1
+	}
+
+	rows := [][]string{}
+
+	for _, job := range filterJobsByTag(pipeline.Workflow, tag) {
+		mode := "sequential"
+
+		if job.IsParallel {
+			mode = "parallel"
+		}
+
+		after := []string{}
+
+		for _, need := range job.Needs {
+			after = append(after, need.Name)
+		}
+
+		image := job.Image
+
+		if job.Dockerfile != "" {
+			image += " (" + job.Dockerfile + ")"
+		}
+
+		rows = append(rows, []string{
+			fmt.Sprint(order[job]),
+			job.Name,
+			mode,
+			orDash(strings.Join(after, ",")),
+			image,
+			orDash(strings.Join(jobConditions(job), ",")),
+			orDash(retrySummary(mergeDocker(pipeline.Docker, job.Docker).Retry)),
+			orDash(strings.Join(job.Tags, ",")),
+			job.Description,
+		})
+	}
+
+	return rows
+}
+
+// jobConditions names the settings that can skip the job.
+func jobConditions(job *Job) []string {
+	conditions := []string{}
+
+	if job.SkipIfUnchanged != nil {
+		conditions = append(conditions, "skipIfUnchanged")
+	}
+
+	if job.ResultCache {
+		conditions = append(conditions, "resultCache")
+	}
+
+	return conditions
+}
+
+func retrySummary(retry *DockerRetry) string {
+	if retry == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("docker %dx/%s", retry.Attempts, retry.Delay)
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+
+	return s
+}
+
+func filterJobsByTag(jobs []*Job, tag string) []*Job {
+	if tag == "" {
+		return jobs
+	}
+
+	filtered := []*Job{}
+
+	for _, job := range jobs {
+		for _, t := range job.Tags {
+			if t == tag {
+				filtered = append(filtered, job)
+				break
+			}
+		}
+	}
+
+	return filtered
+}
@@ -0,0 +1,40 @@
+package runner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterJobsByTag(t *testing.T) {
+	build := &Job{Name: "build", Tags: []string{"ci"}}
+	deploy := &Job{Name: "deploy", Tags: []string{"cd", "prod"}}
+	jobs := []*Job{build, deploy}
+
+	assert.Equal(t, jobs, filterJobsByTag(jobs, ""))
+	assert.Equal(t, []*Job{deploy}, filterJobsByTag(jobs, "prod"))
+	assert.Empty(t, filterJobsByTag(jobs, "nightly"))
+}
+
+func TestListRowsShowOrderModeAndSettings(t *testing.T) {
+	build := &Job{Name: "build", Image: "golang:1", SkipIfUnchanged: &SkipIfUnchanged{}, Tags: []string{"ci"}, Description: "Builds"}
+	lint := &Job{Name: "lint", Image: "golangci/golangci-lint", IsParallel: true}
+	test := &Job{Name: "test", Image: "golang:1", IsParallel: true, ResultCache: true}
+	deploy := &Job{Name: "deploy", Dockerfile: "Dockerfile", Image: "deploy-custom:latest", Docker: &Docker{Retry: &DockerRetry{Attempts: 5, Delay: time.Second}}}
+
+	jobs := []*Job{build, lint, test, deploy}
+	linkJobs(jobs)
+
+	rows := listRows(Pipeline{Workflow: jobs}, "")
+
+	assert.Equal(t, []string{"1", "build", "sequential", "-", "golang:1", "skipIfUnchanged", "-", "ci", "Builds"}, rows[0])
+	assert.Equal(t, []string{"2", "lint", "parallel", "-", "golangci/golangci-lint", "-", "-", "-", ""}, rows[1])
+	assert.Equal(t, []string{"3", "test", "parallel", "-", "golang:1", "resultCache", "-", "-", ""}, rows[2])
+	assert.Equal(t, []string{"4", "deploy", "sequential", "lint,test", "deploy-custom:latest (Dockerfile)", "-", "docker 5x/1s", "-", ""}, rows[3])
+
+	rows = listRows(Pipeline{Workflow: jobs}, "ci")
+
+	assert.Equal(t, 1, len(rows))
+	assert.Equal(t, "1", rows[0][0])
+}
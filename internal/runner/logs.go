@@ -0,0 +1,106 @@
+package runner
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Logs prints the recorded output of a detached run, with follow until the
+// run finishes and with job only the lines of that job.
+func Logs(runID string, follow bool, job string) error {
+	var w io.Writer = os.Stdout
+	var filter *jobLineFilter
+
+	if job != "" {
+		filter = &jobLineFilter{w: os.Stdout, job: job}
+		w = filter
+	}
+
+	err := copyRunOutput(runID, w, follow)
+
+	if err == nil && filter != nil {
+		err = filter.Flush()
+	}
+
+	if err != nil {
+		fmt.Println(err)
+		return err
+	}
+
+	return nil
+}
+
+// jobLineFilter passes the lines logged by a job, lines without a job prefix
+// like script output belong to the job that logged the line before them.
+type jobLineFilter struct {
+	w       io.Writer
+	job     string
+	current string
+	partial []byte
+}
+
+func (f *jobLineFilter) Write(p []byte) (int, error) {
+	f.partial = append(f.partial, p...)
+
+	for {
+		i := bytes.IndexByte(f.partial, '\n')
+
+		if i < 0 {
+			return len(p), nil
+		}
+
+		if err := f.line(f.partial[:i+1]); err != nil {
+			return 0, err
+		}
+
+		f.partial = f.partial[i+1:]
+	}
+}
+
+// Flush writes the last line when the output does not end with a newline.
+func (f *jobLineFilter) Flush() error {
+	if len(f.partial) == 0 {
+		return nil
+	}
+
+	err := f.line(f.partial)
+	f.partial = nil
+
+	return err
+}
+
+func (f *jobLineFilter) line(line []byte) error {
+	if job, ok := lineJob(line); ok {
+		f.current = job
+	}
+
+	if f.current != f.job {
+		return nil
+	}
+
+	_, err := f.w.Write(line)
+
+	return err
+}
+
+// lineJob returns the job of a line logged with a job logger, both the
+// unicode and the ascii glyph are accepted.
+func lineJob(line []byte) (string, bool) {
+	for _, glyph := range []string{glyphJob, asciiGlyphs[glyphJob]} {
+		prefix := []byte(glyph + " ")
+
+		if !bytes.HasPrefix(line, prefix) {
+			continue
+		}
+
+		rest := line[len(prefix):]
+
+		if i := bytes.IndexByte(rest, ' '); i > 0 {
+			return string(rest[:i]), true
+		}
+	}
+
+	return "", false
+}
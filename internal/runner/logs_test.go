@@ -0,0 +1,38 @@
+package runner
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobLineFilterKeepsLinesOfJob(t *testing.T) {
+	var out bytes.Buffer
+
+	filter := &jobLineFilter{w: &out, job: "build"}
+
+	filter.Write([]byte("Run ID: 1\n⚉ build Image pulled\n"))
+	filter.Write([]byte("go: downloading\n* test Starting the container\nok\n⚉ bui"))
+	filter.Write([]byte("ld Job ended"))
+	filter.Flush()
+
+	assert.Equal(t, "⚉ build Image pulled\ngo: downloading\n⚉ build Job ended", out.String())
+}
+
+func TestCopyRunOutputWithoutFollowStopsAtEnd(t *testing.T) {
+	t.Setenv("PIN_HOME", t.TempDir())
+
+	id := newRunID()
+	dir, _ := runDir(id)
+
+	os.MkdirAll(dir, 0755)
+	os.WriteFile(filepath.Join(dir, runOutputFile), []byte("⚉ build started\n"), 0644)
+
+	var out bytes.Buffer
+
+	assert.Equal(t, nil, copyRunOutput(id, &out, false))
+	assert.Equal(t, "⚉ build started\n", out.String())
+}
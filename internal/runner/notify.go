@@ -0,0 +1,164 @@
+package runner
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// Notification is an onSuccess or onFailure block, the script runs on the
+// host and the webhook receives the run metadata as json.
+type Notification struct {
+	Script  []string
+	Webhook string
+}
+
+type jobNotificationPayload struct {
+	RunID string      `json:"runId"`
+	Job   JobSnapshot `json:"job"`
+}
+
+var webhookClient = &http.Client{Timeout: 10 * time.Second}
+
+func getNotification(notification interface{}) (*Notification, error) {
+	if notification == nil {
+		return nil, nil
+	}
+
+	notificationMap := getStringMap(notification)
+
+	script, err := getStringArray("notification script", notificationMap["script"])
+
+	if err != nil {
+		return nil, err
+	}
+	webhook, _ := notificationMap["webhook"].(string)
+
+	if len(script) == 0 && webhook == "" {
+		return nil, errors.New("notification needs a script or a webhook")
+	}
+
+	return &Notification{Script: script, Webhook: webhook}, nil
+}
+
+func (r Runner) notifyJob(currentJob *Job) {
+	notification := currentJob.OnSuccess
+
+	if currentJob.Status == JobStatusFailed || currentJob.Status == JobStatusCancelled {
+		notification = currentJob.OnFailure
+	}
+
+	if notification == nil {
+		return
+	}
+
+	env := []string{
+		"PIN_RUN_ID=" + r.runID,
+		"PIN_JOB=" + currentJob.Name,
+		"PIN_STATUS=" + currentJob.Status,
+	}
+
+	sendNotification(*notification, currentJob.InfoLog, env, jobNotificationPayload{
+		RunID: r.runID,
+		Job:   newJobSnapshot(currentJob),
+	})
+}
+
+func (r Runner) notifyPipeline(pipeline Pipeline, run RunMetadata) {
+	notification := pipeline.OnSuccess
+
+	if run.Status != JobStatusSuccess {
+		notification = pipeline.OnFailure
+	}
+
+	if notification == nil {
+		return
+	}
+
+	env := []string{
+		"PIN_RUN_ID=" + run.ID,
+		"PIN_PIPELINE=" + run.Pipeline,
+		"PIN_STATUS=" + run.Status,
+	}
+
+	sendNotification(*notification, r.pipelineLogger(), env, run)
+}
+
+// sendNotification never fails the pipeline, errors are printed as warnings.
+func sendNotification(notification Notification, infoLog *log.Logger, env []string, payload interface{}) {
+	for _, script := range notification.Script {
+		infoLog.Printf("Notification command: %s", script)
+
+		output, err := hostCommand(script, env).CombinedOutput()
+
+		if len(output) != 0 {
+			fmt.Println("\n" + string(output))
+		}
+
+		if err != nil {
+			color.Set(color.FgYellow)
+			infoLog.Printf("warning: notification command failed: %s", err)
+			color.Unset()
+		}
+	}
+
+	if notification.Webhook == "" {
+		return
+	}
+
+	if err := postWebhook(notification.Webhook, payload); err != nil {
+		color.Set(color.FgYellow)
+		infoLog.Printf("warning: notification webhook failed: %s", err)
+		color.Unset()
+		return
+	}
+
+	infoLog.Println("Notification webhook sent")
+}
+
+func postWebhook(url string, payload interface{}) error {
+	b, err := json.Marshal(payload)
+
+	if err != nil {
+		return err
+	}
+
+	resp, err := webhookClient.Post(url, "application/json", bytes.NewReader(b))
+
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	return nil
+}
+
+// hostCommand prepares a command that runs on the host, not in a container,
+// with the given variables added to the environment of pin.
+func hostCommand(command string, env []string) *exec.Cmd {
+	var cmd *exec.Cmd
+
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", command)
+	} else {
+		cmd = exec.Command("sh", "-c", command)
+	}
+
+	cmd.Env = append(os.Environ(), env...)
+
+	return cmd
+}
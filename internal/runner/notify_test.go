@@ -0,0 +1,71 @@
+package runner
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetNotificationNeedsScriptOrWebhook(t *testing.T) {
+	notification, err := getNotification(map[string]interface{}{
+		"script":  "echo failed",
+		"webhook": "https://hooks.example.com/pin",
+	})
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, &Notification{Script: []string{"echo failed"}, Webhook: "https://hooks.example.com/pin"}, notification)
+
+	notification, err = getNotification(nil)
+
+	assert.Equal(t, err, nil)
+	assert.Nil(t, notification)
+
+	_, err = getNotification(map[string]interface{}{})
+
+	assert.EqualError(t, err, "notification needs a script or a webhook")
+}
+
+func TestNotifyJobRunsTheFailureNotification(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the script uses sh")
+	}
+
+	var payload jobNotificationPayload
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		b, _ := io.ReadAll(req.Body)
+		json.Unmarshal(b, &payload)
+	}))
+
+	defer server.Close()
+
+	output := filepath.Join(t.TempDir(), "status")
+
+	job := &Job{
+		Name:      "test",
+		Status:    JobStatusFailed,
+		InfoLog:   log.New(io.Discard, "", 0),
+		OnSuccess: &Notification{Script: []string{"exit 1"}},
+		OnFailure: &Notification{
+			Script:  []string{"echo $PIN_JOB $PIN_STATUS > " + output},
+			Webhook: server.URL,
+		},
+	}
+
+	Runner{runID: "run-1"}.notifyJob(job)
+
+	b, err := os.ReadFile(output)
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, "test failed\n", string(b))
+	assert.Equal(t, "run-1", payload.RunID)
+	assert.Equal(t, "test", payload.Job.Name)
+}
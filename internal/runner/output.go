@@ -0,0 +1,257 @@
+package runner
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/fatih/color"
+	"github.com/muhammedikinci/pin/internal/log_level"
+)
+
+// ASCIIOutput replaces the glyphs in logs with plain markers, it defaults to
+// true on terminals that are unlikely to render them and is set by the cli.
+var ASCIIOutput = !supportsUnicode()
+
+const (
+	glyphJob     = "⚉"
+	glyphSuccess = "✅"
+	glyphFailure = "❌"
+)
+
+var asciiGlyphs = map[string]string{
+	glyphJob:     "*",
+	glyphSuccess: "[ok]",
+	glyphFailure: "[x]",
+}
+
+func glyph(g string) string {
+	if ASCIIOutput {
+		return asciiGlyphs[g]
+	}
+
+	return g
+}
+
+// supportsUnicode guesses from the locale whether the terminal can render
+// the glyphs, legacy windows consoles are detected by the missing markers of
+// windows terminal and modern editors.
+func supportsUnicode() bool {
+	if runtime.GOOS == "windows" {
+		return os.Getenv("WT_SESSION") != "" || os.Getenv("TERM_PROGRAM") != ""
+	}
+
+	locale := ""
+
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if locale = os.Getenv(name); locale != "" {
+			break
+		}
+	}
+
+	if locale == "" {
+		return true
+	}
+
+	locale = strings.ToLower(locale)
+
+	return strings.Contains(locale, "utf-8") || strings.Contains(locale, "utf8")
+}
+
+// quietOutput holds the output of a job in quiet mode, it is only shown when
+// the job fails.
+type quietOutput struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (q *quietOutput) Write(p []byte) (int, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.buf.Write(p)
+}
+
+// stdoutMu serializes the writes of all jobs to stdout.
+var stdoutMu sync.Mutex
+
+// sharedStdout is the stdout every job writes to, each write is done under
+// stdoutMu so writes of parallel jobs never interleave. os.Stdout is looked
+// up on every write because --output moves the logs to stderr.
+type sharedStdout struct{}
+
+func (sharedStdout) Write(p []byte) (int, error) {
+	stdoutMu.Lock()
+	defer stdoutMu.Unlock()
+
+	return os.Stdout.Write(p)
+}
+
+// jobColors tell the prefixes of the jobs apart, the colors of success,
+// failure and warning messages are left out.
+var jobColors = []color.Attribute{color.FgCyan, color.FgMagenta, color.FgBlue, color.FgHiCyan, color.FgHiMagenta, color.FgHiBlue}
+
+var sgrPattern = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// lastColor is the color the color package set last, the terminal prints
+// with it until the next reset.
+var lastColor struct {
+	sync.Mutex
+	sgr string
+}
+
+func init() {
+	color.Output = colorTracker{w: color.Output}
+}
+
+// colorTracker updates lastColor with the escape codes written through the
+// color package.
+type colorTracker struct {
+	w io.Writer
+}
+
+func (t colorTracker) Write(p []byte) (int, error) {
+	if codes := sgrPattern.FindAll(p, -1); len(codes) > 0 {
+		sgr := string(codes[len(codes)-1])
+
+		// a reset needs nothing to be set again
+		if sgr == "\x1b[0m" {
+			sgr = ""
+		}
+
+		lastColor.Lock()
+		lastColor.sgr = sgr
+		lastColor.Unlock()
+	}
+
+	return t.w.Write(p)
+}
+
+// jobStdout colors the prefix of every line of a job before writing it to
+// sharedStdout, the color that was set for the message is set again after
+// the prefix.
+type jobStdout struct {
+	prefix string
+	color  color.Attribute
+}
+
+func (o jobStdout) Write(p []byte) (int, error) {
+	if color.NoColor || !bytes.HasPrefix(p, []byte(o.prefix)) {
+		return sharedStdout{}.Write(p)
+	}
+
+	lastColor.Lock()
+	sgr := lastColor.sgr
+	lastColor.Unlock()
+
+	line := color.New(o.color).Sprint(strings.TrimSuffix(o.prefix, " ")) + sgr + " "
+
+	if _, err := (sharedStdout{}).Write(append([]byte(line), p[len(o.prefix):]...)); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// jobPrefix is written before every line a job logs and its script prints.
+func jobPrefix(currentJob *Job) string {
+	return glyph(glyphJob) + " " + currentJob.Name + " "
+}
+
+// lineWriter passes only whole lines to w, each tagged with prefix, so the
+// script output of parallel jobs can not be split mid-line.
+type lineWriter struct {
+	mu      sync.Mutex
+	w       io.Writer
+	prefix  []byte
+	partial []byte
+}
+
+func newLineWriter(w io.Writer, prefix string) *lineWriter {
+	return &lineWriter{w: w, prefix: []byte(prefix)}
+}
+
+func (lw *lineWriter) Write(p []byte) (int, error) {
+	lw.mu.Lock()
+	defer lw.mu.Unlock()
+
+	lw.partial = append(lw.partial, p...)
+
+	for {
+		i := bytes.IndexByte(lw.partial, '\n')
+
+		if i < 0 {
+			return len(p), nil
+		}
+
+		if err := lw.line(lw.partial[:i+1]); err != nil {
+			return 0, err
+		}
+
+		lw.partial = lw.partial[i+1:]
+	}
+}
+
+// Flush writes the last line when the output does not end with a newline.
+func (lw *lineWriter) Flush() error {
+	lw.mu.Lock()
+	defer lw.mu.Unlock()
+
+	if len(lw.partial) == 0 {
+		return nil
+	}
+
+	err := lw.line(append(lw.partial, '\n'))
+	lw.partial = nil
+
+	return err
+}
+
+func (lw *lineWriter) line(line []byte) error {
+	_, err := lw.w.Write(append(append([]byte{}, lw.prefix...), line...))
+
+	return err
+}
+
+func newJobOutput(currentJob *Job) io.Writer {
+	if log_level.IsQuiet() {
+		return &quietOutput{}
+	}
+
+	return jobStdout{prefix: jobPrefix(currentJob), color: currentJob.PrefixColor}
+}
+
+// newScriptOutput tags the script output of a job with the job prefix, like
+// the lines of its logger, so logs --job can tell the jobs apart.
+func newScriptOutput(w io.Writer, currentJob *Job) *lineWriter {
+	return newLineWriter(w, jobPrefix(currentJob))
+}
+
+// flushJobOutput prints the held output of a failed job in quiet mode.
+func flushJobOutput(w io.Writer) {
+	q, ok := w.(*quietOutput)
+
+	if !ok {
+		return
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	sharedStdout{}.Write(q.buf.Bytes())
+	q.buf.Reset()
+}
+
+// jobWriter returns where the command output of a job goes, jobs that did
+// not go through jobRunner write to stdout.
+func jobWriter(currentJob Job) io.Writer {
+	if currentJob.ScriptOutput == nil {
+		return os.Stdout
+	}
+
+	return currentJob.ScriptOutput
+}
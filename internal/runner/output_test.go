@@ -0,0 +1,152 @@
+package runner
+
+import (
+	"io"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/muhammedikinci/pin/internal/log_level"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSupportsUnicodeFollowsTheLocale(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("locale is not used on windows")
+	}
+
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_CTYPE", "")
+	t.Setenv("LANG", "en_US.UTF-8")
+	assert.True(t, supportsUnicode())
+
+	t.Setenv("LANG", "C")
+	assert.False(t, supportsUnicode())
+
+	t.Setenv("LC_ALL", "tr_TR.utf8")
+	assert.True(t, supportsUnicode())
+}
+
+func TestGlyphUsesASCIIMarkers(t *testing.T) {
+	defer func(v bool) { ASCIIOutput = v }(ASCIIOutput)
+
+	ASCIIOutput = false
+	assert.Equal(t, "✅", glyph(glyphSuccess))
+
+	ASCIIOutput = true
+	assert.Equal(t, "[ok]", glyph(glyphSuccess))
+	assert.Equal(t, "*", glyph(glyphJob))
+}
+
+func TestJobOutputIsHeldInQuietMode(t *testing.T) {
+	defer log_level.Set(log_level.Get())
+
+	log_level.Set(log_level.Info)
+	assert.Equal(t, jobStdout{prefix: jobPrefix(&Job{Name: "build"}), color: color.FgCyan}, newJobOutput(&Job{Name: "build", PrefixColor: color.FgCyan}))
+
+	log_level.Set(log_level.Quiet)
+	output := newJobOutput(&Job{Name: "build"})
+
+	io.WriteString(output, "step 1\n")
+	io.WriteString(output, "step 2\n")
+
+	held, ok := output.(*quietOutput)
+
+	assert.True(t, ok)
+	assert.Equal(t, "step 1\nstep 2\n", held.buf.String())
+}
+
+func TestJobWriterDefaultsToStdout(t *testing.T) {
+	assert.Equal(t, os.Stdout, jobWriter(Job{}))
+
+	output := newLineWriter(&quietOutput{}, "")
+
+	assert.Equal(t, output, jobWriter(Job{ScriptOutput: output}))
+}
+
+func TestLineWriterWritesWholeTaggedLines(t *testing.T) {
+	var writes []string
+
+	w := newLineWriter(writerFunc(func(p []byte) (int, error) {
+		writes = append(writes, string(p))
+		return len(p), nil
+	}), "* build ")
+
+	io.WriteString(w, "compil")
+	io.WriteString(w, "ing\r\nlinking\ndone")
+
+	assert.Equal(t, []string{"* build compiling\r\n", "* build linking\n"}, writes)
+
+	w.Flush()
+
+	assert.Equal(t, "* build done\n", writes[2])
+}
+
+func TestLineWritersOfParallelJobsDoNotInterleave(t *testing.T) {
+	output := &quietOutput{}
+
+	var wg sync.WaitGroup
+
+	for _, job := range []string{"a", "b"} {
+		wg.Add(1)
+
+		go func(job string) {
+			defer wg.Done()
+
+			w := newLineWriter(output, job+" ")
+
+			for i := 0; i < 100; i++ {
+				io.WriteString(w, strings.Repeat(job, 10))
+				io.WriteString(w, strings.Repeat(job, 10)+"\n")
+			}
+		}(job)
+	}
+
+	wg.Wait()
+
+	for _, line := range strings.Split(strings.TrimSuffix(output.buf.String(), "\n"), "\n") {
+		assert.True(t, line == "a "+strings.Repeat("a", 20) || line == "b "+strings.Repeat("b", 20), line)
+	}
+}
+
+type writerFunc func(p []byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) {
+	return f(p)
+}
+
+func TestJobStdoutColorsThePrefixAndKeepsTheMessageColor(t *testing.T) {
+	defer func(noColor bool) { color.NoColor = noColor }(color.NoColor)
+	defer func(ascii bool) { ASCIIOutput = ascii }(ASCIIOutput)
+	defer func(stdout *os.File) { os.Stdout = stdout }(os.Stdout)
+
+	color.NoColor = false
+	ASCIIOutput = true
+
+	r, w, _ := os.Pipe()
+
+	os.Stdout = w
+
+	output := newJobOutput(&Job{Name: "build", PrefixColor: color.FgCyan})
+
+	colorTracker{w: io.Discard}.Write([]byte("\x1b[32m"))
+	io.WriteString(output, "* build Job ended\n")
+
+	colorTracker{w: io.Discard}.Write([]byte("\x1b[0m"))
+	io.WriteString(output, "* build Execute command: ls\n")
+	io.WriteString(output, "no prefix\n")
+
+	color.NoColor = true
+	io.WriteString(output, "* build plain\n")
+
+	w.Close()
+	out, _ := io.ReadAll(r)
+
+	assert.Equal(t, "\x1b[36m* build\x1b[0m\x1b[32m Job ended\n"+
+		"\x1b[36m* build\x1b[0m Execute command: ls\n"+
+		"no prefix\n"+
+		"* build plain\n", string(out))
+}
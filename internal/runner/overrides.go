@@ -0,0 +1,138 @@
+package runner
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// applySet overrides a value of the configuration with a path=value entry
+// of --set. The path is made of keys separated by dots, missing mappings
+// are created, and the value is parsed as yaml so numbers, booleans and
+// lists keep their type.
+func applySet(config map[string]interface{}, entry string) error {
+	path, raw, found := strings.Cut(entry, "=")
+
+	if !found || path == "" {
+		return fmt.Errorf("invalid --set entry: %s", entry)
+	}
+
+	var value interface{}
+
+	if err := yaml.Unmarshal([]byte(raw), &value); err != nil {
+		value = raw
+	}
+
+	if m, ok := value.(map[string]interface{}); ok {
+		lowerKeys(m)
+	}
+
+	keys := strings.Split(strings.ToLower(path), ".")
+	current := config
+
+	for i, key := range keys[:len(keys)-1] {
+		next, ok := current[key]
+
+		if !ok || next == nil {
+			m := map[string]interface{}{}
+			current[key] = m
+			current = m
+
+			continue
+		}
+
+		m, ok := next.(map[string]interface{})
+
+		if !ok {
+			return fmt.Errorf("invalid --set entry: %s is not a mapping", strings.Join(keys[:i+1], "."))
+		}
+
+		current = m
+	}
+
+	current[keys[len(keys)-1]] = value
+
+	return nil
+}
+
+// applyEnv appends the KEY=VALUE entries of --env to the env of every job
+// in the workflow, they override the variables of the pipeline.
+func applyEnv(config map[string]interface{}, env []string) error {
+	for _, v := range env {
+		if !strings.Contains(v, "=") {
+			return fmt.Errorf("invalid --env entry: %s", v)
+		}
+	}
+
+	workflow, err := getStringArray("workflow", config["workflow"])
+
+	if err != nil {
+		return err
+	}
+
+	for _, name := range workflow {
+		job, ok := config[strings.ToLower(name)].(map[string]interface{})
+
+		if !ok {
+			continue
+		}
+
+		vars, err := getStringArray("env", job["env"])
+
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+
+		jobEnv := []interface{}{}
+
+		for _, v := range vars {
+			jobEnv = append(jobEnv, v)
+		}
+
+		for _, v := range env {
+			jobEnv = append(jobEnv, v)
+		}
+
+		job["env"] = jobEnv
+	}
+
+	return nil
+}
+
+// loadApplyConfig reads the pipeline files and applies the --set and --env
+// overrides, the saved document includes the overrides so a rerun behaves
+// the same.
+func loadApplyConfig(filepaths []string, options ApplyOptions) (map[string]interface{}, []byte, error) {
+	config, content, err := loadConfig(filepaths)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(options.Set) == 0 && len(options.Env) == 0 && options.MaxParallel == 0 {
+		return config, content, nil
+	}
+
+	if options.MaxParallel > 0 {
+		config["maxparallel"] = options.MaxParallel
+	}
+
+	for _, entry := range options.Set {
+		if err := applySet(config, entry); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if err := applyEnv(config, options.Env); err != nil {
+		return nil, nil, err
+	}
+
+	content, err = yaml.Marshal(config)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return config, content, nil
+}
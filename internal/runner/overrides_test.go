@@ -0,0 +1,62 @@
+package runner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplySetOverridesYamlPaths(t *testing.T) {
+	config, _ := decodeConfig([]byte(`
+workflow:
+  - build
+
+build:
+  image: golang:alpine
+  script:
+    - go build ./...
+`))
+
+	assert.Equal(t, nil, applySet(config, "Build.Image=golang:1.18-alpine"))
+	assert.Equal(t, nil, applySet(config, "build.parallel=true"))
+	assert.Equal(t, nil, applySet(config, "build.docker.retry.attempts=5"))
+	assert.Equal(t, nil, applySet(config, "build.script=[go vet ./..., go test ./...]"))
+
+	pipeline, _, err := parse(config)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "golang:1.18-alpine", pipeline.Workflow[0].Image)
+	assert.Equal(t, true, pipeline.Workflow[0].IsParallel)
+	assert.Equal(t, 5, pipeline.Workflow[0].Docker.Retry.Attempts)
+	assert.Equal(t, []string{"go vet ./...", "go test ./..."}, pipeline.Workflow[0].Script)
+
+	assert.EqualError(t, applySet(config, "build.image"), "invalid --set entry: build.image")
+	assert.EqualError(t, applySet(config, "build.image.name=alpine"), "invalid --set entry: build.image is not a mapping")
+}
+
+func TestApplyEnvAddsEnvToEveryJob(t *testing.T) {
+	config, _ := decodeConfig([]byte(`
+workflow:
+  - build
+  - test
+
+build:
+  image: golang:alpine
+  env:
+    - CGO_ENABLED=1
+    - GOOS=linux
+
+test:
+  image: golang:alpine
+`))
+
+	assert.Equal(t, nil, applyEnv(config, []string{"CGO_ENABLED=0"}))
+
+	pipeline, _, err := parse(config)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []string{"CGO_ENABLED=0", "GOOS=linux"}, pipeline.Workflow[0].Env)
+	assert.Equal(t, []string{"CGO_ENABLED=0"}, pipeline.Workflow[1].Env)
+
+	assert.EqualError(t, applyEnv(config, []string{"CGO_ENABLED"}), "invalid --env entry: CGO_ENABLED")
+}
@@ -2,80 +2,587 @@ package runner
 
 import (
 	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
 	"reflect"
+	"regexp"
+	"sort"
 	"strings"
+	"time"
 
-	"github.com/spf13/viper"
+	"github.com/muhammedikinci/pin/internal/interfaces"
 )
 
 type Pipeline struct {
-	Workflow     []*Job
-	LogsWithTime bool
+	// Version is the declared version of the pipeline format.
+	Version         int
+	Workflow        []*Job
+	LogsWithTime    bool
+	OnSuccess       *Notification
+	OnFailure       *Notification
+	HostHooks       map[string]HostHook
+	SuccessCriteria *SuccessCriteria
+	Docker          *Docker
+	GitNotes        bool
+	// MaxParallel limits how many jobs run at the same time, 0 runs every
+	// ready job.
+	MaxParallel int
+	// FailFast cancels the running jobs when a job fails, without it the
+	// jobs that do not need the failed job keep running.
+	FailFast bool
+	// Env is inherited by every job, job variables override it.
+	Env []string
 }
 
-func parse() (Pipeline, error) {
+func parse(config map[string]interface{}) (Pipeline, []Warning, error) {
 	var pipeline Pipeline = Pipeline{}
 
-	flows := viper.GetStringSlice("workflow")
+	flows, err := getStringArray("workflow", config["workflow"])
 
-	for i, v := range flows {
-		configMap := viper.GetStringMap(v)
+	if err != nil {
+		return Pipeline{}, []Warning{}, err
+	}
+
+	warnings := pipelineWarnings(config, flows)
+
+	version, err := getSchemaVersion(config["version"])
+
+	if err != nil {
+		return Pipeline{}, warnings, err
+	}
+
+	pipeline.Version = version
+
+	migratePipelineFields(config)
+
+	if err := strictWarnings(warnings, version); err != nil {
+		return Pipeline{}, warnings, err
+	}
+
+	plugins, err := getPlugins(config["plugins"])
+
+	if err != nil {
+		return Pipeline{}, warnings, fmt.Errorf("plugins: %w", err)
+	}
+
+	if pipeline.Env, err = getEnv(config["env"], config["envfile"]); err != nil {
+		return Pipeline{}, warnings, fmt.Errorf("env: %w", err)
+	}
+
+	for _, v := range flows {
+		configMap := getStringMap(config[strings.ToLower(v)])
+		migrateJobFields(configMap)
 
 		job, err := generateJob(configMap)
 
 		if err != nil {
-			return Pipeline{}, err
+			return Pipeline{}, warnings, err
 		}
 
 		job.Name = v
+		job.Env = mergeEnv(pipeline.Env, job.Env)
+
+		if job.Uses != "" {
+			if job.Uses, err = resolvePlugin(plugins, job.Uses); err != nil {
+				return Pipeline{}, warnings, err
+			}
+		}
+
+		if job.Dockerfile != "" {
+			job.Image = job.ImageTag
+
+			if job.Image == "" {
+				job.Image = v + "-custom:latest"
+			}
+		}
+
+		jobWarnings := jobWarnings(v, configMap, job)
+		warnings = append(warnings, jobWarnings...)
+
+		if err := strictWarnings(jobWarnings, version); err != nil {
+			return Pipeline{}, warnings, err
+		}
+
+		pipeline.Workflow = append(pipeline.Workflow, job)
+	}
+
+	linkJobs(pipeline.Workflow)
+
+	if err := checkMinSuccess(pipeline.Workflow); err != nil {
+		return Pipeline{}, warnings, err
+	}
+
+	pipeline.LogsWithTime, _ = config["logswithtime"].(bool)
+	pipeline.GitNotes, _ = config["gitnotes"].(bool)
+
+	if pipeline.MaxParallel, err = getMaxParallel(config["maxparallel"]); err != nil {
+		return Pipeline{}, warnings, err
+	}
+
+	if config["failfast"] != nil {
+		failFast, ok := config["failfast"].(bool)
+
+		if !ok {
+			return Pipeline{}, warnings, fmt.Errorf("invalid failFast: %v", config["failfast"])
+		}
+
+		pipeline.FailFast = failFast
+	}
+
+	onSuccess, err := getNotification(config["onsuccess"])
+
+	if err != nil {
+		return Pipeline{}, warnings, fmt.Errorf("onSuccess: %w", err)
+	}
+
+	onFailure, err := getNotification(config["onfailure"])
+
+	if err != nil {
+		return Pipeline{}, warnings, fmt.Errorf("onFailure: %w", err)
+	}
+
+	pipeline.OnSuccess = onSuccess
+	pipeline.OnFailure = onFailure
+
+	hostHooks, err := getHostHooks(config["hosthooks"])
+
+	if err != nil {
+		return Pipeline{}, warnings, fmt.Errorf("hostHooks: %w", err)
+	}
+
+	pipeline.HostHooks = hostHooks
+
+	successCriteria, err := getSuccessCriteria(config["successcriteria"])
+
+	if err != nil {
+		return Pipeline{}, warnings, fmt.Errorf("successCriteria: %w", err)
+	}
+
+	pipeline.SuccessCriteria = successCriteria
+
+	docker, err := getDocker(config["docker"])
+
+	if err != nil {
+		return Pipeline{}, warnings, fmt.Errorf("docker: %w", err)
+	}
+
+	pipeline.Docker = docker
+
+	return pipeline, warnings, nil
+}
+
+// linkJobs builds the execution graph of the workflow. A sequential job
+// needs the job before it, or every job of the parallel group before it.
+// Parallel jobs need nothing and start with the run, consecutive parallel
+// jobs form a group.
+func linkJobs(jobs []*Job) {
+	for _, job := range jobs {
+		job.Needs = nil
+		job.Group = nil
+	}
+
+	for _, group := range parallelGroups(jobs) {
+		for _, job := range group {
+			job.Group = group
+		}
+	}
+
+	for i, job := range jobs {
+		if i == 0 || job.IsParallel {
+			continue
+		}
+
+		if previous := jobs[i-1]; previous.IsParallel {
+			job.Needs = previous.Group
+		} else {
+			job.Needs = []*Job{previous}
+		}
+	}
+}
 
-		if i > 0 && (!job.IsParallel || !pipeline.Workflow[i-1].IsParallel) {
-			job.Previous = pipeline.Workflow[i-1]
+// selectJobs keeps only the named jobs of the workflow and links them again.
+func selectJobs(pipeline Pipeline, names map[string]bool) Pipeline {
+	selected := []*Job{}
+
+	for _, job := range pipeline.Workflow {
+		if names[job.Name] {
+			selected = append(selected, job)
 		}
+	}
+
+	linkJobs(selected)
+	pipeline.Workflow = selected
+
+	return pipeline
+}
+
+// onlyJobs keeps the jobs given with --jobs, every name must be a job of the
+// workflow. The jobs keep their workflow order.
+func onlyJobs(pipeline Pipeline, names []string) (Pipeline, error) {
+	workflow := map[string]bool{}
+
+	for _, job := range pipeline.Workflow {
+		workflow[job.Name] = true
+	}
+
+	selected := map[string]bool{}
+
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+
+		if name == "" {
+			continue
+		}
+
+		if !workflow[name] {
+			return pipeline, fmt.Errorf("job %s is not in the workflow", name)
+		}
+
+		selected[name] = true
+	}
+
+	if len(selected) == 0 {
+		return pipeline, errors.New("--jobs needs at least one job name")
+	}
+
+	return selectJobs(pipeline, selected), nil
+}
+
+func generateJob(configMap map[string]interface{}) (*Job, error) {
+	if configMap["uses"] != nil {
+		return generatePluginJob(configMap)
+	}
+
+	dockerfile, _ := configMap["dockerfile"].(string)
+
+	if dockerfile != "" && configMap["image"] != nil {
+		return &Job{}, errors.New("image and dockerfile can not be used together")
+	}
+
+	image := ""
+
+	if dockerfile == "" {
+		var err error
+		image, err = getJobImage(configMap["image"])
+
+		if err != nil {
+			return &Job{}, err
+		}
+	}
+
+	imageFallbacks, err := getStringArray("imageFallbacks", configMap["imagefallbacks"])
+
+	if err != nil {
+		return &Job{}, err
+	}
+
+	if dockerfile != "" && len(imageFallbacks) > 0 {
+		return &Job{}, errors.New("imageFallbacks can not be used with dockerfile")
+	}
+
+	target, _ := configMap["target"].(string)
+	imageTag, _ := configMap["imagetag"].(string)
+
+	buildArgs, err := getBuildArgs(configMap["buildargs"])
+
+	if err != nil {
+		return &Job{}, err
+	}
+
+	workDir, err := getWorkDir(configMap["workdir"])
+
+	if err != nil {
+		return &Job{}, err
+	}
+
+	copyFiles, err := getCopyFiles(configMap["copyfiles"])
+
+	if err != nil {
+		return &Job{}, err
+	}
+
+	mountFiles, mountReadOnly, err := getMountFiles(configMap["mountfiles"])
+
+	if err != nil {
+		return &Job{}, err
+	}
+
+	if copyFiles && mountFiles {
+		return &Job{}, errors.New("copyFiles and mountFiles can not be used together")
+	}
+
+	services, err := getServices(configMap["services"])
+
+	if err != nil {
+		return &Job{}, err
+	}
+
+	detach, err := getBool("detach", configMap["detach"], false)
+
+	if err != nil {
+		return &Job{}, err
+	}
+
+	if detach && len(services) > 0 {
+		return &Job{}, errors.New("detach and services can not be used together")
+	}
+
+	stopGracePeriod, err := getDuration(configMap["stopgraceperiod"])
+
+	if err != nil {
+		return &Job{}, err
+	}
+
+	docker, err := getDocker(configMap["docker"])
+
+	if err != nil {
+		return &Job{}, fmt.Errorf("docker: %w", err)
+	}
+
+	volumes, err := getVolumes(configMap["volumes"])
+
+	if err != nil {
+		return &Job{}, err
+	}
+
+	cache, err := getCache(configMap["cache"])
+
+	if err != nil {
+		return &Job{}, err
+	}
+
+	artifacts, err := getArtifacts(configMap["artifacts"])
+
+	if err != nil {
+		return &Job{}, err
+	}
+
+	reports, err := getReports(configMap["reports"])
+
+	if err != nil {
+		return &Job{}, fmt.Errorf("reports: %w", err)
+	}
+
+	skipIfUnchanged, err := getSkipIfUnchanged(configMap["skipifunchanged"])
+
+	if err != nil {
+		return &Job{}, err
+	}
+
+	compression, err := getCompression(configMap["compression"])
+
+	if err != nil {
+		return &Job{}, err
+	}
+
+	copyStrategy, err := getCopyStrategy(configMap["copystrategy"])
+
+	if err != nil {
+		return &Job{}, err
+	}
+
+	sessionMode, err := getSessionMode(configMap["sessionmode"])
+
+	if err != nil {
+		return &Job{}, err
+	}
+
+	extraHosts, err := getExtraHosts(configMap["extrahosts"])
+
+	if err != nil {
+		return &Job{}, err
+	}
+
+	dns, err := getDNS(configMap["dns"])
+
+	if err != nil {
+		return &Job{}, err
+	}
+
+	hostname, err := getHostname(configMap["hostname"])
+
+	if err != nil {
+		return &Job{}, err
+	}
+
+	labels, err := getLabels(configMap["labels"])
+
+	if err != nil {
+		return &Job{}, err
+	}
+
+	env, err := getEnv(configMap["env"], configMap["envfile"])
+
+	if err != nil {
+		return &Job{}, err
+	}
+
+	stdin, err := getStdin(configMap["stdin"])
+
+	if err != nil {
+		return &Job{}, err
+	}
+
+	onSuccess, err := getNotification(configMap["onsuccess"])
+
+	if err != nil {
+		return &Job{}, fmt.Errorf("onSuccess: %w", err)
+	}
+
+	onFailure, err := getNotification(configMap["onfailure"])
+
+	if err != nil {
+		return &Job{}, fmt.Errorf("onFailure: %w", err)
+	}
+
+	healthcheck, err := getContainerHealthcheck(configMap["healthcheck"])
+
+	if err != nil {
+		return &Job{}, err
+	}
+
+	port, err := getJobPort(configMap["port"])
+
+	if err != nil {
+		return &Job{}, err
+	}
+
+	minSuccess, err := getMinSuccess(configMap["minsuccess"])
+
+	if err != nil {
+		return &Job{}, err
+	}
+
+	soloExecution, err := getBool("soloExecution", configMap["soloexecution"], false)
+
+	if err != nil {
+		return &Job{}, err
+	}
+
+	isParallel, err := getBool("parallel", configMap["parallel"], false)
+
+	if err != nil {
+		return &Job{}, err
+	}
+
+	resultCache, err := getBool("resultCache", configMap["resultcache"], false)
+
+	if err != nil {
+		return &Job{}, err
+	}
+
+	privileged, err := getBool("privileged", configMap["privileged"], false)
+
+	if err != nil {
+		return &Job{}, err
+	}
+
+	capAdd, err := getStringArray("capAdd", configMap["capadd"])
+
+	if err != nil {
+		return &Job{}, err
+	}
+
+	capDrop, err := getStringArray("capDrop", configMap["capdrop"])
+
+	if err != nil {
+		return &Job{}, err
+	}
+
+	copyIgnore, err := getStringArray("copyIgnore", configMap["copyignore"])
+
+	if err != nil {
+		return &Job{}, err
+	}
+
+	copyInclude, err := getStringArray("copyInclude", configMap["copyinclude"])
+
+	if err != nil {
+		return &Job{}, err
+	}
+
+	script, err := getStringArray("script", configMap["script"])
 
-		pipeline.Workflow = append(pipeline.Workflow, job)
+	if err != nil {
+		return &Job{}, err
 	}
 
-	pipeline.LogsWithTime = viper.GetBool("logsWithTime")
+	dnsSearch, err := getStringArray("dnsSearch", configMap["dnssearch"])
 
-	return pipeline, nil
-}
+	if err != nil {
+		return &Job{}, err
+	}
 
-func generateJob(configMap map[string]interface{}) (*Job, error) {
-	image, err := getJobImage(configMap["image"])
+	tags, err := getStringArray("tags", configMap["tags"])
 
 	if err != nil {
 		return &Job{}, err
 	}
 
-	workDir, err := getWorkDir(configMap["workdir"])
+	expects, err := getStringArray("expects", configMap["expects"])
 
 	if err != nil {
 		return &Job{}, err
 	}
 
-	copyFiles, err := getCopyFiles(configMap["copyfiles"])
+	envPassthrough, err := getStringArray("envPassthrough", configMap["envpassthrough"])
 
 	if err != nil {
 		return &Job{}, err
 	}
 
-	soloExecution := getBool(configMap["soloexecution"], false)
-	isParallel := getBool(configMap["parallel"], false)
-	copyIgnore := getStringArray(configMap["copyignore"])
-	script := getStringArray(configMap["script"])
-	port := getJobPort(configMap["port"])
+	description, _ := configMap["description"].(string)
 
 	var job *Job = &Job{
-		Image:         image,
-		Script:        script,
-		CopyFiles:     copyFiles,
-		WorkDir:       workDir,
-		SoloExecution: soloExecution,
-		IsParallel:    isParallel,
-		Port:          port,
-		CopyIgnore:    copyIgnore,
-		ErrorChannel:  make(chan error, 1),
+		Description:     description,
+		Tags:            tags,
+		Image:           image,
+		ImageFallbacks:  imageFallbacks,
+		Dockerfile:      dockerfile,
+		BuildArgs:       buildArgs,
+		Target:          target,
+		ImageTag:        imageTag,
+		Env:             env,
+		EnvPassthrough:  envPassthrough,
+		Stdin:           stdin,
+		Script:          script,
+		CopyFiles:       copyFiles,
+		MountFiles:      mountFiles,
+		MountReadOnly:   mountReadOnly,
+		WorkDir:         workDir,
+		SoloExecution:   soloExecution,
+		SessionMode:     sessionMode,
+		IsParallel:      isParallel,
+		MinSuccess:      minSuccess,
+		Port:            port,
+		CopyIgnore:      copyIgnore,
+		CopyInclude:     copyInclude,
+		Compression:     compression,
+		CopyStrategy:    copyStrategy,
+		Services:        services,
+		StopGracePeriod: stopGracePeriod,
+		Docker:          docker,
+		Volumes:         volumes,
+		Privileged:      privileged,
+		CapAdd:          capAdd,
+		CapDrop:         capDrop,
+		ExtraHosts:      extraHosts,
+		DNS:             dns,
+		DNSSearch:       dnsSearch,
+		Hostname:        hostname,
+		Labels:          labels,
+		OnSuccess:       onSuccess,
+		OnFailure:       onFailure,
+		Cache:           cache,
+		Artifacts:       artifacts,
+		Reports:         reports,
+		Expects:         expects,
+		Healthcheck:     healthcheck,
+		SkipIfUnchanged: skipIfUnchanged,
+		ResultCache:     resultCache,
+		Detach:          detach,
 	}
 
 	return job, nil
@@ -86,51 +593,481 @@ func getJobImage(image interface{}) (string, error) {
 		return "", errors.New("image not specified")
 	}
 
-	return image.(string), nil
+	imageName, ok := image.(string)
+
+	if !ok {
+		return "", fmt.Errorf("invalid image: %v", image)
+	}
+
+	return imageName, nil
 }
 
-func getStringArray(stringArray interface{}) []string {
+// getStringArray accepts a string or a list of strings, field names the
+// value in the error of any other type.
+func getStringArray(field string, stringArray interface{}) ([]string, error) {
+	if stringArray == nil {
+		return []string{}, nil
+	}
+
+	if s, ok := stringArray.(string); ok {
+		return []string{s}, nil
+	}
+
 	refVal := reflect.ValueOf(stringArray)
 
-	if refVal.Kind() == reflect.Slice {
-		arr := make([]string, refVal.Len())
+	if refVal.Kind() != reflect.Slice {
+		return []string{}, fmt.Errorf("invalid %s: %v", field, stringArray)
+	}
+
+	arr := make([]string, refVal.Len())
+
+	for i := 0; i < refVal.Len(); i++ {
+		s, ok := refVal.Index(i).Interface().(string)
+
+		if !ok {
+			return []string{}, fmt.Errorf("invalid %s: %v", field, stringArray)
+		}
+
+		arr[i] = s
+	}
+
+	return arr, nil
+}
+
+func getJobPort(port interface{}) ([]Port, error) {
+	refVal := reflect.ValueOf(port)
+
+	lines := []string{}
 
+	if refVal.Kind() == reflect.Slice {
 		for i := 0; i < refVal.Len(); i++ {
-			arr[i] = refVal.Index(i).Interface().(string)
+			lines = append(lines, fmt.Sprint(refVal.Index(i).Interface()))
+		}
+	} else if port != nil {
+		lines = append(lines, fmt.Sprint(port))
+	}
+
+	arr := make([]Port, len(lines))
+
+	for i, line := range lines {
+		ports := strings.Split(line, ":")
+
+		if len(ports) != 2 || ports[0] == "" || ports[1] == "" {
+			return []Port{}, fmt.Errorf("invalid port: %s", line)
 		}
 
-		return arr
+		arr[i] = Port{Out: ports[0], In: ports[1]}
+	}
+
+	return arr, nil
+}
+
+func getServices(services interface{}) ([]Service, error) {
+	refVal := reflect.ValueOf(services)
+
+	if refVal.Kind() != reflect.Slice {
+		return []Service{}, nil
 	}
 
-	if refVal.Kind() == reflect.String {
-		return []string{stringArray.(string)}
+	arr := make([]Service, refVal.Len())
+
+	for i := 0; i < refVal.Len(); i++ {
+		item := refVal.Index(i).Interface()
+
+		if image, ok := item.(string); ok {
+			arr[i] = Service{Name: serviceNameFromImage(image), Image: image}
+			continue
+		}
+
+		serviceMap := getStringMap(item)
+
+		image, ok := serviceMap["image"].(string)
+
+		if !ok || image == "" {
+			return []Service{}, errors.New("service image not specified")
+		}
+
+		name, ok := serviceMap["name"].(string)
+
+		if !ok || name == "" {
+			name = serviceNameFromImage(image)
+		}
+
+		healthcheck, err := getContainerHealthcheck(serviceMap["healthcheck"])
+
+		if err != nil {
+			return []Service{}, fmt.Errorf("service %s: %w", name, err)
+		}
+
+		env, err := getStringArray("env", serviceMap["env"])
+
+		if err != nil {
+			return []Service{}, fmt.Errorf("service %s: %w", name, err)
+		}
+
+		arr[i] = Service{
+			Name:        name,
+			Image:       image,
+			Env:         env,
+			Healthcheck: healthcheck,
+		}
 	}
 
-	return []string{}
+	return arr, nil
 }
 
-func getJobPort(port interface{}) []Port {
-	refVal := reflect.ValueOf(port)
+// serviceNameFromImage turns an image reference like docker.io/bitnami/redis:7
+// into the hostname the job container can reach the service with (redis).
+func serviceNameFromImage(image string) string {
+	name := image
 
-	if refVal.Kind() == reflect.Slice {
-		arr := make([]Port, refVal.Len())
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
 
-		for i := 0; i < refVal.Len(); i++ {
-			line := refVal.Index(i).Interface().(string)
-			ports := strings.Split(line, ":")
-			arr[i] = Port{Out: ports[0], In: ports[1]}
+	if i := strings.Index(name, ":"); i >= 0 {
+		name = name[:i]
+	}
+
+	if i := strings.Index(name, "@"); i >= 0 {
+		name = name[:i]
+	}
+
+	return name
+}
+
+// getStringMap converts maps nested in lists, whose keys are not lower-cased
+// by decodeConfig, to lower-cased string keyed maps.
+func getStringMap(val interface{}) map[string]interface{} {
+	result := map[string]interface{}{}
+
+	switch m := val.(type) {
+	case map[string]interface{}:
+		for k, v := range m {
+			result[strings.ToLower(k)] = v
+		}
+	case map[interface{}]interface{}:
+		for k, v := range m {
+			if key, ok := k.(string); ok {
+				result[strings.ToLower(key)] = v
+			}
 		}
+	}
+
+	return result
+}
+
+// getVolumes validates volume definitions and converts relative host paths
+// to absolute ones, docker treats every other source as a named volume.
+func getVolumes(volumes interface{}) ([]string, error) {
+	arr, err := getStringArray("volumes", volumes)
 
-		return arr
+	if err != nil {
+		return []string{}, err
 	}
 
-	if refVal.Kind() == reflect.String {
-		line := port.(string)
-		ports := strings.Split(line, ":")
-		return []Port{{Out: ports[0], In: ports[1]}}
+	for i, volume := range arr {
+		parts := strings.Split(volume, ":")
+
+		if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+			return []string{}, fmt.Errorf("invalid volume: %s", volume)
+		}
+
+		if len(parts) == 3 && parts[2] != "ro" && parts[2] != "rw" {
+			return []string{}, fmt.Errorf("invalid volume mode: %s", volume)
+		}
+
+		if strings.HasPrefix(parts[0], ".") {
+			source, err := filepath.Abs(parts[0])
+
+			if err != nil {
+				return []string{}, err
+			}
+
+			parts[0] = source
+		}
+
+		arr[i] = strings.Join(parts, ":")
+	}
+
+	return arr, nil
+}
+
+func getCache(cache interface{}) (*Cache, error) {
+	if cache == nil {
+		return nil, nil
+	}
+
+	cacheMap := getStringMap(cache)
+
+	key, ok := cacheMap["key"].(string)
+
+	if !ok || key == "" {
+		return nil, errors.New("cache key not specified")
+	}
+
+	paths, err := getStringArray("cache paths", cacheMap["paths"])
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(paths) == 0 {
+		return nil, errors.New("cache paths not specified")
+	}
+
+	return &Cache{Key: key, Paths: paths}, nil
+}
+
+// getArtifacts accepts a path, a list of paths or a block with paths and
+// destination.
+func getArtifacts(artifacts interface{}) (*Artifacts, error) {
+	if artifacts == nil {
+		return nil, nil
+	}
+
+	refVal := reflect.ValueOf(artifacts)
+
+	if refVal.Kind() == reflect.Slice || refVal.Kind() == reflect.String {
+		paths, err := getStringArray("artifacts", artifacts)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return &Artifacts{Paths: paths}, nil
+	}
+
+	artifactsMap := getStringMap(artifacts)
+
+	paths, err := getStringArray("artifact paths", artifactsMap["paths"])
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(paths) == 0 {
+		return nil, errors.New("artifact paths not specified")
+	}
+
+	destination, _ := artifactsMap["destination"].(string)
+
+	return &Artifacts{Paths: paths, Destination: destination}, nil
+}
+
+func getSkipIfUnchanged(skipIfUnchanged interface{}) (*SkipIfUnchanged, error) {
+	if skipIfUnchanged == nil {
+		return nil, nil
+	}
+
+	inputs, err := getStringArray("skipIfUnchanged inputs", getStringMap(skipIfUnchanged)["inputs"])
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(inputs) == 0 {
+		return nil, errors.New("skipIfUnchanged inputs not specified")
+	}
+
+	return &SkipIfUnchanged{Inputs: inputs}, nil
+}
+
+func getSessionMode(sessionMode interface{}) (string, error) {
+	if sessionMode == nil {
+		return SessionModeIsolated, nil
+	}
+
+	mode, _ := sessionMode.(string)
+
+	if mode != SessionModeIsolated && mode != SessionModeShared {
+		return "", fmt.Errorf("invalid sessionMode: %v", sessionMode)
+	}
+
+	return mode, nil
+}
+
+// getExtraHosts accepts a list of "host:ip" entries or a map of hosts to ips,
+// host-gateway is allowed as the ip of the docker host.
+func getExtraHosts(extraHosts interface{}) ([]string, error) {
+	if refVal := reflect.ValueOf(extraHosts); refVal.Kind() == reflect.Map {
+		hostMap := getStringMap(extraHosts)
+		arr := []string{}
+
+		for host, ip := range hostMap {
+			arr = append(arr, fmt.Sprintf("%s:%v", host, ip))
+		}
+
+		sort.Strings(arr)
+		extraHosts = arr
+	}
+
+	arr, err := getStringArray("extraHosts", extraHosts)
+
+	if err != nil {
+		return []string{}, err
+	}
+
+	for _, entry := range arr {
+		host, ip, found := strings.Cut(entry, ":")
+
+		if !found || host == "" || (ip != "host-gateway" && net.ParseIP(ip) == nil) {
+			return []string{}, fmt.Errorf("invalid extra host: %s", entry)
+		}
+	}
+
+	return arr, nil
+}
+
+func getDNS(dns interface{}) ([]string, error) {
+	arr, err := getStringArray("dns", dns)
+
+	if err != nil {
+		return []string{}, err
+	}
+
+	for _, server := range arr {
+		if net.ParseIP(server) == nil {
+			return []string{}, fmt.Errorf("invalid dns server: %s", server)
+		}
+	}
+
+	return arr, nil
+}
+
+// hostnamePattern is a hostname as defined by RFC 1123: dot separated
+// labels of letters, digits and hyphens that do not start or end with a
+// hyphen.
+var hostnamePattern = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(\.[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$`)
+
+func getHostname(hostname interface{}) (string, error) {
+	if hostname == nil {
+		return "", nil
+	}
+
+	h, ok := hostname.(string)
+
+	if !ok || len(h) > 253 || !hostnamePattern.MatchString(h) {
+		return "", fmt.Errorf("invalid hostname: %v", hostname)
+	}
+
+	return h, nil
+}
+
+// getLabels reads KEY=VALUE container labels, the pin. prefix is reserved
+// for the labels pin sets itself.
+func getLabels(labels interface{}) (map[string]string, error) {
+	result := map[string]string{}
+
+	if m := getStringMap(labels); len(m) > 0 {
+		return result, errors.New("labels must be a list of KEY=VALUE entries")
+	}
+
+	list, err := getStringArray("labels", labels)
+
+	if err != nil {
+		return result, err
+	}
+
+	for _, label := range list {
+		key, value, found := strings.Cut(label, "=")
+
+		if !found || key == "" {
+			return result, fmt.Errorf("invalid label: %s", label)
+		}
+
+		if strings.HasPrefix(key, "pin.") {
+			return result, fmt.Errorf("label %s uses the reserved pin. prefix", key)
+		}
+
+		result[key] = value
+	}
+
+	return result, nil
+}
+
+// getBuildArgs accepts KEY=VALUE entries, a KEY without value takes the value
+// from the environment of pin like docker build --build-arg does.
+func getBuildArgs(buildArgs interface{}) (map[string]*string, error) {
+	args := map[string]*string{}
+
+	if m := getStringMap(buildArgs); len(m) > 0 {
+		return args, errors.New("buildArgs must be a list of KEY=VALUE entries")
 	}
 
-	return []Port{}
+	list, err := getStringArray("buildArgs", buildArgs)
+
+	if err != nil {
+		return args, err
+	}
+
+	for _, arg := range list {
+		name, value, found := strings.Cut(arg, "=")
+
+		if name == "" {
+			return args, fmt.Errorf("invalid build arg: %s", arg)
+		}
+
+		if !found {
+			env, ok := os.LookupEnv(name)
+
+			if !ok {
+				continue
+			}
+
+			value = env
+		}
+
+		v := value
+		args[name] = &v
+	}
+
+	return args, nil
+}
+
+// getStdin accepts the input inline or a block with the file to read it from.
+func getStdin(stdin interface{}) (*string, error) {
+	if stdin == nil {
+		return nil, nil
+	}
+
+	if input, ok := stdin.(string); ok {
+		return &input, nil
+	}
+
+	file, ok := getStringMap(stdin)["file"].(string)
+
+	if !ok || file == "" {
+		return nil, errors.New("stdin must be a string or a block with file")
+	}
+
+	content, err := os.ReadFile(file)
+
+	if err != nil {
+		return nil, err
+	}
+
+	input := string(content)
+
+	return &input, nil
+}
+
+func getCompression(compression interface{}) (string, error) {
+	if compression == nil {
+		return interfaces.CompressionNone, nil
+	}
+
+	algorithm, _ := compression.(string)
+
+	if algorithm == "zstd" {
+		return "", errors.New("zstd compression is not supported by the docker api, use gzip")
+	}
+
+	if algorithm != interfaces.CompressionNone && algorithm != interfaces.CompressionGzip {
+		return "", fmt.Errorf("invalid compression: %v", compression)
+	}
+
+	return algorithm, nil
 }
 
 func getWorkDir(workDir interface{}) (string, error) {
@@ -138,21 +1075,81 @@ func getWorkDir(workDir interface{}) (string, error) {
 		return "/root", nil
 	}
 
-	return workDir.(string), nil
+	dir, ok := workDir.(string)
+
+	if !ok {
+		return "", fmt.Errorf("invalid workDir: %v", workDir)
+	}
+
+	return dir, nil
+}
+
+// getMountFiles accepts true or a block with readOnly, the second result is
+// whether the mount is read-only.
+func getMountFiles(mountFiles interface{}) (bool, bool, error) {
+	if mountFiles == nil {
+		return false, false, nil
+	}
+
+	if mount, ok := mountFiles.(bool); ok {
+		return mount, false, nil
+	}
+
+	mountMap := getStringMap(mountFiles)
+
+	if len(mountMap) == 0 {
+		return false, false, fmt.Errorf("invalid mountFiles: %v", mountFiles)
+	}
+
+	readOnly, err := getBool("mountFiles readOnly", mountMap["readonly"], false)
+
+	if err != nil {
+		return false, false, err
+	}
+
+	return true, readOnly, nil
 }
 
 func getCopyFiles(copyFiles interface{}) (bool, error) {
-	if copyFiles == nil {
-		return false, nil
+	return getBool("copyFiles", copyFiles, false)
+}
+
+// getDuration accepts seconds as a number or a duration string like "1m30s".
+func getDuration(val interface{}) (*time.Duration, error) {
+	if val == nil {
+		return nil, nil
+	}
+
+	switch v := val.(type) {
+	case int:
+		d := time.Duration(v) * time.Second
+		return &d, nil
+	case float64:
+		d := time.Duration(v * float64(time.Second))
+		return &d, nil
+	case string:
+		d, err := time.ParseDuration(v)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return &d, nil
 	}
 
-	return copyFiles.(bool), nil
+	return nil, fmt.Errorf("invalid duration: %v", val)
 }
 
-func getBool(val interface{}, defaultValue bool) bool {
+func getBool(field string, val interface{}, defaultValue bool) (bool, error) {
 	if val == nil {
-		return defaultValue
+		return defaultValue, nil
+	}
+
+	b, ok := val.(bool)
+
+	if !ok {
+		return false, fmt.Errorf("invalid %s: %v", field, val)
 	}
 
-	return val.(bool)
+	return b, nil
 }
@@ -0,0 +1,424 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetServicesAcceptsImageStringsAndMaps(t *testing.T) {
+	services, err := getServices([]interface{}{
+		"postgres:15",
+		"docker.io/bitnami/redis:7",
+		map[interface{}]interface{}{
+			"name":  "db",
+			"image": "mysql:8",
+			"env":   []interface{}{"MYSQL_ROOT_PASSWORD=pin"},
+		},
+	})
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, []Service{
+		{Name: "postgres", Image: "postgres:15"},
+		{Name: "redis", Image: "docker.io/bitnami/redis:7"},
+		{Name: "db", Image: "mysql:8", Env: []string{"MYSQL_ROOT_PASSWORD=pin"}},
+	}, services)
+}
+
+func TestGetServicesWithoutImageReturnsError(t *testing.T) {
+	_, err := getServices([]interface{}{
+		map[interface{}]interface{}{"name": "db"},
+	})
+
+	assert.EqualError(t, err, "service image not specified")
+}
+
+func TestGetDurationAcceptsSecondsAndDurationStrings(t *testing.T) {
+	seconds, err := getDuration(30)
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, 30*time.Second, *seconds)
+
+	duration, err := getDuration("1m30s")
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, 90*time.Second, *duration)
+
+	empty, err := getDuration(nil)
+
+	assert.Equal(t, err, nil)
+	assert.Nil(t, empty)
+
+	_, err = getDuration("soon")
+
+	assert.NotNil(t, err)
+}
+
+func TestGetVolumesResolvesRelativeBindMounts(t *testing.T) {
+	wd, _ := os.Getwd()
+
+	volumes, err := getVolumes([]interface{}{"./data:/data:ro", "go-cache:/root/.cache", "/tmp:/tmp"})
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, []string{filepath.Join(wd, "data") + ":/data:ro", "go-cache:/root/.cache", "/tmp:/tmp"}, volumes)
+}
+
+func TestGetVolumesWithInvalidDefinitionReturnsError(t *testing.T) {
+	_, err := getVolumes("/data")
+
+	assert.EqualError(t, err, "invalid volume: /data")
+
+	_, err = getVolumes("/data:/data:rx")
+
+	assert.EqualError(t, err, "invalid volume mode: /data:/data:rx")
+}
+
+func TestParseReturnsWarningsForSoftIssues(t *testing.T) {
+	config, _ := decodeConfig([]byte(`
+workflow:
+  - build
+
+logsWithtime: true
+colour: red
+
+build:
+  image: golang:alpine3.15
+  copyfile: true
+  copyIgnore:
+    - node_modules
+  port:
+    - 8080:80
+    - 8080:81
+
+lint:
+  image: golangci/golangci-lint
+`))
+
+	pipeline, warnings, err := parse(config)
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, 1, len(pipeline.Workflow))
+	assert.Equal(t, []Warning{
+		{Field: "colour", Message: `unknown field "colour"`},
+		{Job: "lint", Message: "job is defined but not in workflow"},
+		{Job: "build", Field: "copyfile", Message: `unknown field "copyfile"`},
+		{Job: "build", Field: "script", Message: "script is empty, the job only starts a container"},
+		{Job: "build", Field: "copyignore", Message: "copyIgnore has no effect without copyFiles"},
+		{Job: "build", Field: "port", Message: "host port 8080 is mapped more than once"},
+	}, warnings)
+}
+
+func TestParseWarnsAboutEnvPassthroughWildcards(t *testing.T) {
+	config, _ := decodeConfig([]byte(`
+workflow:
+  - deploy
+
+deploy:
+  image: amazon/aws-cli
+  envPassthrough:
+    - AWS_*
+    - GITHUB_TOKEN
+  script:
+    - aws s3 ls
+`))
+
+	pipeline, warnings, err := parse(config)
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, []string{"AWS_*", "GITHUB_TOKEN"}, pipeline.Workflow[0].EnvPassthrough)
+	assert.Equal(t, []Warning{
+		{Job: "deploy", Field: "envpassthrough", Message: "envPassthrough pattern AWS_* passes every matching host variable to the container, secrets included"},
+	}, warnings)
+}
+
+func TestGetSessionMode(t *testing.T) {
+	mode, err := getSessionMode(nil)
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, SessionModeIsolated, mode)
+
+	mode, err = getSessionMode("shared")
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, SessionModeShared, mode)
+
+	_, err = getSessionMode("tmux")
+
+	assert.EqualError(t, err, "invalid sessionMode: tmux")
+}
+
+func TestSelectJobsLinksTheRemainingJobs(t *testing.T) {
+	build := &Job{Name: "build"}
+	test := &Job{Name: "test", IsParallel: true}
+	lint := &Job{Name: "lint", IsParallel: true}
+	deploy := &Job{Name: "deploy"}
+
+	pipeline := Pipeline{Workflow: []*Job{build, test, lint, deploy}}
+	linkJobs(pipeline.Workflow)
+
+	assert.Nil(t, build.Needs)
+	assert.Nil(t, test.Needs)
+	assert.Nil(t, lint.Needs)
+	assert.Equal(t, []*Job{test, lint}, deploy.Needs)
+
+	selected := selectJobs(pipeline, map[string]bool{"test": true, "deploy": true})
+
+	assert.Equal(t, []*Job{test, deploy}, selected.Workflow)
+	assert.Nil(t, test.Needs)
+	assert.Equal(t, []*Job{test}, deploy.Needs)
+}
+
+func TestOnlyJobsKeepsTheWorkflowOrder(t *testing.T) {
+	build := &Job{Name: "build"}
+	test := &Job{Name: "test"}
+	deploy := &Job{Name: "deploy"}
+
+	pipeline := Pipeline{Workflow: []*Job{build, test, deploy}}
+	linkJobs(pipeline.Workflow)
+
+	selected, err := onlyJobs(pipeline, []string{"deploy", " build"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []*Job{build, deploy}, selected.Workflow)
+	assert.Equal(t, []*Job{build}, deploy.Needs)
+
+	_, err = onlyJobs(pipeline, []string{"build", "release"})
+
+	assert.EqualError(t, err, "job release is not in the workflow")
+
+	_, err = onlyJobs(pipeline, []string{""})
+
+	assert.EqualError(t, err, "--jobs needs at least one job name")
+}
+
+func TestGetExtraHostsAcceptsListsAndMaps(t *testing.T) {
+	hosts, err := getExtraHosts([]interface{}{"registry.local:10.0.0.5", "docker.host:host-gateway"})
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, []string{"registry.local:10.0.0.5", "docker.host:host-gateway"}, hosts)
+
+	hosts, err = getExtraHosts(map[string]interface{}{"git.corp": "10.0.0.7", "api.corp": "10.0.0.8"})
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, []string{"api.corp:10.0.0.8", "git.corp:10.0.0.7"}, hosts)
+
+	_, err = getExtraHosts([]interface{}{"registry.local"})
+
+	assert.EqualError(t, err, "invalid extra host: registry.local")
+}
+
+func TestGetHostnameValidatesRFC1123(t *testing.T) {
+	hostname, err := getHostname("db-test.local")
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, "db-test.local", hostname)
+
+	for _, invalid := range []interface{}{"-db", "db_test", "db..local", "db-", 8080} {
+		_, err = getHostname(invalid)
+
+		assert.EqualError(t, err, fmt.Sprintf("invalid hostname: %v", invalid))
+	}
+}
+
+func TestGetLabelsKeepsKeyCase(t *testing.T) {
+	labels, err := getLabels([]interface{}{"com.example.Team=core", "traefik.enable=true"})
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, map[string]string{"com.example.Team": "core", "traefik.enable": "true"}, labels)
+
+	_, err = getLabels(map[string]interface{}{"team": "core"})
+
+	assert.EqualError(t, err, "labels must be a list of KEY=VALUE entries")
+
+	_, err = getLabels([]interface{}{"team"})
+
+	assert.EqualError(t, err, "invalid label: team")
+
+	_, err = getLabels([]interface{}{"pin.job=build"})
+
+	assert.EqualError(t, err, "label pin.job uses the reserved pin. prefix")
+}
+
+func TestGetDNSValidatesServers(t *testing.T) {
+	dns, err := getDNS([]interface{}{"10.0.0.2", "8.8.8.8"})
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, []string{"10.0.0.2", "8.8.8.8"}, dns)
+
+	_, err = getDNS("dns.corp")
+
+	assert.EqualError(t, err, "invalid dns server: dns.corp")
+}
+
+func TestGetCompression(t *testing.T) {
+	compression, err := getCompression(nil)
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, "none", compression)
+
+	compression, err = getCompression("gzip")
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, "gzip", compression)
+
+	_, err = getCompression("zstd")
+
+	assert.NotNil(t, err)
+}
+
+func TestGetStdinAcceptsInlineInputAndFiles(t *testing.T) {
+	stdin, err := getStdin("answers\n")
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, "answers\n", *stdin)
+
+	file := filepath.Join(t.TempDir(), "input.json")
+	os.WriteFile(file, []byte(`{"env":"ci"}`), 0644)
+
+	stdin, err = getStdin(map[string]interface{}{"file": file})
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, `{"env":"ci"}`, *stdin)
+
+	stdin, err = getStdin(nil)
+
+	assert.Equal(t, err, nil)
+	assert.Nil(t, stdin)
+
+	_, err = getStdin(map[string]interface{}{})
+
+	assert.EqualError(t, err, "stdin must be a string or a block with file")
+}
+
+func TestGetJobPortRejectsInvalidPorts(t *testing.T) {
+	ports, err := getJobPort([]interface{}{"8082:8080", "9000:9000"})
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, []Port{{Out: "8082", In: "8080"}, {Out: "9000", In: "9000"}}, ports)
+
+	_, err = getJobPort(8080)
+
+	assert.EqualError(t, err, "invalid port: 8080")
+}
+
+func TestGenerateJobWithDockerfile(t *testing.T) {
+	t.Setenv("PIN_TEST_TOKEN", "secret")
+
+	job, err := generateJob(map[string]interface{}{
+		"dockerfile": "./build/Dockerfile",
+		"buildargs":  []interface{}{"VERSION=1.2.3", "PIN_TEST_TOKEN", "PIN_TEST_UNSET"},
+	})
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, "./build/Dockerfile", job.Dockerfile)
+	assert.Len(t, job.BuildArgs, 2)
+	assert.Equal(t, "1.2.3", *job.BuildArgs["VERSION"])
+	assert.Equal(t, "secret", *job.BuildArgs["PIN_TEST_TOKEN"])
+
+	_, err = generateJob(map[string]interface{}{
+		"dockerfile": "./Dockerfile",
+		"image":      "golang:alpine3.15",
+	})
+
+	assert.EqualError(t, err, "image and dockerfile can not be used together")
+}
+
+func TestGenerateJobWithImageFallbacks(t *testing.T) {
+	job, err := generateJob(map[string]interface{}{
+		"image":          "mirror.example.com/golang:1",
+		"imagefallbacks": []interface{}{"golang:1"},
+	})
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, []string{"golang:1"}, job.ImageFallbacks)
+
+	_, err = generateJob(map[string]interface{}{
+		"dockerfile":     "./Dockerfile",
+		"imagefallbacks": []interface{}{"golang:1"},
+	})
+
+	assert.EqualError(t, err, "imageFallbacks can not be used with dockerfile")
+}
+
+func TestParseTagsDockerfileImages(t *testing.T) {
+	config, _ := decodeConfig([]byte(`workflow:
+  - test
+  - release
+
+test:
+  dockerfile: Dockerfile
+  target: test
+  imageTag: myapp-test:dev
+  script:
+    - go test ./...
+
+release:
+  dockerfile: Dockerfile
+  script:
+    - ./app --version
+`))
+
+	pipeline, _, err := parse(config)
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, "myapp-test:dev", pipeline.Workflow[0].Image)
+	assert.Equal(t, "test", pipeline.Workflow[0].Target)
+	assert.Equal(t, "release-custom:latest", pipeline.Workflow[1].Image)
+}
+
+func TestGenerateJobWithMountFiles(t *testing.T) {
+	job, err := generateJob(map[string]interface{}{
+		"image":      "golang:alpine3.15",
+		"mountfiles": map[string]interface{}{"readonly": true},
+	})
+
+	assert.Equal(t, err, nil)
+	assert.True(t, job.MountFiles)
+	assert.True(t, job.MountReadOnly)
+
+	_, err = generateJob(map[string]interface{}{
+		"image":      "golang:alpine3.15",
+		"copyfiles":  true,
+		"mountfiles": true,
+	})
+
+	assert.EqualError(t, err, "copyFiles and mountFiles can not be used together")
+
+	_, err = generateJob(map[string]interface{}{
+		"image":      "golang:alpine3.15",
+		"mountfiles": "yes",
+	})
+
+	assert.EqualError(t, err, "invalid mountFiles: yes")
+}
+
+func TestProjectMountUsesWorkDir(t *testing.T) {
+	wd, _ := os.Getwd()
+
+	mount, err := projectMount(&Job{WorkDir: "/app", MountReadOnly: true})
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, wd+":/app:ro", mount)
+}
+
+func TestGenerateJobWithDetach(t *testing.T) {
+	job, err := generateJob(map[string]interface{}{
+		"image":  "nginx:alpine",
+		"detach": true,
+	})
+
+	assert.Equal(t, err, nil)
+	assert.True(t, job.Detach)
+
+	_, err = generateJob(map[string]interface{}{
+		"image":    "nginx:alpine",
+		"detach":   true,
+		"services": []interface{}{map[string]interface{}{"name": "db", "image": "postgres:14"}},
+	})
+
+	assert.EqualError(t, err, "detach and services can not be used together")
+}
@@ -0,0 +1,193 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// resolveConfigPaths expands ~ and environment variables in the host paths
+// of every job and makes relative ones absolute against dir, the directory
+// of the pipeline file, so a pipeline behaves the same wherever pin is
+// started. It runs on the decoded configuration before parse.
+func resolveConfigPaths(config map[string]interface{}, dir string) error {
+	resolveEnvFile(config, dir)
+	resolvePlugins(config, dir)
+
+	for key, val := range config {
+		if knownPipelineFields[key] {
+			continue
+		}
+
+		job, ok := val.(map[string]interface{})
+
+		if !ok {
+			continue
+		}
+
+		if err := resolveJobPaths(job, dir); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+// resolveEnvFile resolves the envFile of a job or of the pipeline.
+func resolveEnvFile(m map[string]interface{}, dir string) {
+	switch envFile := m["envfile"].(type) {
+	case string:
+		m["envfile"] = expandPath(envFile, dir)
+	case []interface{}:
+		for i, file := range envFile {
+			if s, ok := file.(string); ok {
+				envFile[i] = expandPath(s, dir)
+			}
+		}
+	}
+}
+
+// resolvePlugins resolves the executables declared under plugins.
+func resolvePlugins(config map[string]interface{}, dir string) {
+	plugins, ok := config["plugins"].(map[string]interface{})
+
+	if !ok {
+		return
+	}
+
+	for name, plugin := range plugins {
+		switch p := plugin.(type) {
+		case string:
+			if p != "" {
+				plugins[name] = expandPath(p, dir)
+			}
+		case map[string]interface{}:
+			if path, ok := p["path"].(string); ok && path != "" {
+				p["path"] = expandPath(path, dir)
+			}
+		}
+	}
+}
+
+func resolveJobPaths(job map[string]interface{}, dir string) error {
+	if dockerfile, ok := job["dockerfile"].(string); ok && dockerfile != "" {
+		job["dockerfile"] = expandPath(dockerfile, dir)
+	}
+
+	// other values of uses are names of declared plugins
+	if uses, ok := job["uses"].(string); ok && (strings.HasPrefix(uses, "./") || strings.HasPrefix(uses, "../") || strings.HasPrefix(uses, "~/")) {
+		job["uses"] = expandPath(uses, dir)
+	}
+
+	resolveEnvFile(job, dir)
+
+	if volumes, ok := job["volumes"].([]interface{}); ok {
+		for i, volume := range volumes {
+			if s, ok := volume.(string); ok {
+				volumes[i] = expandVolume(s, dir)
+			}
+		}
+	}
+
+	if stdin, ok := job["stdin"].(map[string]interface{}); ok {
+		if file, ok := stdin["file"].(string); ok && file != "" {
+			stdin["file"] = expandPath(file, dir)
+		}
+	}
+
+	if artifacts, ok := job["artifacts"].(map[string]interface{}); ok {
+		if destination, ok := artifacts["destination"].(string); ok && destination != "" {
+			artifacts["destination"] = expandPath(destination, dir)
+		}
+	}
+
+	if cache, ok := job["cache"].(map[string]interface{}); ok {
+		if key, ok := cache["key"].(string); ok {
+			cache["key"] = checksumPattern.ReplaceAllStringFunc(key, func(match string) string {
+				file := checksumPattern.FindStringSubmatch(match)[1]
+
+				return fmt.Sprintf("{{ checksum %q }}", expandPath(file, dir))
+			})
+		}
+	}
+
+	return nil
+}
+
+// expandPath expands environment variables and a leading ~ and makes a
+// relative path absolute against dir.
+func expandPath(path, dir string) string {
+	path = os.ExpandEnv(path)
+
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, path[1:])
+		}
+	}
+
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+
+	return filepath.Join(dir, path)
+}
+
+// expandVolume expands the source of a bind mount, named volumes are left
+// as they are.
+func expandVolume(volume, dir string) string {
+	source, rest, found := strings.Cut(volume, ":")
+
+	if !found {
+		return volume
+	}
+
+	source = os.ExpandEnv(source)
+
+	if source != "~" && !strings.HasPrefix(source, "~/") && !strings.HasPrefix(source, ".") && !filepath.IsAbs(source) {
+		return volume
+	}
+
+	return expandPath(source, dir) + ":" + rest
+}
+
+// configDir is the absolute directory relative paths of a pipeline file
+// are resolved against, the current directory for stdin.
+func configDir(configPath string) string {
+	dir := "."
+
+	if configPath != stdinConfigPath && configPath != "" {
+		dir = filepath.Dir(configPath)
+	}
+
+	if abs, err := filepath.Abs(dir); err == nil {
+		return abs
+	}
+
+	return dir
+}
+
+// projectDir is the directory pin is started in, which pin rerun and pin
+// retry change back into. The project files that copyFiles, mountFiles,
+// copyStrategy: delta, skipIfUnchanged and resultCache read come from it and
+// plugins run in it, unlike the paths of the pipeline file which belong to
+// the directory of that file.
+func projectDir() (string, error) {
+	return os.Getwd()
+}
+
+// contextPath returns the path of the dockerfile inside the build context,
+// docker only reads dockerfiles of the context.
+func contextPath(contextDir, dockerfile string) (string, error) {
+	if !filepath.IsAbs(dockerfile) {
+		return dockerfile, nil
+	}
+
+	rel, err := filepath.Rel(contextDir, dockerfile)
+
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("dockerfile %s is outside of the build context %s", dockerfile, contextDir)
+	}
+
+	return rel, nil
+}
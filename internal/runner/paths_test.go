@@ -0,0 +1,128 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveConfigPathsAgainstPipelineDirectory(t *testing.T) {
+	t.Setenv("HOME", "/home/pin")
+	t.Setenv("DATA_DIR", "/srv/data")
+
+	config, _ := decodeConfig([]byte(`
+workflow:
+  - build
+
+docker:
+  retry: true
+
+build:
+  dockerfile: ./docker/Dockerfile
+  envFile:
+    - .env
+    - ~/.config/pin.env
+  volumes:
+    - go-cache:/root/.cache/go-build
+    - ./testdata:/testdata:ro
+    - $DATA_DIR:/data
+  cache:
+    key: go-{{ checksum "go.sum" }}
+    paths:
+      - /go/pkg/mod
+  artifacts:
+    paths:
+      - dist
+    destination: ../out
+  stdin:
+    file: ./deploy/answers.txt
+`))
+
+	assert.Equal(t, nil, resolveConfigPaths(config, "/project/ci"))
+
+	build := config["build"].(map[string]interface{})
+
+	assert.Equal(t, "/project/ci/docker/Dockerfile", build["dockerfile"])
+	assert.Equal(t, []interface{}{"/project/ci/.env", "/home/pin/.config/pin.env"}, build["envfile"])
+	assert.Equal(t, []interface{}{"go-cache:/root/.cache/go-build", "/project/ci/testdata:/testdata:ro", "/srv/data:/data"}, build["volumes"])
+	assert.Equal(t, `go-{{ checksum "/project/ci/go.sum" }}`, build["cache"].(map[string]interface{})["key"])
+	assert.Equal(t, "/project/out", build["artifacts"].(map[string]interface{})["destination"])
+	assert.Equal(t, "/project/ci/deploy/answers.txt", build["stdin"].(map[string]interface{})["file"])
+	assert.Equal(t, true, config["docker"].(map[string]interface{})["retry"])
+}
+
+func TestResolveConfigPathsOfPlugins(t *testing.T) {
+	config, _ := decodeConfig([]byte(`
+workflow:
+  - deploy
+  - notify
+  - lint
+
+plugins:
+  deploy: ./plugins/deploy
+  notify:
+    path: ../tools/notify
+
+deploy:
+  uses: deploy
+
+notify:
+  uses: notify
+
+lint:
+  uses: ./plugins/lint
+`))
+
+	assert.Equal(t, nil, resolveConfigPaths(config, "/project/ci"))
+
+	plugins := config["plugins"].(map[string]interface{})
+
+	assert.Equal(t, "/project/ci/plugins/deploy", plugins["deploy"])
+	assert.Equal(t, "/project/tools/notify", plugins["notify"].(map[string]interface{})["path"])
+	assert.Equal(t, "deploy", config["deploy"].(map[string]interface{})["uses"])
+	assert.Equal(t, "/project/ci/plugins/lint", config["lint"].(map[string]interface{})["uses"])
+}
+
+func TestContextPath(t *testing.T) {
+	path, err := contextPath("/project", "Dockerfile")
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "Dockerfile", path)
+
+	path, err = contextPath("/project", "/project/docker/Dockerfile")
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, filepath.Join("docker", "Dockerfile"), path)
+
+	_, err = contextPath("/project", "/other/Dockerfile")
+
+	assert.EqualError(t, err, "dockerfile /other/Dockerfile is outside of the build context /project")
+}
+
+func TestParseWarnsAboutMissingPaths(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "Dockerfile"), []byte("FROM alpine"), 0644)
+
+	config, _ := decodeConfig([]byte(`
+workflow:
+  - build
+
+build:
+  dockerfile: Dockerfile
+  volumes:
+    - ./data:/data
+  script:
+    - ls /data
+`))
+
+	resolveConfigPaths(config, dir)
+
+	_, warnings, err := parse(config)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []Warning{
+		{Job: "build", Field: "volumes", Message: "volume source " + filepath.Join(dir, "data") + " does not exist, docker creates it as an empty directory"},
+	}, warnings)
+}
@@ -0,0 +1,350 @@
+package runner
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/fatih/color"
+)
+
+// pluginProtocol is the version of the request a plugin gets on stdin.
+const pluginProtocol = 1
+
+// maxPluginLineSize is the longest line read from a plugin.
+const maxPluginLineSize = 1024 * 1024
+
+const (
+	PluginMessageLog    = "log"
+	PluginMessageOutput = "output"
+	PluginMessageError  = "error"
+)
+
+// pluginJobFields are the job fields that have an effect with uses, the job
+// runs on the host so container settings are ignored.
+var pluginJobFields = map[string]bool{
+	"uses":        true,
+	"with":        true,
+	"env":         true,
+	"envfile":     true,
+	"description": true,
+	"tags":        true,
+	"parallel":    true,
+	"minsuccess":  true,
+	"onsuccess":   true,
+	"onfailure":   true,
+}
+
+// PluginRequest is written as json to the stdin of a plugin.
+type PluginRequest struct {
+	Protocol int                    `json:"protocol"`
+	RunID    string                 `json:"runId"`
+	Job      string                 `json:"job"`
+	WorkDir  string                 `json:"workDir"`
+	Inputs   map[string]interface{} `json:"inputs"`
+	Env      []string               `json:"env"`
+}
+
+// PluginMessage is one json line a plugin writes to stdout.
+type PluginMessage struct {
+	Type    string `json:"type"`
+	Message string `json:"message,omitempty"`
+	Name    string `json:"name,omitempty"`
+	Value   string `json:"value,omitempty"`
+}
+
+// getPlugins maps plugin names to the executables declared under plugins,
+// relative paths are resolved against the project directory when run.
+func getPlugins(plugins interface{}) (map[string]string, error) {
+	result := map[string]string{}
+
+	if plugins == nil {
+		return result, nil
+	}
+
+	pluginsMap := getStringMap(plugins)
+
+	if len(pluginsMap) == 0 {
+		return result, errors.New("plugins must be a mapping of names to executables")
+	}
+
+	for name, path := range pluginsMap {
+		if pathMap := getStringMap(path); len(pathMap) > 0 {
+			path = pathMap["path"]
+		}
+
+		p, _ := path.(string)
+
+		if p == "" {
+			return result, fmt.Errorf("%s: path not specified", name)
+		}
+
+		result[name] = p
+	}
+
+	return result, nil
+}
+
+// generatePluginJob parses a job that runs a plugin instead of a container.
+func generatePluginJob(configMap map[string]interface{}) (*Job, error) {
+	uses, _ := configMap["uses"].(string)
+
+	if uses == "" {
+		return &Job{}, fmt.Errorf("invalid uses: %v", configMap["uses"])
+	}
+
+	for _, field := range []string{"image", "dockerfile", "script"} {
+		if configMap[field] != nil {
+			return &Job{}, fmt.Errorf("uses and %s can not be used together", field)
+		}
+	}
+
+	with := map[string]interface{}{}
+
+	if configMap["with"] != nil {
+		if with = getStringMap(configMap["with"]); len(with) == 0 {
+			return &Job{}, errors.New("with must be a mapping of inputs")
+		}
+	}
+
+	env, err := getEnv(configMap["env"], configMap["envfile"])
+
+	if err != nil {
+		return &Job{}, err
+	}
+
+	onSuccess, err := getNotification(configMap["onsuccess"])
+
+	if err != nil {
+		return &Job{}, fmt.Errorf("onSuccess: %w", err)
+	}
+
+	onFailure, err := getNotification(configMap["onfailure"])
+
+	if err != nil {
+		return &Job{}, fmt.Errorf("onFailure: %w", err)
+	}
+
+	minSuccess, err := getMinSuccess(configMap["minsuccess"])
+
+	if err != nil {
+		return &Job{}, err
+	}
+
+	tags, err := getStringArray("tags", configMap["tags"])
+
+	if err != nil {
+		return &Job{}, err
+	}
+
+	isParallel, err := getBool("parallel", configMap["parallel"], false)
+
+	if err != nil {
+		return &Job{}, err
+	}
+
+	description, _ := configMap["description"].(string)
+
+	return &Job{
+		Uses:        uses,
+		With:        with,
+		Env:         env,
+		Description: description,
+		Tags:        tags,
+		IsParallel:  isParallel,
+		MinSuccess:  minSuccess,
+		OnSuccess:   onSuccess,
+		OnFailure:   onFailure,
+	}, nil
+}
+
+// resolvePlugin returns the executable of a declared plugin, uses can also
+// be a path starting with ./, ../ or /.
+func resolvePlugin(plugins map[string]string, uses string) (string, error) {
+	if path, ok := plugins[strings.ToLower(uses)]; ok {
+		return path, nil
+	}
+
+	if strings.HasPrefix(uses, "./") || strings.HasPrefix(uses, "../") || filepath.IsAbs(uses) {
+		return uses, nil
+	}
+
+	return "", fmt.Errorf("unknown plugin: %s", uses)
+}
+
+func pluginJobWarnings(name string, configMap map[string]interface{}) []Warning {
+	warnings := []Warning{}
+
+	keys := []string{}
+
+	for key := range configMap {
+		if knownJobFields[key] && !pluginJobFields[key] {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		warnings = append(warnings, Warning{Job: name, Field: key, Message: fmt.Sprintf("%s has no effect with uses", key)})
+	}
+
+	return warnings
+}
+
+// runPlugin executes the plugin of the job on the host, it gets the request
+// on stdin and reports logs, outputs and errors as json lines on stdout.
+func (r Runner) runPlugin(currentJob *Job) error {
+	currentPath, err := projectDir()
+
+	if err != nil {
+		return err
+	}
+
+	path := currentJob.Uses
+
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(currentPath, path)
+	}
+
+	request, err := json.Marshal(PluginRequest{
+		Protocol: pluginProtocol,
+		RunID:    r.runID,
+		Job:      currentJob.Name,
+		WorkDir:  currentPath,
+		Inputs:   currentJob.With,
+		Env:      currentJob.Env,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	color.Set(color.FgBlue)
+	currentJob.InfoLog.Printf("Running plugin %s", currentJob.Uses)
+	color.Unset()
+
+	cmd := exec.CommandContext(r.ctx, path)
+	cmd.Dir = currentPath
+	cmd.Env = append(append(append(os.Environ(), currentJob.Env...), "PIN_JOB="+currentJob.Name), r.metadataEnv(currentJob, currentPath)...)
+	cmd.Stdin = strings.NewReader(string(request) + "\n")
+
+	stdout, err := cmd.StdoutPipe()
+
+	if err != nil {
+		return err
+	}
+
+	stderr, err := cmd.StderrPipe()
+
+	if err != nil {
+		return err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("plugin %s could not be started: %w", currentJob.Uses, err)
+	}
+
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		scanner := pluginScanner(stderr)
+
+		for scanner.Scan() {
+			currentJob.InfoLog.Println(scanner.Text())
+		}
+
+		// the plugin blocks once the pipe is full, the rest is discarded
+		if err := scanner.Err(); err != nil {
+			currentJob.InfoLog.Printf("warning: stderr of the plugin could not be read: %s", err)
+			io.Copy(io.Discard, stderr)
+		}
+	}()
+
+	outputs, pluginErr := readPluginMessages(stdout, currentJob)
+	io.Copy(io.Discard, stdout)
+
+	wg.Wait()
+
+	err = cmd.Wait()
+
+	currentJob.Outputs = outputs
+
+	if pluginErr != "" {
+		return fmt.Errorf("plugin %s failed: %s", currentJob.Uses, pluginErr)
+	}
+
+	if err != nil {
+		return fmt.Errorf("plugin %s failed: %w", currentJob.Uses, err)
+	}
+
+	color.Set(color.FgGreen)
+	currentJob.InfoLog.Println("Job ended")
+	color.Unset()
+
+	return nil
+}
+
+// pluginScanner reads the lines of a plugin, a line can hold up to
+// maxPluginLineSize bytes.
+func pluginScanner(r io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxPluginLineSize)
+
+	return scanner
+}
+
+// readPluginMessages logs the messages of a plugin and collects its outputs,
+// lines that are not json messages are logged as they are. The plugin fails
+// when its messages can not be read to the end, outputs may be missing.
+func readPluginMessages(stdout io.Reader, currentJob *Job) (map[string]string, string) {
+	outputs := map[string]string{}
+	pluginErr := ""
+
+	scanner := pluginScanner(stdout)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		var message PluginMessage
+
+		if err := json.Unmarshal([]byte(line), &message); err != nil || message.Type == "" {
+			currentJob.InfoLog.Println(line)
+			continue
+		}
+
+		switch message.Type {
+		case PluginMessageLog:
+			currentJob.InfoLog.Println(message.Message)
+		case PluginMessageOutput:
+			outputs[message.Name] = message.Value
+			currentJob.InfoLog.Printf("Plugin output: %s", message.Name)
+		case PluginMessageError:
+			pluginErr = message.Message
+
+			color.Set(color.FgRed)
+			currentJob.InfoLog.Println(message.Message)
+			color.Unset()
+		default:
+			currentJob.InfoLog.Println(line)
+		}
+	}
+
+	if err := scanner.Err(); err != nil && pluginErr == "" {
+		pluginErr = fmt.Sprintf("messages could not be read: %s", err)
+	}
+
+	return outputs, pluginErr
+}
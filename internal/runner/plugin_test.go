@@ -0,0 +1,151 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/muhammedikinci/pin/internal/interfaces"
+	"github.com/stretchr/testify/assert"
+)
+
+const testPlugin = `#!/bin/sh
+read request
+echo '{"type":"log","message":"deploying"}'
+echo "plain line"
+echo "$request" | grep -q '"inputs":{"target":"staging"}' || { echo '{"type":"error","message":"unexpected request"}'; exit 1; }
+echo '{"type":"output","name":"url","value":"https://staging.example.com"}'
+echo "warning on stderr" >&2
+`
+
+func TestRunPluginStreamsLogsAndCollectsOutputs(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugin is a shell script")
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "deploy")
+	os.WriteFile(path, []byte(testPlugin), 0755)
+
+	var logs bytes.Buffer
+
+	job := &Job{
+		Name:    "deploy",
+		Uses:    path,
+		With:    map[string]interface{}{"target": "staging"},
+		InfoLog: log.New(&logs, "", 0),
+	}
+
+	r := Runner{ctx: context.Background(), runID: "run"}
+
+	err := r.runPlugin(job)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, map[string]string{"url": "https://staging.example.com"}, job.Outputs)
+	assert.Contains(t, logs.String(), "deploying\n")
+	assert.Contains(t, logs.String(), "plain line\n")
+	assert.Contains(t, logs.String(), "warning on stderr\n")
+
+	job.With = map[string]interface{}{"target": "production"}
+
+	err = r.runPlugin(job)
+
+	assert.EqualError(t, err, "plugin "+path+" failed: unexpected request")
+}
+
+func TestRunPluginReportsLinesLongerThanTheLimit(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugin is a shell script")
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "deploy")
+
+	// the stderr line fills more than the pipe buffer, the plugin only exits
+	// when the rest of stderr is still read
+	os.WriteFile(path, []byte("#!/bin/sh\nhead -c 2097152 /dev/zero | tr '\\0' a >&2\necho >&2\nhead -c 2097152 /dev/zero | tr '\\0' a >&2\necho '{\"type\":\"output\",\"name\":\"url\",\"value\":\"https://staging.example.com\"}'\n"), 0755)
+
+	var logs bytes.Buffer
+
+	job := &Job{Name: "deploy", Uses: path, InfoLog: log.New(&logs, "", 0)}
+
+	r := Runner{ctx: context.Background(), runID: "run"}
+
+	assert.Equal(t, nil, r.runPlugin(job))
+	assert.Equal(t, map[string]string{"url": "https://staging.example.com"}, job.Outputs)
+	assert.Contains(t, logs.String(), "warning: stderr of the plugin could not be read: bufio.Scanner: token too long\n")
+
+	os.WriteFile(path, []byte("#!/bin/sh\nhead -c 2097152 /dev/zero | tr '\\0' a\necho\necho '{\"type\":\"output\",\"name\":\"url\",\"value\":\"https://staging.example.com\"}'\n"), 0755)
+
+	assert.EqualError(t, r.runPlugin(job), "plugin "+path+" failed: messages could not be read: bufio.Scanner: token too long")
+}
+
+func TestParsePluginJobs(t *testing.T) {
+	config, _ := decodeConfig([]byte(`workflow:
+  - deploy
+  - notify
+
+plugins:
+  deploy: ./plugins/deploy
+
+deploy:
+  uses: deploy
+  copyFiles: true
+  with:
+    target: staging
+
+notify:
+  uses: ./plugins/notify
+`))
+
+	pipeline, warnings, err := parse(config)
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, "./plugins/deploy", pipeline.Workflow[0].Uses)
+	assert.Equal(t, map[string]interface{}{"target": "staging"}, pipeline.Workflow[0].With)
+	assert.Equal(t, "./plugins/notify", pipeline.Workflow[1].Uses)
+	assert.Equal(t, []Warning{{Job: "deploy", Field: "copyfiles", Message: "copyfiles has no effect with uses"}}, warnings)
+
+	config, _ = decodeConfig([]byte(`workflow:
+  - deploy
+
+deploy:
+  uses: deploy
+`))
+
+	_, _, err = parse(config)
+
+	assert.EqualError(t, err, "unknown plugin: deploy")
+}
+
+func TestPluginPipelineRunsWithoutDockerClient(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugin is a shell script")
+	}
+
+	defer func(f func() (interfaces.Client, error)) { newDockerClient = f }(newDockerClient)
+
+	newDockerClient = func() (interfaces.Client, error) {
+		t.Fatal("docker client created for a pipeline of plugin jobs")
+		return nil, nil
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "deploy")
+	os.WriteFile(path, []byte("#!/bin/sh\necho '{\"type\":\"log\",\"message\":\"deployed\"}'\n"), 0755)
+
+	job := &Job{Name: "deploy", Uses: path}
+
+	r := Runner{hooks: &hookLog{}}
+
+	err := r.run(Pipeline{Workflow: []*Job{job}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, JobStatusSuccess, job.Status)
+
+	assert.NoError(t, r.dryRun(Pipeline{Workflow: []*Job{job}}))
+}
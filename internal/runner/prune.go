@@ -0,0 +1,112 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/docker/docker/client"
+	"github.com/fatih/color"
+	"github.com/muhammedikinci/pin/internal/container_manager"
+	"github.com/muhammedikinci/pin/internal/image_manager"
+)
+
+// Labels of the containers, networks and built images of a run.
+const (
+	LabelPipeline = "pin.pipeline"
+	LabelJob      = "pin.job"
+	LabelRunID    = "pin.run_id"
+	LabelService  = "pin.service"
+)
+
+func (r Runner) labels(currentJob *Job) map[string]string {
+	return map[string]string{
+		container_manager.ManagedLabel: "true",
+		LabelPipeline:                  r.pipelineName,
+		LabelJob:                       currentJob.Name,
+		LabelRunID:                     r.runID,
+	}
+}
+
+// containerLabels are the labels of the job container, the labels of the job
+// and the ones pin sets.
+func (r Runner) containerLabels(currentJob *Job) map[string]string {
+	labels := r.labels(currentJob)
+
+	for key, value := range currentJob.Labels {
+		labels[key] = value
+	}
+
+	return labels
+}
+
+func (r Runner) serviceLabels(currentJob *Job, service string) map[string]string {
+	labels := r.labels(currentJob)
+	labels[LabelService] = service
+
+	return labels
+}
+
+// Prune removes the leftover containers, networks and built images of pin,
+// only the ones of a single run when runID is given. Running containers and
+// resources they use are kept.
+func Prune(runID string) error {
+	infoLog := log.New(os.Stdout, glyph(glyphJob)+" prune ", 0)
+	ctx := context.Background()
+
+	cli, err := client.NewClientWithOpts()
+
+	if err != nil {
+		fmt.Println(err)
+		return err
+	}
+
+	labels := []string{}
+
+	if runID != "" {
+		labels = append(labels, LabelRunID+"="+runID)
+	}
+
+	containerManager := container_manager.NewContainerManager(cli, infoLog)
+	imageManager := image_manager.NewImageManager(cli, infoLog)
+
+	containers, err := containerManager.PruneContainers(ctx, labels)
+
+	if err != nil {
+		fmt.Println(err)
+		return err
+	}
+
+	for _, name := range containers {
+		infoLog.Printf("Container removed: %s", name)
+	}
+
+	networks, err := containerManager.PruneNetworks(ctx, 0, labels)
+
+	if err != nil {
+		fmt.Println(err)
+		return err
+	}
+
+	for _, name := range networks {
+		infoLog.Printf("Network removed: %s", name)
+	}
+
+	images, err := imageManager.PruneImages(ctx, append([]string{container_manager.ManagedLabel}, labels...))
+
+	if err != nil {
+		fmt.Println(err)
+		return err
+	}
+
+	for _, name := range images {
+		infoLog.Printf("Image removed: %s", name)
+	}
+
+	color.Set(color.FgGreen)
+	infoLog.Printf("Removed %d containers, %d networks and %d images", len(containers), len(networks), len(images))
+	color.Unset()
+
+	return nil
+}
@@ -0,0 +1,159 @@
+package runner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/muhammedikinci/pin/internal/interfaces"
+	"github.com/muhammedikinci/pin/internal/pin_error"
+	"gopkg.in/yaml.v3"
+)
+
+// RunReport is written to stdout by apply --output, for wrappers and bots.
+type RunReport struct {
+	ID         string              `json:"id" yaml:"id"`
+	Pipeline   string              `json:"pipeline" yaml:"pipeline"`
+	Status     string              `json:"status" yaml:"status"`
+	StartedAt  time.Time           `json:"startedAt" yaml:"startedAt"`
+	FinishedAt time.Time           `json:"finishedAt" yaml:"finishedAt"`
+	Duration   time.Duration       `json:"duration" yaml:"duration"`
+	Jobs       []JobReport         `json:"jobs" yaml:"jobs"`
+	Error      *pin_error.PinError `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+type JobReport struct {
+	Name      string              `json:"name" yaml:"name"`
+	Status    string              `json:"status" yaml:"status"`
+	Tolerated bool                `json:"tolerated,omitempty" yaml:"tolerated,omitempty"`
+	Attempts  int                 `json:"attempts" yaml:"attempts"`
+	Duration  time.Duration       `json:"duration" yaml:"duration"`
+	ExitCode  *int                `json:"exitCode,omitempty" yaml:"exitCode,omitempty"`
+	Artifacts []string            `json:"artifacts,omitempty" yaml:"artifacts,omitempty"`
+	Tests     *TestReport         `json:"tests,omitempty" yaml:"tests,omitempty"`
+	Timings   *JobTimings         `json:"timings,omitempty" yaml:"timings,omitempty"`
+	Resources *JobResources       `json:"resources,omitempty" yaml:"resources,omitempty"`
+	Error     *pin_error.PinError `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// JobResources are the peaks of the docker stats sampled while the script of
+// a job ran.
+type JobResources struct {
+	PeakCPUPercent  float64 `json:"peakCpuPercent" yaml:"peakCpuPercent"`
+	PeakMemoryBytes uint64  `json:"peakMemoryBytes" yaml:"peakMemoryBytes"`
+	NetworkRxBytes  uint64  `json:"networkRxBytes" yaml:"networkRxBytes"`
+	NetworkTxBytes  uint64  `json:"networkTxBytes" yaml:"networkTxBytes"`
+}
+
+// jobResources is nil for jobs whose container was never sampled.
+func jobResources(usage interfaces.ResourceUsage) *JobResources {
+	if usage.Samples == 0 {
+		return nil
+	}
+
+	return &JobResources{
+		PeakCPUPercent:  usage.PeakCPUPercent,
+		PeakMemoryBytes: usage.PeakMemoryBytes,
+		NetworkRxBytes:  usage.NetworkRxBytes,
+		NetworkTxBytes:  usage.NetworkTxBytes,
+	}
+}
+
+func checkOutputFormat(output string) error {
+	if output != "" && output != "text" && output != "json" && output != "yaml" {
+		return fmt.Errorf("unsupported output format: %s", output)
+	}
+
+	return nil
+}
+
+// newRunReport combines the recorded run with the errors of the jobs, the
+// exit code is known for jobs whose script ran.
+func newRunReport(run RunMetadata, pipeline Pipeline, runErr error) RunReport {
+	report := RunReport{
+		ID:         run.ID,
+		Pipeline:   run.Pipeline,
+		Status:     run.Status,
+		StartedAt:  run.StartedAt,
+		FinishedAt: run.FinishedAt,
+		Jobs:       []JobReport{},
+		Error:      classifyError(runErr),
+	}
+
+	if !run.StartedAt.IsZero() && run.FinishedAt.After(run.StartedAt) {
+		report.Duration = run.FinishedAt.Sub(run.StartedAt)
+	}
+
+	for _, job := range pipeline.Workflow {
+		jobReport := JobReport{
+			Name:      job.Name,
+			Status:    job.Status,
+			Tolerated: job.Tolerated,
+			Attempts:  jobAttempts(run, job.Name),
+			Tests:     job.TestReport,
+			Timings:   recordedTimings(job),
+			Resources: jobResources(job.ResourceUsage),
+			Error:     classifyError(job.Err),
+		}
+
+		// the recorded jobs do not have to follow the workflow order
+		if _, snapshot, ok := jobRun(run, job.Name); ok {
+			jobReport.Duration = jobDuration(snapshot)
+		}
+
+		var cmdErr *commandError
+
+		if errors.As(job.Err, &cmdErr) && cmdErr.exitCode >= 0 {
+			jobReport.ExitCode = &cmdErr.exitCode
+		} else if job.Status == JobStatusSuccess && job.Uses == "" && len(job.Script) > 0 {
+			exitCode := 0
+			jobReport.ExitCode = &exitCode
+		}
+
+		for _, artifact := range job.ArtifactFiles {
+			jobReport.Artifacts = append(jobReport.Artifacts, artifact.Path)
+		}
+
+		report.Jobs = append(report.Jobs, jobReport)
+	}
+
+	return report
+}
+
+func writeReport(w io.Writer, output string, report RunReport) error {
+	var b []byte
+	var err error
+
+	if output == "yaml" {
+		b, err = yaml.Marshal(report)
+	} else {
+		b, err = json.MarshalIndent(report, "", "  ")
+		b = append(b, '\n')
+	}
+
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(b)
+
+	return err
+}
+
+// redirectLogs sends everything pin prints to stderr, so stdout only carries
+// the report, and returns the original stdout and a function restoring it.
+func redirectLogs() (io.Writer, func()) {
+	stdout, colorOutput := os.Stdout, color.Output
+
+	os.Stdout = os.Stderr
+	color.Output = colorTracker{w: os.Stderr}
+
+	return stdout, func() {
+		os.Stdout = stdout
+		color.Output = colorOutput
+	}
+}
@@ -0,0 +1,149 @@
+package runner
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/muhammedikinci/pin/internal/interfaces"
+	"github.com/muhammedikinci/pin/internal/pin_error"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestNewRunReportHasExitCodesArtifactsAndErrors(t *testing.T) {
+	start := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	build := &Job{Name: "build", Script: []string{"go build"}, Status: JobStatusSuccess, StartedAt: start, FinishedAt: start.Add(time.Second), ArtifactFiles: []ArtifactFile{{Path: "dist/pin", Size: 10}}}
+	test := &Job{Name: "test", Script: []string{"go test"}, Status: JobStatusFailed, Err: &commandError{exitCode: 2}, StartedAt: start.Add(time.Second), FinishedAt: start.Add(3 * time.Second)}
+	pull := &Job{Name: "pull", Status: JobStatusFailed, Err: pin_error.Timeout("ImagePull", time.Minute, nil)}
+
+	pipeline := Pipeline{Workflow: []*Job{build, test, pull}}
+	run := newRunMetadata("id", "test", "pipeline.yaml", pipeline)
+
+	report := newRunReport(run, pipeline, test.Err)
+
+	assert.Equal(t, JobStatusFailed, report.Status)
+	assert.Equal(t, 3*time.Second, report.Duration)
+	assert.Equal(t, &pin_error.PinError{Code: pin_error.CodeScriptFailed, Message: "command execution failed", Err: test.Err}, report.Error)
+
+	assert.Equal(t, 0, *report.Jobs[0].ExitCode)
+	assert.Equal(t, []string{"dist/pin"}, report.Jobs[0].Artifacts)
+	assert.Nil(t, report.Jobs[0].Error)
+
+	assert.Equal(t, 2, *report.Jobs[1].ExitCode)
+	assert.Equal(t, 2*time.Second, report.Jobs[1].Duration)
+
+	assert.Nil(t, report.Jobs[2].ExitCode)
+	assert.Equal(t, pin_error.CodeTimeout, report.Jobs[2].Error.Code)
+	assert.Equal(t, "ImagePull", report.Jobs[2].Error.Operation)
+}
+
+func TestNewRunReportMatchesTheRecordedJobsByName(t *testing.T) {
+	start := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	pipeline := Pipeline{Workflow: []*Job{{Name: "build"}, {Name: "test"}}}
+	run := RunMetadata{ID: "id", Jobs: []JobSnapshot{
+		{Name: "test", StartedAt: start, FinishedAt: start.Add(2 * time.Second)},
+		{Name: "build", StartedAt: start, FinishedAt: start.Add(time.Second)},
+	}}
+
+	report := newRunReport(run, pipeline, nil)
+
+	assert.Equal(t, time.Second, report.Jobs[0].Duration)
+	assert.Equal(t, 2*time.Second, report.Jobs[1].Duration)
+}
+
+func TestNewRunReportHasTheResourcePeaksOfSampledJobs(t *testing.T) {
+	build := &Job{Name: "build", Status: JobStatusSuccess, ResourceUsage: interfaces.ResourceUsage{PeakCPUPercent: 85, PeakMemoryBytes: 512 << 20, NetworkRxBytes: 2048, NetworkTxBytes: 100, Samples: 4}}
+	deploy := &Job{Name: "deploy", Uses: "./deploy", Status: JobStatusSuccess}
+
+	pipeline := Pipeline{Workflow: []*Job{build, deploy}}
+
+	report := newRunReport(newRunMetadata("id", "test", "pipeline.yaml", pipeline), pipeline, nil)
+
+	assert.Equal(t, &JobResources{PeakCPUPercent: 85, PeakMemoryBytes: 512 << 20, NetworkRxBytes: 2048, NetworkTxBytes: 100}, report.Jobs[0].Resources)
+	assert.Nil(t, report.Jobs[1].Resources)
+}
+
+func TestWriteReportAsJSONAndYAML(t *testing.T) {
+	report := RunReport{ID: "id", Status: JobStatusFailed, Jobs: []JobReport{}, Error: pin_error.From(errors.New("boom"))}
+
+	var b bytes.Buffer
+
+	assert.NoError(t, writeReport(&b, "json", report))
+
+	decoded := map[string]interface{}{}
+
+	assert.NoError(t, json.Unmarshal(b.Bytes(), &decoded))
+	assert.Equal(t, map[string]interface{}{"code": "ERROR", "message": "boom"}, decoded["error"])
+
+	b.Reset()
+
+	assert.NoError(t, writeReport(&b, "yaml", report))
+
+	decoded = map[string]interface{}{}
+
+	assert.NoError(t, yaml.Unmarshal(b.Bytes(), &decoded))
+	assert.Equal(t, map[string]interface{}{"code": "ERROR", "message": "boom"}, decoded["error"])
+}
+
+func TestApplyWithOutputWritesTheErrorReportToStdout(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pipeline.yaml")
+	os.WriteFile(path, []byte("workflow:\n  - build\n"), 0644)
+
+	r, w, _ := os.Pipe()
+
+	defer func(stdout *os.File) { os.Stdout = stdout }(os.Stdout)
+
+	os.Stdout = w
+
+	err := Apply("", []string{path}, ApplyOptions{Output: "json"})
+
+	w.Close()
+	out, _ := io.ReadAll(r)
+
+	assert.Error(t, err)
+
+	report := RunReport{}
+
+	assert.NoError(t, json.Unmarshal(out, &report))
+	assert.Equal(t, JobStatusFailed, report.Status)
+	assert.Equal(t, pin_error.CodeValidation, report.Error.Code)
+	assert.Equal(t, "image not specified", report.Error.Message)
+	assert.EqualError(t, err, "VALIDATION: image not specified")
+}
+
+func TestApplyWithOutputReportsInvalidFlags(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pipeline.yaml")
+	os.WriteFile(path, []byte("workflow:\n  - build\n"), 0644)
+
+	r, w, _ := os.Pipe()
+
+	defer func(stdout *os.File) { os.Stdout = stdout }(os.Stdout)
+
+	os.Stdout = w
+
+	err := Apply("", []string{path}, ApplyOptions{Output: "json", MaxParallel: -1})
+
+	w.Close()
+	out, _ := io.ReadAll(r)
+
+	report := RunReport{}
+
+	assert.NoError(t, json.Unmarshal(out, &report))
+	assert.Equal(t, JobStatusFailed, report.Status)
+	assert.Equal(t, "invalid --max-parallel: -1, use a positive number", report.Error.Message)
+	assert.EqualError(t, err, "VALIDATION: invalid --max-parallel: -1, use a positive number")
+}
+
+func TestApplyRejectsUnknownOutputFormats(t *testing.T) {
+	err := Apply("", []string{"pipeline.yaml"}, ApplyOptions{Output: "xml"})
+
+	assert.EqualError(t, err, "VALIDATION: unsupported output format: xml")
+}
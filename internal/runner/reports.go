@@ -0,0 +1,192 @@
+package runner
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// runReportsDir is the directory of a run that keeps the test reports of
+// its jobs, one directory per job.
+const runReportsDir = "reports"
+
+type Reports struct {
+	JUnit []string
+}
+
+// TestReport aggregates the junit reports of a job, it is collected after
+// the script whether it failed or not.
+type TestReport struct {
+	Tests    int      `json:"tests" yaml:"tests"`
+	Failures int      `json:"failures" yaml:"failures"`
+	Errors   int      `json:"errors" yaml:"errors"`
+	Skipped  int      `json:"skipped" yaml:"skipped"`
+	Failed   []string `json:"failed,omitempty" yaml:"failed,omitempty"`
+	Files    []string `json:"files,omitempty" yaml:"files,omitempty"`
+
+	collected bool
+}
+
+type junitSuite struct {
+	Suites []junitSuite `xml:"testsuite"`
+	Cases  []junitCase  `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string    `xml:"name,attr"`
+	ClassName string    `xml:"classname,attr"`
+	Failure   *struct{} `xml:"failure"`
+	Error     *struct{} `xml:"error"`
+	Skipped   *struct{} `xml:"skipped"`
+}
+
+// getReports accepts the junit report paths of a job as a path or a list of
+// paths, relative to the workdir and glob patterns like artifacts.
+func getReports(reports interface{}) (*Reports, error) {
+	if reports == nil {
+		return nil, nil
+	}
+
+	reportsMap := getStringMap(reports)
+
+	if len(reportsMap) == 0 {
+		return nil, errors.New("reports must be a mapping of report formats to paths")
+	}
+
+	for key := range reportsMap {
+		if key != "junit" {
+			return nil, fmt.Errorf("unsupported report format: %s", key)
+		}
+	}
+
+	junit, err := getStringArray("junit report paths", reportsMap["junit"])
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(junit) == 0 {
+		return nil, errors.New("junit report paths not specified")
+	}
+
+	return &Reports{JUnit: junit}, nil
+}
+
+// collectReports copies the junit reports out of the job container into the
+// run history and parses them. A report that can not be read only prints a
+// warning, the script decides whether the job failed.
+func (r Runner) collectReports(currentJob Job) {
+	if currentJob.TestReport == nil || currentJob.TestReport.collected {
+		return
+	}
+
+	currentJob.TestReport.collected = true
+
+	if err := r.readReports(currentJob); err != nil {
+		color.Set(color.FgYellow)
+		currentJob.InfoLog.Printf("warning: test reports could not be collected: %s", err)
+		color.Unset()
+		return
+	}
+
+	report := currentJob.TestReport
+
+	currentJob.InfoLog.Printf("Test reports: %d tests, %d failed, %d skipped", report.Tests, report.Failures+report.Errors, report.Skipped)
+}
+
+func (r Runner) readReports(currentJob Job) error {
+	dir, err := runDir(r.runID)
+
+	if err != nil {
+		return err
+	}
+
+	destination := filepath.Join(dir, runReportsDir, filepath.Base(currentJob.Name))
+
+	files, err := currentJob.ContainerManager.CopyFromContainer(r.ctx, currentJob.Container.ID, currentJob.WorkDir, currentJob.Reports.JUnit, destination)
+
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		f, err := os.Open(filepath.Join(destination, filepath.FromSlash(file)))
+
+		if err != nil {
+			return err
+		}
+
+		err = parseJUnit(f, currentJob.TestReport)
+		f.Close()
+
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+
+		currentJob.TestReport.Files = append(currentJob.TestReport.Files, file)
+	}
+
+	return nil
+}
+
+// parseJUnit adds the test cases of a junit report to report, the root is
+// a testsuites or a testsuite element and suites can be nested.
+func parseJUnit(r io.Reader, report *TestReport) error {
+	var suite junitSuite
+
+	if err := xml.NewDecoder(r).Decode(&suite); err != nil {
+		return err
+	}
+
+	addJUnitSuite(suite, report)
+
+	return nil
+}
+
+func addJUnitSuite(suite junitSuite, report *TestReport) {
+	for _, c := range suite.Cases {
+		report.Tests++
+
+		name := c.Name
+
+		if c.ClassName != "" {
+			name = c.ClassName + "." + c.Name
+		}
+
+		switch {
+		case c.Failure != nil:
+			report.Failures++
+			report.Failed = append(report.Failed, name)
+		case c.Error != nil:
+			report.Errors++
+			report.Failed = append(report.Failed, name)
+		case c.Skipped != nil:
+			report.Skipped++
+		}
+	}
+
+	for _, nested := range suite.Suites {
+		addJUnitSuite(nested, report)
+	}
+}
+
+// printTestReports lists the failed tests of every job below the summary.
+func printTestReports(w io.Writer, jobs []JobReport) {
+	for _, job := range jobs {
+		if job.Tests == nil || len(job.Tests.Failed) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(w, "Failed tests of %s (%d of %d):\n", job.Name, len(job.Tests.Failed), job.Tests.Tests)
+
+		for _, name := range job.Tests.Failed {
+			fmt.Fprintf(w, "  %s %s\n", color.New(color.FgRed).Sprint(glyph(glyphFailure)), strings.TrimSpace(name))
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/muhammedikinci/pin/internal/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+const testJUnitReport = `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+  <testsuite name="api">
+    <testcase classname="api.Users" name="create"/>
+    <testcase classname="api.Users" name="delete">
+      <failure message="expected 204">assertion failed</failure>
+    </testcase>
+    <testsuite name="nested">
+      <testcase name="timeout"><error message="panic"/></testcase>
+      <testcase name="later"><skipped/></testcase>
+    </testsuite>
+  </testsuite>
+</testsuites>
+`
+
+func TestGetReportsAcceptsJUnitPaths(t *testing.T) {
+	reports, err := getReports(map[string]interface{}{"junit": "reports/*.xml"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &Reports{JUnit: []string{"reports/*.xml"}}, reports)
+
+	reports, err = getReports(nil)
+
+	assert.NoError(t, err)
+	assert.Nil(t, reports)
+
+	_, err = getReports(map[string]interface{}{"cobertura": "coverage.xml"})
+
+	assert.EqualError(t, err, "unsupported report format: cobertura")
+
+	_, err = getReports("reports/*.xml")
+
+	assert.EqualError(t, err, "reports must be a mapping of report formats to paths")
+}
+
+func TestParseJUnitCountsNestedSuites(t *testing.T) {
+	report := &TestReport{}
+
+	assert.NoError(t, parseJUnit(strings.NewReader(testJUnitReport), report))
+
+	assert.Equal(t, 4, report.Tests)
+	assert.Equal(t, 1, report.Failures)
+	assert.Equal(t, 1, report.Errors)
+	assert.Equal(t, 1, report.Skipped)
+	assert.Equal(t, []string{"api.Users.delete", "timeout"}, report.Failed)
+
+	assert.Error(t, parseJUnit(strings.NewReader("not xml"), &TestReport{}))
+}
+
+func TestCollectReportsCopiesReportsIntoTheRunHistory(t *testing.T) {
+	t.Setenv("PIN_HOME", t.TempDir())
+
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	containerManager := mocks.NewMockContainerManager(ctrl)
+
+	containerManager.EXPECT().
+		CopyFromContainer(gomock.Any(), "abc", "/app", []string{"reports/*.xml"}, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, containerID, workDir string, patterns []string, destination string) ([]string, error) {
+			assert.NoError(t, os.MkdirAll(filepath.Join(destination, "reports"), 0755))
+			assert.NoError(t, os.WriteFile(filepath.Join(destination, "reports", "unit.xml"), []byte(testJUnitReport), 0644))
+
+			return []string{"reports/unit.xml"}, nil
+		}).
+		Times(1)
+
+	var logs bytes.Buffer
+
+	job := Job{
+		Name:             "test",
+		WorkDir:          "/app",
+		Reports:          &Reports{JUnit: []string{"reports/*.xml"}},
+		TestReport:       &TestReport{},
+		ContainerManager: containerManager,
+		InfoLog:          log.New(&logs, "", 0),
+	}
+	job.Container.ID = "abc"
+
+	r := Runner{runID: "20220515-101500-a1b2c3"}
+
+	r.collectReports(job)
+
+	// a failure after the script does not copy the reports twice
+	r.collectReports(job)
+
+	assert.Equal(t, 4, job.TestReport.Tests)
+	assert.Equal(t, []string{"reports/unit.xml"}, job.TestReport.Files)
+	assert.Contains(t, logs.String(), "Test reports: 4 tests, 2 failed, 1 skipped")
+
+	dir, err := runDir("20220515-101500-a1b2c3")
+	assert.NoError(t, err)
+	assert.FileExists(t, filepath.Join(dir, runReportsDir, "test", "reports", "unit.xml"))
+}
@@ -0,0 +1,156 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Rerun executes a previous run again from its stored configuration, either
+// completely or only the jobs that failed.
+func Rerun(runID string, onlyFailed bool) error {
+	run, err := loadRun(runID)
+
+	if err != nil {
+		err = fmt.Errorf("run %s not found: %w", runID, err)
+		fmt.Println(err)
+		return err
+	}
+
+	configPath, content, pipeline, err := storedPipeline(run)
+
+	if err != nil {
+		fmt.Println(err)
+		return err
+	}
+
+	if onlyFailed {
+		failed := map[string]bool{}
+
+		for _, job := range run.Jobs {
+			if job.Status == JobStatusFailed {
+				failed[job.Name] = true
+			}
+		}
+
+		if len(failed) == 0 {
+			fmt.Printf("Run %s has no failed jobs\n", run.ID)
+			return nil
+		}
+
+		pipeline = selectJobs(pipeline, failed)
+	}
+
+	if run.ProjectPath != "" {
+		if err := os.Chdir(run.ProjectPath); err != nil {
+			fmt.Println(err)
+			return err
+		}
+	}
+
+	fmt.Printf("Rerunning %s (%s)\n", run.ID, run.Pipeline)
+
+	_, err = executePipeline(run.Pipeline, configPath, content, pipeline, run.ID)
+
+	return err
+}
+
+// runAttempt numbers a run that reruns rerunOf, following the runs it reran.
+// A new run is attempt 1, runs recorded before attempts count as 1.
+func runAttempt(rerunOf string) int {
+	if rerunOf == "" {
+		return 1
+	}
+
+	run, err := loadRun(rerunOf)
+
+	if err != nil {
+		return 2
+	}
+
+	if run.Attempt == 0 {
+		return runAttempt(run.RerunOf) + 1
+	}
+
+	return run.Attempt + 1
+}
+
+// storedPipeline parses the pipeline configuration saved with a run. The
+// returned path is the original pipeline file, the build context and the
+// paths of the next rerun belong to its directory rather than to the copy.
+// The jobs run with the image digests recorded for the run.
+func storedPipeline(run RunMetadata) (string, []byte, Pipeline, error) {
+	dir, err := runDir(run.ID)
+
+	if err != nil {
+		return "", nil, Pipeline{}, err
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "pipeline.yaml"))
+
+	var config map[string]interface{}
+
+	if err == nil {
+		config, err = decodeConfig(content)
+	}
+
+	// relative paths belong to the directory of the original pipeline file
+	if err == nil {
+		err = resolveConfigPaths(config, rerunConfigDir(run))
+	}
+
+	if err != nil {
+		return "", nil, Pipeline{}, err
+	}
+
+	pipeline, warnings, err := parse(config)
+
+	printWarnings(warnings)
+
+	if err == nil {
+		pinImages(pipeline, run)
+	}
+
+	return rerunConfigPath(run), content, pipeline, err
+}
+
+// pinImages replaces the image of every job with the repo digest it ran with,
+// so a tag that moved since the run does not change what is rerun. Built
+// images and images without a repo digest keep their name.
+func pinImages(pipeline Pipeline, run RunMetadata) {
+	for _, job := range pipeline.Workflow {
+		if job.Dockerfile != "" || job.Uses != "" {
+			continue
+		}
+
+		_, snapshot, ok := jobRun(run, job.Name)
+
+		if !ok || !strings.Contains(snapshot.ImageDigest, "@sha256:") || snapshot.ImageDigest == job.Image {
+			continue
+		}
+
+		fmt.Printf("Image of %s pinned to %s\n", job.Name, snapshot.ImageDigest)
+
+		job.Image = snapshot.ImageDigest
+	}
+}
+
+// rerunConfigPath is the pipeline file of a run, a run read from stdin or
+// recorded without it resolves its paths from the project directory, which
+// the rerun changes into.
+func rerunConfigPath(run RunMetadata) string {
+	if run.ConfigPath == "" {
+		return stdinConfigPath
+	}
+
+	return run.ConfigPath
+}
+
+func rerunConfigDir(run RunMetadata) string {
+	if run.ConfigPath == stdinConfigPath || run.ConfigPath == "" {
+		return run.ProjectPath
+	}
+
+	return configDir(run.ConfigPath)
+}
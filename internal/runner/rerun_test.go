@@ -0,0 +1,84 @@
+package runner
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/golang/mock/gomock"
+	"github.com/muhammedikinci/pin/internal/interfaces"
+	"github.com/muhammedikinci/pin/internal/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRerunBuildsTheDockerfileInThePipelineDirectory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	project := t.TempDir()
+	os.MkdirAll(filepath.Join(project, "ci"), 0755)
+	os.WriteFile(filepath.Join(project, "ci", "Dockerfile"), []byte("FROM alpine\n"), 0644)
+	os.WriteFile(filepath.Join(project, "ci", "pipeline.yaml"), []byte("workflow:\n  - build\nbuild:\n  dockerfile: ./Dockerfile\n  script:\n    - echo built\n"), 0644)
+
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+	os.Chdir(project)
+
+	t.Setenv("PIN_HOME", t.TempDir())
+
+	mockCli := mocks.NewMockClient(ctrl)
+	mockCli.EXPECT().ServerVersion(gomock.Any()).Return(types.Version{}, nil).AnyTimes()
+
+	// the build stops the job, every run has to get that far
+	mockCli.EXPECT().ImageBuild(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_, _ interface{}, options types.ImageBuildOptions) (types.ImageBuildResponse, error) {
+		assert.Equal(t, "Dockerfile", options.Dockerfile)
+
+		return types.ImageBuildResponse{}, errors.New("build stopped")
+	}).Times(3)
+
+	defer func(f func() (interfaces.Client, error)) { newDockerClient = f }(newDockerClient)
+
+	newDockerClient = func() (interfaces.Client, error) {
+		return mockCli, nil
+	}
+
+	t.Setenv(detachedRunEnv, "20220515-101500-a1b2c3")
+	assert.EqualError(t, Apply("", []string{filepath.Join("ci", "pipeline.yaml")}, ApplyOptions{}), "build stopped")
+
+	// a rerun of the rerun reads the pipeline saved with the first rerun
+	t.Setenv(detachedRunEnv, "20220515-101600-d4e5f6")
+	assert.EqualError(t, Rerun("20220515-101500-a1b2c3", false), "build stopped")
+
+	t.Setenv(detachedRunEnv, "20220515-101700-a7b8c9")
+	assert.EqualError(t, Rerun("20220515-101600-d4e5f6", false), "build stopped")
+
+	run, err := loadRun("20220515-101700-a7b8c9")
+
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(project, "ci", "pipeline.yaml"), run.ConfigPath)
+}
+
+func TestStoredPipelinePinsTheRecordedImageDigests(t *testing.T) {
+	t.Setenv("PIN_HOME", t.TempDir())
+
+	content := []byte("workflow:\n  - test\n  - lint\n  - build\ntest:\n  image: golang:alpine3.15\nlint:\n  image: golangci-lint\nbuild:\n  dockerfile: ./Dockerfile\n")
+
+	assert.NoError(t, saveRun(RunMetadata{ID: "20220515-100000-aaaaaa", Jobs: []JobSnapshot{
+		{Name: "test", Image: "golang:alpine3.15", ImageDigest: "golang@sha256:2a8a4b5c"},
+		{Name: "lint", Image: "golangci-lint", ImageDigest: "sha256:9f8e7d6c"},
+		{Name: "build", Image: "build", ImageDigest: "build@sha256:1b2c3d4e"},
+	}}, content))
+
+	run, _ := loadRun("20220515-100000-aaaaaa")
+
+	_, _, pipeline, err := storedPipeline(run)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "golang@sha256:2a8a4b5c", pipeline.Workflow[0].Image)
+	// images that were never pushed have no repo digest to pull
+	assert.Equal(t, "golangci-lint", pipeline.Workflow[1].Image)
+	assert.NotEqual(t, "build@sha256:1b2c3d4e", pipeline.Workflow[2].Image)
+}
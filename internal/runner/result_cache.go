@@ -0,0 +1,97 @@
+package runner
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// resultCacheKey hashes the image digest, the script and env of the job and
+// the project files it gets, an identical key means an identical job.
+func (r Runner) resultCacheKey(currentJob *Job) (string, error) {
+	digest, err := currentJob.ImageManager.ImageDigest(r.ctx, currentJob.Image)
+
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+
+	io.WriteString(h, digest+"\n")
+	io.WriteString(h, strings.Join(currentJob.Script, "\n")+"\n")
+	io.WriteString(h, strings.Join(jobEnv(currentJob), "\n")+"\n")
+
+	if currentJob.CopyFiles || currentJob.MountFiles {
+		currentPath, err := projectDir()
+
+		if err != nil {
+			return "", err
+		}
+
+		files, err := workspaceFiles(currentPath, currentJob.CopyIgnore, currentJob.CopyInclude)
+
+		if err != nil {
+			return "", err
+		}
+
+		names := make([]string, 0, len(files))
+
+		for name := range files {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		for _, name := range names {
+			io.WriteString(h, name+" "+files[name]+"\n")
+		}
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func resultCacheFile(jobName, key string) (string, error) {
+	base, err := stateDir()
+
+	if err != nil {
+		return "", err
+	}
+
+	project, err := projectID()
+
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(base, "results", project, unsafeKeyChars.ReplaceAllString(jobName, "_"), key), nil
+}
+
+func hasResult(jobName, key string) bool {
+	file, err := resultCacheFile(jobName, key)
+
+	if err != nil {
+		return false
+	}
+
+	_, err = os.Stat(file)
+
+	return err == nil
+}
+
+func saveResult(jobName, key string) error {
+	file, err := resultCacheFile(jobName, key)
+
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(file, nil, 0644)
+}
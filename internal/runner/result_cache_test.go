@@ -0,0 +1,55 @@
+package runner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/muhammedikinci/pin/internal/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResultCacheKeyChangesWithDigestAndScript(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	mockImageManager := mocks.NewMockImageManager(ctrl)
+
+	gomock.InOrder(
+		mockImageManager.EXPECT().ImageDigest(gomock.Any(), "golang:1").Return("golang@sha256:1", nil),
+		mockImageManager.EXPECT().ImageDigest(gomock.Any(), "golang:1").Return("golang@sha256:1", nil),
+		mockImageManager.EXPECT().ImageDigest(gomock.Any(), "golang:1").Return("golang@sha256:2", nil),
+		mockImageManager.EXPECT().ImageDigest(gomock.Any(), "golang:1").Return("golang@sha256:2", nil),
+	)
+
+	r := Runner{ctx: context.Background()}
+	job := &Job{Name: "test", Image: "golang:1", Script: []string{"go test ./..."}, ImageManager: mockImageManager}
+
+	first, err := r.resultCacheKey(job)
+	assert.Equal(t, err, nil)
+
+	same, _ := r.resultCacheKey(job)
+	assert.Equal(t, first, same)
+
+	newDigest, _ := r.resultCacheKey(job)
+	assert.NotEqual(t, first, newDigest)
+
+	job.Script = []string{"go test -race ./..."}
+
+	newScript, _ := r.resultCacheKey(job)
+	assert.NotEqual(t, newDigest, newScript)
+}
+
+func TestSaveResultRecordsEveryKey(t *testing.T) {
+	t.Setenv("PIN_HOME", t.TempDir())
+
+	assert.False(t, hasResult("test", "a"))
+
+	assert.Equal(t, nil, saveResult("test", "a"))
+	assert.Equal(t, nil, saveResult("test", "b"))
+
+	assert.True(t, hasResult("test", "a"))
+	assert.True(t, hasResult("test", "b"))
+	assert.False(t, hasResult("lint", "a"))
+}
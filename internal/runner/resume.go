@@ -0,0 +1,106 @@
+package runner
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+)
+
+// resumeStart returns the job a resumed run starts from, the first job of
+// the workflow that did not succeed in the run. It is empty when every job
+// succeeded.
+func resumeStart(pipeline Pipeline, run RunMetadata) string {
+	statuses := map[string]string{}
+
+	for _, job := range run.Jobs {
+		statuses[job.Name] = job.Status
+	}
+
+	for _, job := range pipeline.Workflow {
+		switch statuses[job.Name] {
+		case JobStatusSuccess, JobStatusCached, JobStatusCacheHit:
+			continue
+		}
+
+		return job.Name
+	}
+
+	return ""
+}
+
+// fromJob keeps the jobs of the workflow starting with the named one.
+func fromJob(pipeline Pipeline, name string) (Pipeline, error) {
+	for i, job := range pipeline.Workflow {
+		if job.Name != name {
+			continue
+		}
+
+		selected := append([]*Job{}, pipeline.Workflow[i:]...)
+
+		linkJobs(selected)
+		pipeline.Workflow = selected
+
+		return pipeline, nil
+	}
+
+	return pipeline, fmt.Errorf("job %s is not in the workflow", name)
+}
+
+// restoreArtifacts copies the artifacts that the jobs skipped by a resume
+// collected back to their destination, so the resumed jobs find them like in
+// a full run.
+func restoreArtifacts(skipped []*Job, run RunMetadata) error {
+	for _, job := range skipped {
+		if job.Artifacts == nil {
+			continue
+		}
+
+		source, snapshot, ok := jobRun(run, job.Name)
+
+		if !ok || len(snapshot.Artifacts) == 0 {
+			continue
+		}
+
+		files := []string{}
+
+		for _, artifact := range snapshot.Artifacts {
+			files = append(files, artifact.Path)
+		}
+
+		destination := artifactsDestination(job)
+
+		if err := restoreRunArtifacts(source.ID, job.Name, destination, files); err != nil {
+			return fmt.Errorf("artifacts of %s could not be restored: %w", job.Name, err)
+		}
+
+		color.Set(color.FgGreen)
+		fmt.Printf("Artifacts of %s restored from run %s: %d files to %s\n", job.Name, source.ID, len(files), destination)
+		color.Unset()
+	}
+
+	return nil
+}
+
+// jobRun returns the run that last ran a job, a resumed run only has the
+// jobs it ran so the runs it resumed are searched too.
+func jobRun(run RunMetadata, name string) (RunMetadata, JobSnapshot, bool) {
+	for {
+		for _, job := range run.Jobs {
+			if job.Name == name {
+				return run, job, true
+			}
+		}
+
+		if run.RerunOf == "" {
+			return run, JobSnapshot{}, false
+		}
+
+		previous, err := loadRun(run.RerunOf)
+
+		if err != nil {
+			return run, JobSnapshot{}, false
+		}
+
+		run = previous
+	}
+}
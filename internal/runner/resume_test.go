@@ -0,0 +1,86 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResumeStartIsTheFirstJobThatDidNotSucceed(t *testing.T) {
+	pipeline := Pipeline{Workflow: []*Job{{Name: "build"}, {Name: "test"}, {Name: "deploy"}, {Name: "notify"}}}
+
+	run := RunMetadata{Jobs: []JobSnapshot{
+		{Name: "build", Status: JobStatusCached},
+		{Name: "test", Status: JobStatusSuccess},
+		{Name: "deploy", Status: JobStatusFailed},
+		{Name: "notify", Status: JobStatusSkipped},
+	}}
+
+	assert.Equal(t, "deploy", resumeStart(pipeline, run))
+
+	// jobs added to the pipeline after the run have not succeeded yet
+	pipeline.Workflow = append([]*Job{{Name: "lint"}}, pipeline.Workflow...)
+
+	assert.Equal(t, "lint", resumeStart(pipeline, run))
+
+	run.Jobs[2].Status = JobStatusSuccess
+	run.Jobs[3].Status = JobStatusSuccess
+	pipeline.Workflow = pipeline.Workflow[1:]
+
+	assert.Equal(t, "", resumeStart(pipeline, run))
+}
+
+func TestFromJobSkipsTheJobsBeforeIt(t *testing.T) {
+	build := &Job{Name: "build"}
+	test := &Job{Name: "test"}
+	deploy := &Job{Name: "deploy"}
+
+	pipeline := Pipeline{Workflow: []*Job{build, test, deploy}}
+	linkJobs(pipeline.Workflow)
+
+	selected, err := fromJob(pipeline, "test")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []*Job{test, deploy}, selected.Workflow)
+	assert.Nil(t, test.Needs)
+	assert.Equal(t, []*Job{test}, deploy.Needs)
+
+	_, err = fromJob(pipeline, "release")
+
+	assert.EqualError(t, err, "job release is not in the workflow")
+}
+
+func TestRestoreArtifactsCopiesArtifactsOfSkippedJobs(t *testing.T) {
+	t.Setenv("PIN_HOME", t.TempDir())
+
+	source := t.TempDir()
+
+	assert.NoError(t, os.MkdirAll(filepath.Join(source, "bin"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(source, "bin", "app"), []byte("binary"), 0755))
+	assert.NoError(t, storeRunArtifacts("20220515-100000-aaaaaa", "build", source, []string{"bin/app"}))
+
+	assert.NoError(t, saveRun(RunMetadata{ID: "20220515-100000-aaaaaa", Jobs: []JobSnapshot{
+		{Name: "build", Status: JobStatusSuccess, Artifacts: []ArtifactFile{{Path: "bin/app", Size: 6}}},
+		{Name: "test", Status: JobStatusFailed},
+	}}, []byte("workflow: []")))
+
+	// the second run resumed the first one and only ran test
+	assert.NoError(t, saveRun(RunMetadata{ID: "20220515-110000-bbbbbb", RerunOf: "20220515-100000-aaaaaa", Jobs: []JobSnapshot{
+		{Name: "test", Status: JobStatusFailed},
+	}}, []byte("workflow: []")))
+
+	run, err := loadRun("20220515-110000-bbbbbb")
+	assert.NoError(t, err)
+
+	destination := filepath.Join(t.TempDir(), "out")
+
+	err = restoreArtifacts([]*Job{{Name: "build", Artifacts: &Artifacts{Destination: destination}}, {Name: "lint"}}, run)
+
+	assert.NoError(t, err)
+
+	b, err := os.ReadFile(filepath.Join(destination, "bin", "app"))
+	assert.NoError(t, err)
+	assert.Equal(t, "binary", string(b))
+}
@@ -0,0 +1,74 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+)
+
+// Retry executes the jobs of a previous run that failed and the jobs that
+// were skipped after them, the artifacts of the jobs that succeeded are
+// restored from the run history instead of producing them again.
+func Retry(runID string) error {
+	run, err := loadRun(runID)
+
+	if err != nil {
+		err = fmt.Errorf("run %s not found: %w", runID, err)
+		fmt.Println(err)
+		return err
+	}
+
+	configPath, content, pipeline, err := storedPipeline(run)
+
+	if err != nil {
+		fmt.Println(err)
+		return err
+	}
+
+	retried, kept := retryJobs(pipeline, run)
+
+	if len(retried) == 0 {
+		fmt.Printf("Run %s has no failed jobs\n", run.ID)
+		return nil
+	}
+
+	pipeline = selectJobs(pipeline, retried)
+
+	if run.ProjectPath != "" {
+		if err := os.Chdir(run.ProjectPath); err != nil {
+			fmt.Println(err)
+			return err
+		}
+	}
+
+	if err := restoreArtifacts(kept, run); err != nil {
+		fmt.Println(err)
+		return err
+	}
+
+	fmt.Printf("Retrying %d jobs of %s (%s)\n", len(pipeline.Workflow), run.ID, run.Pipeline)
+
+	_, err = executePipeline(run.Pipeline, configPath, content, pipeline, run.ID)
+
+	return err
+}
+
+// retryJobs splits the workflow into the jobs to retry and the jobs whose
+// result is kept. A job is kept when it succeeded, in the run or in the run
+// it retried, every other job failed or did not run because of a failure.
+func retryJobs(pipeline Pipeline, run RunMetadata) (map[string]bool, []*Job) {
+	retried := map[string]bool{}
+	kept := []*Job{}
+
+	for _, job := range pipeline.Workflow {
+		_, snapshot, _ := jobRun(run, job.Name)
+
+		switch snapshot.Status {
+		case JobStatusSuccess, JobStatusCached, JobStatusCacheHit:
+			kept = append(kept, job)
+		default:
+			retried[job.Name] = true
+		}
+	}
+
+	return retried, kept
+}
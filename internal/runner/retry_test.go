@@ -0,0 +1,50 @@
+package runner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryJobsKeepsTheJobsThatSucceededInTheChain(t *testing.T) {
+	t.Setenv("PIN_HOME", t.TempDir())
+
+	assert.NoError(t, saveRun(RunMetadata{ID: "20220515-100000-aaaaaa", Jobs: []JobSnapshot{
+		{Name: "build", Status: JobStatusSuccess},
+		{Name: "lint", Status: JobStatusCached},
+		{Name: "test", Status: JobStatusFailed},
+		{Name: "deploy", Status: JobStatusSkipped},
+	}}, []byte("workflow: []")))
+
+	build := &Job{Name: "build"}
+	lint := &Job{Name: "lint"}
+	test := &Job{Name: "test"}
+	deploy := &Job{Name: "deploy"}
+
+	pipeline := Pipeline{Workflow: []*Job{build, lint, test, deploy}}
+
+	run, err := loadRun("20220515-100000-aaaaaa")
+	assert.NoError(t, err)
+
+	retried, kept := retryJobs(pipeline, run)
+
+	assert.Equal(t, map[string]bool{"test": true, "deploy": true}, retried)
+	assert.Equal(t, []*Job{build, lint}, kept)
+
+	// the retry fixed test but deploy failed, only deploy is left
+	retry := RunMetadata{ID: "20220515-110000-bbbbbb", RerunOf: "20220515-100000-aaaaaa", Jobs: []JobSnapshot{
+		{Name: "test", Status: JobStatusSuccess},
+		{Name: "deploy", Status: JobStatusFailed},
+	}}
+
+	retried, kept = retryJobs(pipeline, retry)
+
+	assert.Equal(t, map[string]bool{"deploy": true}, retried)
+	assert.Equal(t, []*Job{build, lint, test}, kept)
+
+	retry.Jobs[1].Status = JobStatusSuccess
+
+	retried, _ = retryJobs(pipeline, retry)
+
+	assert.Empty(t, retried)
+}
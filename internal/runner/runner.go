@@ -1,166 +1,558 @@
 package runner
 
 import (
-	"archive/tar"
 	"context"
-	"errors"
 	"fmt"
 	"io"
 	"log"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"strings"
 	"syscall"
 	"time"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/fatih/color"
 	"github.com/muhammedikinci/pin/internal/container_manager"
 	"github.com/muhammedikinci/pin/internal/image_manager"
 	"github.com/muhammedikinci/pin/internal/interfaces"
+	"github.com/muhammedikinci/pin/internal/log_level"
+	"github.com/muhammedikinci/pin/internal/pin_error"
 	"github.com/muhammedikinci/pin/internal/shell_commander"
+	"github.com/muhammedikinci/pin/internal/timing_client"
 )
 
 type Runner struct {
-	ctx context.Context
-	cli interfaces.Client
+	ctx            context.Context
+	cli            interfaces.Client
+	dockerCli      interfaces.Client
+	docker         *Docker
+	dockerVersion  string
+	runID          string
+	pipelineName   string
+	hostHooks      map[string]HostHook
+	hooks          *hookLog
+	healthcheck    *HealthcheckResult
+	chaos          *Chaos
+	debugOnFailure bool
+	ci             string
+	ciGroups       bool
+	logDir         string
+	configDir      string
+	secrets        *secretRegistry
+	attempt        int
+	startedAt      time.Time
 }
 
 func (r *Runner) run(pipeline Pipeline) error {
 	r.createGlobalContext(pipeline.Workflow)
+	r.hostHooks = pipeline.HostHooks
+	r.ciGroups = ciGroups(r.ci, pipeline.Workflow)
 
-	cli, err := client.NewClientWithOpts()
+	r.secrets = newSecretRegistry()
 
-	if err != nil {
+	for i, job := range pipeline.Workflow {
+		job.PrefixColor = jobColors[i%len(jobColors)]
+		r.secrets.add(jobEnv(job))
+	}
+
+	if err := r.runHostHook(HookPrePipeline, r.hostHooks, "", r.pipelineLogger()); err != nil {
 		return err
 	}
 
-	r.cli = cli
+	r.docker = pipeline.Docker
+
+	if needsDocker(pipeline.Workflow) {
+		cli, err := newDockerClient()
+
+		if err != nil {
+			return pin_error.New(pin_error.CodeDockerConnection, "", err.Error(), err)
+		}
+
+		r.dockerCli = cli
+		r.cli = r.dockerClient(cli, pipeline.Docker)
+
+		version, err := r.cli.ServerVersion(r.ctx)
+
+		if client.IsErrConnectionFailed(err) {
+			return err
+		}
+
+		if err == nil {
+			r.dockerVersion = version.Version
+		}
+	}
+
+	r.schedule(pipeline)
+
+	tolerateGroupFailures(pipeline.Workflow, r.pipelineLogger())
 
-	for _, job := range pipeline.Workflow {
-		go func(job *Job) {
-			r.jobRunner(job, pipeline.LogsWithTime)
-		}(job)
+	runErr := runError(pipeline.Workflow)
+
+	if runErr == nil {
+		runErr = r.checkSuccessCriteria(pipeline.SuccessCriteria)
 	}
 
-	err = <-pipeline.Workflow[len(pipeline.Workflow)-1].ErrorChannel
+	hookErr := r.runHostHook(HookPostPipeline, r.hostHooks, "", r.pipelineLogger())
+
+	if runErr != nil {
+		return runErr
+	}
 
-	return err
+	return hookErr
 }
 
 func (r *Runner) jobRunner(currentJob *Job, logsWithTime bool) {
+	currentJob.Output = newJobOutput(currentJob)
+
+	console := r.secrets.writer(currentJob.Output)
+
+	currentJob.ScriptOutput = newScriptOutput(console, currentJob)
+
 	if logsWithTime {
-		currentJob.InfoLog = log.New(os.Stdout, fmt.Sprintf("⚉ %s ", currentJob.Name), log.Ldate|log.Ltime)
+		currentJob.InfoLog = log.New(console, jobPrefix(currentJob), log.Ldate|log.Ltime)
 	} else {
-		currentJob.InfoLog = log.New(os.Stdout, fmt.Sprintf("⚉ %s ", currentJob.Name), 0)
+		currentJob.InfoLog = log.New(console, jobPrefix(currentJob), 0)
+	}
+
+	if currentJob.Docker != nil || log_level.IsVerbose() {
+		jobRunner := *r
+
+		if currentJob.Docker != nil {
+			jobRunner.cli = r.dockerClient(r.dockerCli, mergeDocker(r.docker, currentJob.Docker))
+		}
+
+		if log_level.IsVerbose() {
+			jobRunner.cli = timing_client.NewTimingClient(jobRunner.cli, currentJob.InfoLog)
+		}
+
+		r = &jobRunner
 	}
 
 	currentJob.ImageManager = image_manager.NewImageManager(r.cli, currentJob.InfoLog)
 	currentJob.ContainerManager = container_manager.NewContainerManager(r.cli, currentJob.InfoLog)
 	currentJob.ShellCommander = shell_commander.NewShellCommander()
 
-	if currentJob.Previous != nil && !currentJob.IsParallel {
-		previousJobError := <-currentJob.Previous.ErrorChannel
+	// jobs that did not start yet are skipped once the run is cancelled
+	if needsError(currentJob) != nil || r.ctx.Err() != nil {
+		currentJob.Status = JobStatusSkipped
+		return
+	}
+
+	if logFile := r.teeJobLog(currentJob); logFile != nil {
+		defer logFile.Close()
+	}
+
+	if r.ciGroups {
+		startCIGroup(os.Stdout, r.ci, currentJob.Name)
+	}
+
+	currentJob.Timings = &JobTimings{}
+	currentJob.StartedAt = time.Now()
+
+	err := r.runHostHook(HookPreJob, r.hostHooks, currentJob.Name, currentJob.InfoLog)
+
+	if err == nil {
+		err = r.executeJob(currentJob)
+	}
+
+	if hookErr := r.runHostHook(HookPostJob, r.hostHooks, currentJob.Name, currentJob.InfoLog); err == nil {
+		err = hookErr
+	}
+
+	currentJob.ScriptOutput.Flush()
+	currentJob.FinishedAt = time.Now()
 
-		if previousJobError != nil {
-			currentJob.ErrorChannel <- nil
-			return
+	if r.ciGroups {
+		endCIGroup(os.Stdout, r.ci, currentJob.Name)
+	}
+
+	err = r.secrets.maskError(err)
+
+	switch {
+	case err != nil && r.ctx.Err() != nil:
+		currentJob.Status = JobStatusCancelled
+		currentJob.Err = err
+	case err != nil:
+		currentJob.Status = JobStatusFailed
+		currentJob.Err = err
+	case currentJob.Cached:
+		currentJob.Status = JobStatusCached
+	case currentJob.CacheHit:
+		currentJob.Status = JobStatusCacheHit
+	default:
+		currentJob.Status = JobStatusSuccess
+	}
+
+	if err != nil {
+		flushJobOutput(currentJob.Output)
+	}
+
+	r.notifyJob(currentJob)
+}
+
+func (r *Runner) executeJob(currentJob *Job) error {
+	if currentJob.Uses != "" {
+		startedAt := time.Now()
+		defer currentJob.Timings.phase(PhaseExec, startedAt)
+
+		return r.runPlugin(currentJob)
+	}
+
+	fingerprint := ""
+
+	if currentJob.SkipIfUnchanged != nil {
+		currentPath, _ := projectDir()
+
+		var err error
+		fingerprint, err = inputFingerprint(currentJob, currentPath)
+
+		if err != nil {
+			return err
+		}
+
+		if fingerprint == lastFingerprint(currentJob.Name) {
+			currentJob.Cached = true
+
+			color.Set(color.FgGreen)
+			currentJob.InfoLog.Println("Job skipped, inputs unchanged (cached)")
+			color.Unset()
+
+			return nil
 		}
 	}
 
-	isImageAvailable, err := currentJob.ImageManager.CheckTheImageAvailable(r.ctx, currentJob.Image)
+	imageStartedAt := time.Now()
+	err := r.prepareImage(currentJob)
+
+	if currentJob.Dockerfile != "" {
+		currentJob.Timings.phase(PhaseBuild, imageStartedAt)
+	} else {
+		currentJob.Timings.phase(PhasePull, imageStartedAt)
+	}
 
 	if err != nil {
-		currentJob.ErrorChannel <- err
-		return
+		return err
 	}
 
-	if !isImageAvailable {
-		if err := currentJob.ImageManager.PullImage(r.ctx, currentJob.Image); err != nil {
-			currentJob.ErrorChannel <- err
-			return
+	resultKey := ""
+
+	if currentJob.ResultCache {
+		var err error
+		resultKey, err = r.resultCacheKey(currentJob)
+
+		if err != nil {
+			return err
+		}
+
+		if hasResult(currentJob.Name, resultKey) {
+			currentJob.CacheHit = true
+
+			color.Set(color.FgGreen)
+			currentJob.InfoLog.Println("Job skipped, an identical job succeeded before (cache hit)")
+			color.Unset()
+
+			return nil
 		}
 	}
 
-	ports := map[string]string{}
+	if len(currentJob.Services) > 0 {
+		defer r.stopServices(currentJob)
 
-	for _, port := range currentJob.Port {
-		ports[port.Out] = port.In
+		if err := r.startServices(currentJob); err != nil {
+			return err
+		}
+	}
+
+	if err := r.startJobContainer(currentJob); err != nil {
+		return err
+	}
+
+	if currentJob.Cache != nil {
+		if err := r.restoreCache(*currentJob); err != nil {
+			return r.removeFailedContainer(*currentJob, err)
+		}
+	}
+
+	samplingCtx, stopSampling := context.WithCancel(r.ctx)
+	usageChannel := make(chan interfaces.ResourceUsage, 1)
+
+	go func() {
+		usage, _ := currentJob.ContainerManager.SampleResourceUsage(samplingCtx, currentJob.Container.ID)
+		usageChannel <- usage
+	}()
+
+	if currentJob.Reports != nil {
+		currentJob.TestReport = &TestReport{}
 	}
 
-	resp, err := currentJob.ContainerManager.StartContainer(r.ctx, currentJob.Name, currentJob.Image, ports)
+	execStartedAt := time.Now()
+	err = r.commandScriptExecutor((*currentJob))
+	currentJob.Timings.phase(PhaseExec, execStartedAt)
+
+	stopSampling()
+	currentJob.ResourceUsage = <-usageChannel
+	r.logResourceUsage(currentJob)
 
 	if err != nil {
-		currentJob.ErrorChannel <- err
-		return
+		return err
 	}
 
-	currentJob.Container = resp
+	r.collectReports(*currentJob)
 
-	if currentJob.CopyFiles {
-		if err := currentJob.ContainerManager.CopyToContainer(r.ctx, resp.ID, currentJob.WorkDir, currentJob.CopyIgnore); err != nil {
-			currentJob.ErrorChannel <- err
-			return
+	if err := r.checkExpects(currentJob); err != nil {
+		return r.removeFailedContainer(*currentJob, err)
+	}
+
+	if currentJob.Cache != nil {
+		if err := r.saveCache(*currentJob); err != nil {
+			return r.removeFailedContainer(*currentJob, err)
 		}
 	}
 
-	color.Set(color.FgGreen)
-	currentJob.InfoLog.Println("Starting the container")
-	color.Unset()
+	if currentJob.Artifacts != nil {
+		artifactsStartedAt := time.Now()
+		err := r.collectArtifacts(currentJob)
+		currentJob.Timings.phase(PhaseArtifacts, artifactsStartedAt)
 
-	if err := r.cli.ContainerStart(r.ctx, currentJob.Container.ID, types.ContainerStartOptions{}); err != nil {
-		currentJob.ErrorChannel <- err
-		return
+		if err != nil {
+			return r.removeFailedContainer(*currentJob, err)
+		}
 	}
 
-	if err := r.commandScriptExecutor((*currentJob)); err != nil {
-		currentJob.ErrorChannel <- err
-		return
+	if currentJob.Detach {
+		color.Set(color.FgGreen)
+		currentJob.InfoLog.Printf("Container left running, stop it with pin down %s", r.runID)
+		color.Unset()
+	} else {
+		if err := currentJob.ContainerManager.StopContainer(r.ctx, currentJob.Container.ID, currentJob.StopGracePeriod); err != nil {
+			return err
+		}
+
+		if err := currentJob.ContainerManager.RemoveContainer(r.ctx, currentJob.Container.ID, false); err != nil {
+			return err
+		}
 	}
 
-	if err := currentJob.ContainerManager.StopContainer(r.ctx, currentJob.Container.ID); err != nil {
-		currentJob.ErrorChannel <- err
-		return
+	if fingerprint != "" {
+		if err := saveFingerprint(currentJob.Name, fingerprint); err != nil {
+			return err
+		}
 	}
 
-	if err := currentJob.ContainerManager.RemoveContainer(r.ctx, currentJob.Container.ID, false); err != nil {
-		currentJob.ErrorChannel <- err
-		return
+	if resultKey != "" {
+		if err := saveResult(currentJob.Name, resultKey); err != nil {
+			return err
+		}
 	}
 
 	color.Set(color.FgGreen)
 	currentJob.InfoLog.Println("Job ended")
 	color.Unset()
 
-	currentJob.ErrorChannel <- nil
+	return nil
 }
 
-func (r Runner) commandScriptExecutor(currentJob Job) error {
-	cmds := currentJob.ShellCommander.PrepareShellCommands(currentJob.SoloExecution, currentJob.Script)
+// startJobContainer creates and starts the job container with its ports,
+// env and volumes, copies the project files into it and waits for its
+// healthcheck.
+func (r Runner) startJobContainer(currentJob *Job) error {
+	ports := map[string]string{}
 
-	for _, cmd := range cmds {
-		buf, err := currentJob.ShellCommander.ShellToTar(cmd)
+	for _, port := range currentJob.Port {
+		ports[port.Out] = port.In
+	}
+
+	volumes := currentJob.Volumes
+	deltaCopy := currentJob.CopyFiles && currentJob.CopyStrategy == CopyStrategyDelta
+
+	if deltaCopy {
+		volume, err := workspaceVolume(currentJob.Name)
 
 		if err != nil {
 			return err
 		}
 
-		err = r.cli.CopyToContainer(r.ctx, currentJob.Container.ID, "/home/", buf, types.CopyToContainerOptions{})
+		volumes = append(append([]string{}, volumes...), volume+":"+workspaceMount)
+	}
+
+	if currentJob.MountFiles {
+		mount, err := projectMount(currentJob)
 
 		if err != nil {
 			return err
 		}
 
-		if err := r.internalExec("chmod +x /home/shell_command.sh", currentJob); err != nil {
+		volumes = append(append([]string{}, volumes...), mount)
+
+		currentJob.InfoLog.Printf("Project directory mounted: %s", mount)
+	}
+
+	resp, err := currentJob.ContainerManager.StartContainer(r.ctx, interfaces.ContainerOptions{
+		Name:        currentJob.Name,
+		Image:       currentJob.Image,
+		Ports:       ports,
+		Env:         mergeEnv(jobEnv(currentJob), r.metadataEnv(currentJob, currentJob.WorkDir)),
+		Volumes:     volumes,
+		Privileged:  currentJob.Privileged,
+		CapAdd:      currentJob.CapAdd,
+		CapDrop:     currentJob.CapDrop,
+		ExtraHosts:  currentJob.ExtraHosts,
+		DNS:         currentJob.DNS,
+		DNSSearch:   currentJob.DNSSearch,
+		Hostname:    currentJob.Hostname,
+		Network:     currentJob.Network,
+		Labels:      r.containerLabels(currentJob),
+		Healthcheck: currentJob.Healthcheck,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	currentJob.Container = resp
+
+	r.snapshotJobEnvironment(currentJob)
+
+	// the container is removed when it can not be made ready, its service
+	// network can not be removed while it is attached
+	if err := r.readyJobContainer(currentJob, deltaCopy); err != nil {
+		return r.removeFailedContainer(*currentJob, err)
+	}
+
+	return nil
+}
+
+// readyJobContainer copies the project files into the created container,
+// starts it and waits for its healthcheck.
+func (r Runner) readyJobContainer(currentJob *Job, deltaCopy bool) error {
+	if currentJob.CopyFiles && !deltaCopy {
+		copyStartedAt := time.Now()
+		err := currentJob.ContainerManager.CopyToContainer(r.ctx, currentJob.Container.ID, currentJob.WorkDir, currentJob.CopyIgnore, currentJob.CopyInclude, currentJob.Compression)
+		currentJob.Timings.phase(PhaseCopy, copyStartedAt)
+
+		if err != nil {
 			return err
 		}
+	}
 
-		if err := r.commandRunner("sh /home/shell_command.sh", cmd, currentJob); err != nil {
+	color.Set(color.FgGreen)
+	currentJob.InfoLog.Println("Starting the container")
+	color.Unset()
+
+	if err := r.cli.ContainerStart(r.ctx, currentJob.Container.ID, types.ContainerStartOptions{}); err != nil {
+		return err
+	}
+
+	if deltaCopy {
+		copyStartedAt := time.Now()
+		err := r.syncWorkspace(currentJob)
+		currentJob.Timings.phase(PhaseCopy, copyStartedAt)
+
+		if err != nil {
 			return err
 		}
+	}
 
-		if err := r.internalExec("rm /home/shell_command.sh", currentJob); err != nil {
+	return r.waitForJobHealthcheck(currentJob)
+}
+
+// prepareImage builds the job image from its dockerfile or pulls it when it
+// is not available locally.
+func (r Runner) prepareImage(currentJob *Job) error {
+	if currentJob.Dockerfile != "" {
+		// the directory of the pipeline file is the build context, an empty
+		// configDir is the current directory
+		contextDir, err := filepath.Abs(r.configDir)
+
+		if err != nil {
+			return err
+		}
+
+		dockerfile, err := contextPath(contextDir, currentJob.Dockerfile)
+
+		if err != nil {
+			return err
+		}
+
+		return currentJob.ImageManager.BuildImage(r.ctx, interfaces.BuildOptions{
+			ContextDir: contextDir,
+			Dockerfile: dockerfile,
+			Tag:        currentJob.Image,
+			BuildArgs:  currentJob.BuildArgs,
+			Target:     currentJob.Target,
+			Labels:     r.labels(currentJob),
+		})
+	}
+
+	isImageAvailable, err := currentJob.ImageManager.CheckTheImageAvailable(r.ctx, currentJob.Image)
+
+	if err != nil {
+		return err
+	}
+
+	if isImageAvailable {
+		return nil
+	}
+
+	pullErr := currentJob.ImageManager.PullImage(r.ctx, currentJob.Image)
+
+	if pullErr == nil {
+		return nil
+	}
+
+	// the fallbacks are tried in order, the job runs with the first one that
+	// is available locally or can be pulled
+	for _, fallback := range currentJob.ImageFallbacks {
+		color.Set(color.FgYellow)
+		currentJob.InfoLog.Printf("Pulling %s failed: %s, trying %s", currentJob.Image, pullErr, fallback)
+		color.Unset()
+
+		isImageAvailable, err := currentJob.ImageManager.CheckTheImageAvailable(r.ctx, fallback)
+
+		if err != nil {
+			return err
+		}
+
+		if !isImageAvailable {
+			pullErr = currentJob.ImageManager.PullImage(r.ctx, fallback)
+		}
+
+		if isImageAvailable || pullErr == nil {
+			color.Set(color.FgGreen)
+			currentJob.InfoLog.Printf("Using fallback image %s", fallback)
+			color.Unset()
+
+			currentJob.PrimaryImage = currentJob.Image
+			currentJob.Image = fallback
+
+			return nil
+		}
+	}
+
+	return pullErr
+}
+
+func (r Runner) commandScriptExecutor(currentJob Job) error {
+	if currentJob.SessionMode == SessionModeShared {
+		return r.sessionScriptExecutor(currentJob)
+	}
+
+	cmds := currentJob.ShellCommander.PrepareShellCommands(currentJob.SoloExecution, currentJob.Script)
+
+	for i, cmd := range cmds {
+		startedAt := time.Now()
+		err := r.shellCommandExecutor(cmd, currentJob)
+
+		// every script entry is a step of its own only with soloExecution
+		if currentJob.SoloExecution {
+			currentJob.Timings.step(currentJob.Script[i], startedAt)
+		}
+
+		if err != nil {
 			return err
 		}
 	}
@@ -168,6 +560,30 @@ func (r Runner) commandScriptExecutor(currentJob Job) error {
 	return nil
 }
 
+func (r Runner) shellCommandExecutor(cmd string, currentJob Job) error {
+	buf, err := currentJob.ShellCommander.ShellToTar(cmd)
+
+	if err != nil {
+		return err
+	}
+
+	err = r.cli.CopyToContainer(r.ctx, currentJob.Container.ID, "/home/", buf, types.CopyToContainerOptions{})
+
+	if err != nil {
+		return err
+	}
+
+	if err := r.internalExec("chmod +x /home/shell_command.sh", currentJob); err != nil {
+		return err
+	}
+
+	if err := r.commandRunner("sh /home/shell_command.sh", cmd, currentJob); err != nil {
+		return err
+	}
+
+	return r.internalExec("rm /home/shell_command.sh", currentJob)
+}
+
 func (r Runner) commandRunner(command string, name string, currentJob Job) error {
 	args := strings.Split(command, " ")
 
@@ -190,61 +606,107 @@ func (r Runner) commandRunner(command string, name string, currentJob Job) error
 		return err
 	}
 
-	res, err := r.cli.ContainerExecAttach(r.ctx, exec.ID, types.ExecStartCheck{Tty: true})
+	// a tty would echo the input into the output and never pass on the end
+	// of it, so an exec with stdin is started without one and its stream is
+	// multiplexed
+	tty := currentJob.Stdin == nil
+
+	res, err := r.cli.ContainerExecAttach(r.ctx, exec.ID, types.ExecStartCheck{Tty: tty})
 	if err != nil {
 		return err
 	}
 
-	io.Copy(os.Stdout, res.Reader)
+	defer res.Close()
+
+	if !tty {
+		go func() {
+			io.WriteString(res.Conn, *currentJob.Stdin)
+			res.CloseWrite()
+		}()
+	}
+
+	// the script output is streamed while it runs, line by line with the
+	// job prefix
+	if tty {
+		io.Copy(jobWriter(currentJob), res.Reader)
+	} else {
+		stdcopy.StdCopy(jobWriter(currentJob), jobWriter(currentJob), res.Reader)
+	}
+
+	if currentJob.ScriptOutput != nil {
+		currentJob.ScriptOutput.Flush()
+	}
 
 	status, err := r.cli.ContainerExecInspect(r.ctx, exec.ID)
 	if err != nil {
 		return err
 	}
 
+	status.ExitCode = r.injectFailure(currentJob, command, status.ExitCode)
+
 	if status.ExitCode != 0 {
 		color.Set(color.FgRed)
-		currentJob.InfoLog.Printf("Command execution failed")
+		currentJob.InfoLog.Printf("Command execution failed with exit code %d", status.ExitCode)
+		color.Unset()
 
-		currentJob.InfoLog.Println("Command Log:")
+		return r.removeFailedContainer(currentJob, &commandError{exitCode: status.ExitCode})
+	}
 
-		if reader, _, err := r.cli.CopyFromContainer(r.ctx, currentJob.Container.ID, "/shell_command_output.log"); err == nil {
-			tr := tar.NewReader(reader)
-			tr.Next()
-			b, _ := io.ReadAll(tr)
-			fmt.Println("\n" + string(b))
-		}
-		color.Unset()
+	currentJob.InfoLog.Println("Command execution successful")
 
-		r.cli.ContainerKill(r.ctx, currentJob.Container.ID, "KILL")
+	return nil
+}
 
-		if err := currentJob.ContainerManager.StopContainer(r.ctx, currentJob.Container.ID); err != nil {
-			return err
-		}
+// projectMount bind mounts the directory pin runs in on the work directory,
+// instead of copying the project files.
+func projectMount(currentJob *Job) (string, error) {
+	currentPath, err := projectDir()
 
-		if err := currentJob.ContainerManager.RemoveContainer(r.ctx, currentJob.Container.ID, false); err != nil {
-			return err
-		}
+	if err != nil {
+		return "", err
+	}
 
-		return errors.New("command execution failed")
+	mount := currentPath + ":" + currentJob.WorkDir
+
+	if currentJob.MountReadOnly {
+		mount += ":ro"
 	}
 
-	currentJob.InfoLog.Println("Command execution successful")
+	return mount, nil
+}
 
-	if reader, _, err := r.cli.CopyFromContainer(r.ctx, currentJob.Container.ID, "/shell_command_output.log"); err == nil {
-		tr := tar.NewReader(reader)
-		tr.Next()
-		b, _ := io.ReadAll(tr)
+// commandError is the failure of a script command, exitCode is -1 when the
+// shell ended before reporting it.
+type commandError struct {
+	exitCode int
+}
 
-		if len(b) != 0 {
-			color.Set(color.FgGreen)
-			currentJob.InfoLog.Println("Command Log:")
-			fmt.Println("\n" + string(b))
-			color.Unset()
-		}
+func (e *commandError) Error() string {
+	return "command execution failed"
+}
+
+// removeFailedContainer tears the job container down after a failed command
+// and returns failure, with debugOnFailure the container can be kept.
+func (r Runner) removeFailedContainer(currentJob Job, failure error) error {
+	r.collectReports(currentJob)
+
+	if r.debugOnFailure && r.debugFailedContainer(currentJob) {
+		return failure
 	}
 
-	return nil
+	if currentJob.StopGracePeriod == nil {
+		r.cli.ContainerKill(r.ctx, currentJob.Container.ID, "KILL")
+	}
+
+	if err := currentJob.ContainerManager.StopContainer(r.ctx, currentJob.Container.ID, currentJob.StopGracePeriod); err != nil {
+		return err
+	}
+
+	if err := currentJob.ContainerManager.RemoveContainer(r.ctx, currentJob.Container.ID, false); err != nil {
+		return err
+	}
+
+	return failure
 }
 
 func (r Runner) internalExec(command string, currentJob Job) error {
@@ -266,7 +728,7 @@ func (r Runner) internalExec(command string, currentJob Job) error {
 		return err
 	}
 
-	io.Copy(os.Stdout, res.Reader)
+	io.Copy(jobWriter(currentJob), res.Reader)
 
 	_, err = r.cli.ContainerExecInspect(r.ctx, exec.ID)
 	if err != nil {
@@ -276,6 +738,85 @@ func (r Runner) internalExec(command string, currentJob Job) error {
 	return nil
 }
 
+// snapshotJobEnvironment records what the job really ran with, errors are
+// ignored because the snapshot must never fail a job.
+func (r Runner) snapshotJobEnvironment(currentJob *Job) {
+	if digest, err := currentJob.ImageManager.ImageDigest(r.ctx, currentJob.Image); err == nil {
+		currentJob.ImageDigest = digest
+	}
+
+	if inspect, err := r.cli.ContainerInspect(r.ctx, currentJob.Container.ID); err == nil && inspect.Config != nil {
+		currentJob.ContainerEnv = inspect.Config.Env
+	}
+}
+
+func (r Runner) collectArtifacts(currentJob *Job) error {
+	destination := artifactsDestination(currentJob)
+
+	files, err := currentJob.ContainerManager.CopyFromContainer(r.ctx, currentJob.Container.ID, currentJob.WorkDir, currentJob.Artifacts.Paths, destination)
+
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		artifact := ArtifactFile{Path: file}
+
+		if info, err := os.Stat(filepath.Join(destination, filepath.FromSlash(file))); err == nil {
+			artifact.Size = info.Size()
+		}
+
+		currentJob.ArtifactFiles = append(currentJob.ArtifactFiles, artifact)
+	}
+
+	color.Set(color.FgGreen)
+	currentJob.InfoLog.Printf("Artifacts copied: %d files to %s", len(files), destination)
+	color.Unset()
+
+	// the run history copy is only for pin artifacts get, the job does not
+	// fail without it
+	if err := storeRunArtifacts(r.runID, currentJob.Name, destination, files); err != nil {
+		color.Set(color.FgYellow)
+		currentJob.InfoLog.Printf("warning: artifacts could not be stored with the run: %s", err)
+		color.Unset()
+	}
+
+	return nil
+}
+
+func (r Runner) logResourceUsage(currentJob *Job) {
+	usage := currentJob.ResourceUsage
+
+	if usage.Samples == 0 {
+		return
+	}
+
+	currentJob.InfoLog.Printf(
+		"Resource usage: peak cpu %.1f%%, peak memory %s, network rx %s / tx %s",
+		usage.PeakCPUPercent,
+		formatBytes(usage.PeakMemoryBytes),
+		formatBytes(usage.NetworkRxBytes),
+		formatBytes(usage.NetworkTxBytes),
+	)
+}
+
+func formatBytes(b uint64) string {
+	const unit = 1024
+
+	if b < unit {
+		return fmt.Sprintf("%dB", b)
+	}
+
+	div, exp := uint64(unit), 0
+
+	for n := b / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f%ciB", float64(b)/float64(div), "KMGTPE"[exp])
+}
+
 func (r *Runner) createGlobalContext(jobs []*Job) {
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 
@@ -287,15 +828,42 @@ func (r *Runner) createGlobalContext(jobs []*Job) {
 		cancel()
 
 		for _, job := range jobs {
-			if job.Container.ID == "" {
-				continue
-			}
-
-			timedContext, timedCancel := context.WithTimeout(context.Background(), time.Second*3)
-			defer timedCancel()
-			job.ContainerManager.RemoveContainer(timedContext, job.Container.ID, true)
+			removeJobContainers(job)
 		}
 	}()
 
 	r.ctx = ctx
 }
+
+// removeJobContainers removes the containers of a cancelled job and of its
+// services, the job itself can not clean up with its cancelled context.
+func removeJobContainers(job *Job) {
+	for _, service := range job.Services {
+		removeContainerOnCancel(job, service.Container.ID)
+	}
+
+	removeContainerOnCancel(job, job.Container.ID)
+}
+
+// removeContainerOnCancel gives the container its stop grace period before
+// the forced removal, so servers can shut down cleanly on interrupts.
+func removeContainerOnCancel(job *Job, containerID string) {
+	if containerID == "" {
+		return
+	}
+
+	timeout := time.Second * 3
+
+	if job.StopGracePeriod != nil {
+		timeout += *job.StopGracePeriod
+	}
+
+	timedContext, timedCancel := context.WithTimeout(context.Background(), timeout)
+	defer timedCancel()
+
+	if job.StopGracePeriod != nil {
+		job.ContainerManager.StopContainer(timedContext, containerID, job.StopGracePeriod)
+	}
+
+	job.ContainerManager.RemoveContainer(timedContext, containerID, true)
+}
@@ -0,0 +1,175 @@
+package runner
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/golang/mock/gomock"
+	"github.com/muhammedikinci/pin/internal/interfaces"
+	"github.com/muhammedikinci/pin/internal/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrepareImageTriesFallbacksInOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	mockImageManager := mocks.NewMockImageManager(ctrl)
+
+	gomock.InOrder(
+		mockImageManager.EXPECT().CheckTheImageAvailable(gomock.Any(), "mirror.example.com/golang:1").Return(false, nil),
+		mockImageManager.EXPECT().PullImage(gomock.Any(), "mirror.example.com/golang:1").Return(errors.New("connection refused")),
+		mockImageManager.EXPECT().CheckTheImageAvailable(gomock.Any(), "registry.internal/golang:1").Return(false, nil),
+		mockImageManager.EXPECT().PullImage(gomock.Any(), "registry.internal/golang:1").Return(errors.New("unauthorized")),
+		mockImageManager.EXPECT().CheckTheImageAvailable(gomock.Any(), "golang:1").Return(false, nil),
+		mockImageManager.EXPECT().PullImage(gomock.Any(), "golang:1").Return(nil),
+	)
+
+	job := &Job{
+		Image:          "mirror.example.com/golang:1",
+		ImageFallbacks: []string{"registry.internal/golang:1", "golang:1"},
+		ImageManager:   mockImageManager,
+		InfoLog:        log.New(io.Discard, "", 0),
+	}
+
+	r := Runner{ctx: context.Background()}
+
+	assert.Equal(t, nil, r.prepareImage(job))
+	assert.Equal(t, "golang:1", job.Image)
+	assert.Equal(t, "mirror.example.com/golang:1", job.PrimaryImage)
+	assert.Equal(t, "mirror.example.com/golang:1", newJobSnapshot(job).FallbackFor)
+}
+
+func TestPrepareImageBuildsInThePipelineDirectory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	dir := t.TempDir()
+	mockImageManager := mocks.NewMockImageManager(ctrl)
+
+	mockImageManager.EXPECT().BuildImage(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, options interfaces.BuildOptions) error {
+		assert.Equal(t, dir, options.ContextDir)
+		assert.Equal(t, filepath.Join("build", "Dockerfile"), options.Dockerfile)
+
+		return nil
+	})
+
+	job := &Job{
+		Name:         "build",
+		Image:        "build-custom:latest",
+		Dockerfile:   filepath.Join(dir, "build", "Dockerfile"),
+		ImageManager: mockImageManager,
+		InfoLog:      log.New(io.Discard, "", 0),
+	}
+
+	r := Runner{ctx: context.Background(), configDir: dir}
+
+	assert.Equal(t, nil, r.prepareImage(job))
+}
+
+func TestPrepareImageReturnsLastPullError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	mockImageManager := mocks.NewMockImageManager(ctrl)
+
+	mockImageManager.EXPECT().CheckTheImageAvailable(gomock.Any(), gomock.Any()).Return(false, nil).Times(2)
+	mockImageManager.EXPECT().PullImage(gomock.Any(), "primary:1").Return(errors.New("connection refused"))
+	mockImageManager.EXPECT().PullImage(gomock.Any(), "fallback:1").Return(errors.New("not found"))
+
+	job := &Job{
+		Image:          "primary:1",
+		ImageFallbacks: []string{"fallback:1"},
+		ImageManager:   mockImageManager,
+		InfoLog:        log.New(io.Discard, "", 0),
+	}
+
+	r := Runner{ctx: context.Background()}
+
+	assert.EqualError(t, r.prepareImage(job), "not found")
+	assert.Equal(t, "primary:1", job.Image)
+}
+
+func TestLabelsIdentifyTheRunResources(t *testing.T) {
+	r := Runner{runID: "20220101-000000-000000", pipelineName: "deploy"}
+
+	labels := r.serviceLabels(&Job{Name: "test"}, "postgres")
+
+	assert.Equal(t, map[string]string{
+		"pin.managed":  "true",
+		"pin.pipeline": "deploy",
+		"pin.job":      "test",
+		"pin.run_id":   "20220101-000000-000000",
+		"pin.service":  "postgres",
+	}, labels)
+}
+
+func TestCommandRunnerClosesStdinWithoutEchoingIt(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	defer listener.Close()
+
+	// the fake exec reads its stdin to the end and reports how much it got,
+	// like cat > file it only finishes when the input is closed
+	go func() {
+		conn, err := listener.Accept()
+
+		if err != nil {
+			return
+		}
+
+		defer conn.Close()
+
+		input, _ := io.ReadAll(conn)
+		fmt.Fprintf(stdcopy.NewStdWriter(conn, stdcopy.Stdout), "read %d bytes\n", len(input))
+	}()
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	assert.NoError(t, err)
+
+	mockCli := mocks.NewMockClient(ctrl)
+
+	mockCli.EXPECT().ContainerExecCreate(gomock.Any(), "abc", gomock.Any()).DoAndReturn(func(ctx context.Context, container string, config types.ExecConfig) (types.IDResponse, error) {
+		assert.False(t, config.Tty)
+
+		return types.IDResponse{ID: "exec"}, nil
+	})
+	mockCli.EXPECT().ContainerExecAttach(gomock.Any(), "exec", types.ExecStartCheck{Tty: false}).
+		Return(types.HijackedResponse{Conn: conn, Reader: bufio.NewReader(conn)}, nil)
+	mockCli.EXPECT().ContainerExecInspect(gomock.Any(), "exec").Return(types.ContainerExecInspect{ExitCode: 0}, nil)
+
+	var output bytes.Buffer
+
+	stdin := "region=eu-west-1\ntoken=s3cr3t\n"
+
+	job := Job{
+		Stdin:        &stdin,
+		InfoLog:      log.New(io.Discard, "", 0),
+		ScriptOutput: newLineWriter(&output, "* configure "),
+	}
+	job.Container.ID = "abc"
+
+	r := Runner{ctx: context.Background(), cli: mockCli}
+
+	assert.NoError(t, r.commandRunner("sh /home/shell_command.sh", "", job))
+	assert.Equal(t, "* configure read 30 bytes\n", output.String())
+	assert.NotContains(t, output.String(), "s3cr3t")
+}
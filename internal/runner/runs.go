@@ -0,0 +1,228 @@
+package runner
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Version is the pin version recorded in run metadata, it is set by the cli.
+var Version = "dev"
+
+const (
+	JobStatusSuccess = "success"
+	JobStatusFailed  = "failed"
+	JobStatusSkipped = "skipped"
+	JobStatusCached  = "cached"
+	// JobStatusCacheHit is a job skipped by resultCache.
+	JobStatusCacheHit = "cache hit"
+)
+
+type RunMetadata struct {
+	ID            string `json:"id"`
+	Pipeline      string `json:"pipeline"`
+	ConfigPath    string `json:"configPath"`
+	ProjectPath   string `json:"projectPath"`
+	PinVersion    string `json:"pinVersion"`
+	DockerVersion string `json:"dockerVersion"`
+	Status        string `json:"status"`
+	RerunOf       string `json:"rerunOf,omitempty"`
+	// Attempt counts the run and the runs it reran, see runAttempt.
+	Attempt     int                `json:"attempt,omitempty"`
+	StartedAt   time.Time          `json:"startedAt"`
+	FinishedAt  time.Time          `json:"finishedAt"`
+	Jobs        []JobSnapshot      `json:"jobs"`
+	Hooks       []HookResult       `json:"hooks,omitempty"`
+	Healthcheck *HealthcheckResult `json:"healthcheck,omitempty"`
+}
+
+type JobSnapshot struct {
+	Name              string            `json:"name"`
+	Image             string            `json:"image"`
+	FallbackFor       string            `json:"fallbackFor,omitempty"`
+	ImageDigest       string            `json:"imageDigest,omitempty"`
+	Env               []string          `json:"env"`
+	Status            string            `json:"status"`
+	Tolerated         bool              `json:"tolerated,omitempty"`
+	Error             string            `json:"error,omitempty"`
+	StartedAt         time.Time         `json:"startedAt"`
+	FinishedAt        time.Time         `json:"finishedAt"`
+	Artifacts         []ArtifactFile    `json:"artifacts,omitempty"`
+	Outputs           map[string]string `json:"outputs,omitempty"`
+	DetachedContainer string            `json:"detachedContainer,omitempty"`
+	TestReport        *TestReport       `json:"testReport,omitempty"`
+	Timings           *JobTimings       `json:"timings,omitempty"`
+}
+
+var sensitiveEnvPattern = regexp.MustCompile(`(?i)(SECRET|TOKEN|PASSWORD|PASSWD|PASS|API_?KEY|PRIVATE_?KEY|CREDENTIAL|AUTH)`)
+
+func newRunID() string {
+	b := make([]byte, 3)
+	rand.Read(b)
+
+	return time.Now().Format("20060102-150405") + "-" + hex.EncodeToString(b)
+}
+
+func runsDir() (string, error) {
+	base, err := stateDir()
+
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(base, "runs"), nil
+}
+
+func runDir(id string) (string, error) {
+	dir, err := runsDir()
+
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, filepath.Base(id)), nil
+}
+
+// maskEnv hides the values of variables whose names look like secrets.
+func maskEnv(env []string) []string {
+	masked := make([]string, len(env))
+
+	for i, v := range env {
+		name, _, found := strings.Cut(v, "=")
+
+		if found && sensitiveEnvPattern.MatchString(name) {
+			masked[i] = name + "=***"
+			continue
+		}
+
+		masked[i] = v
+	}
+
+	return masked
+}
+
+func newRunMetadata(id, name, configPath string, pipeline Pipeline) RunMetadata {
+	projectPath, _ := os.Getwd()
+	absConfigPath, _ := filepath.Abs(configPath)
+
+	if configPath == stdinConfigPath {
+		absConfigPath = configPath
+	}
+
+	run := RunMetadata{
+		ID:          id,
+		Pipeline:    name,
+		ConfigPath:  absConfigPath,
+		ProjectPath: projectPath,
+		PinVersion:  Version,
+		Status:      JobStatusSuccess,
+		Jobs:        []JobSnapshot{},
+	}
+
+	for _, job := range pipeline.Workflow {
+		if job.Status == JobStatusFailed && !job.Tolerated {
+			run.Status = JobStatusFailed
+		}
+
+		if run.StartedAt.IsZero() || (!job.StartedAt.IsZero() && job.StartedAt.Before(run.StartedAt)) {
+			run.StartedAt = job.StartedAt
+		}
+
+		if job.FinishedAt.After(run.FinishedAt) {
+			run.FinishedAt = job.FinishedAt
+		}
+
+		run.Jobs = append(run.Jobs, newJobSnapshot(job))
+	}
+
+	return run
+}
+
+func newJobSnapshot(job *Job) JobSnapshot {
+	snapshot := JobSnapshot{
+		Name:        job.Name,
+		Image:       job.Image,
+		FallbackFor: job.PrimaryImage,
+		ImageDigest: job.ImageDigest,
+		Env:         maskEnv(job.ContainerEnv),
+		Status:      job.Status,
+		Tolerated:   job.Tolerated,
+		StartedAt:   job.StartedAt,
+		FinishedAt:  job.FinishedAt,
+		Artifacts:   job.ArtifactFiles,
+		Outputs:     job.Outputs,
+		TestReport:  job.TestReport,
+		Timings:     recordedTimings(job),
+	}
+
+	if job.Err != nil {
+		snapshot.Error = job.Err.Error()
+	}
+
+	if job.Detach && job.Status == JobStatusSuccess {
+		snapshot.DetachedContainer = job.Container.ID
+	}
+
+	return snapshot
+}
+
+// saveRun writes the metadata and a copy of the pipeline configuration, so
+// the run can be reproduced later.
+func saveRun(run RunMetadata, config []byte) error {
+	if err := writeRunMetadata(run); err != nil {
+		return err
+	}
+
+	dir, err := runDir(run.ID)
+
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(dir, "pipeline.yaml"), config, 0644)
+}
+
+func writeRunMetadata(run RunMetadata) error {
+	dir, err := runDir(run.ID)
+
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	b, err := json.MarshalIndent(run, "", "  ")
+
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(dir, "run.json"), b, 0644)
+}
+
+func loadRun(id string) (RunMetadata, error) {
+	run := RunMetadata{}
+
+	dir, err := runDir(id)
+
+	if err != nil {
+		return run, err
+	}
+
+	b, err := os.ReadFile(filepath.Join(dir, "run.json"))
+
+	if err != nil {
+		return run, err
+	}
+
+	err = json.Unmarshal(b, &run)
+
+	return run, err
+}
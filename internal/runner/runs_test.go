@@ -0,0 +1,48 @@
+package runner
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaskEnvHidesSensitiveValues(t *testing.T) {
+	env := maskEnv([]string{"PATH=/usr/bin", "GITHUB_TOKEN=abc", "DB_PASSWORD=pin", "AWS_SECRET_ACCESS_KEY=x", "EMPTY"})
+
+	assert.Equal(t, []string{"PATH=/usr/bin", "GITHUB_TOKEN=***", "DB_PASSWORD=***", "AWS_SECRET_ACCESS_KEY=***", "EMPTY"}, env)
+}
+
+func TestNewRunMetadataCollectsJobSnapshots(t *testing.T) {
+	start := time.Now()
+
+	pipeline := Pipeline{
+		Workflow: []*Job{
+			{Name: "build", Image: "golang:alpine3.15", ImageDigest: "golang@sha256:1", Status: JobStatusSuccess, StartedAt: start, FinishedAt: start.Add(time.Second)},
+			{Name: "test", Image: "golang:alpine3.15", Status: JobStatusFailed, Err: errors.New("command execution failed"), StartedAt: start.Add(time.Second), FinishedAt: start.Add(2 * time.Second)},
+		},
+	}
+
+	run := newRunMetadata("id", "pipeline", "pipeline.yaml", pipeline)
+
+	assert.Equal(t, JobStatusFailed, run.Status)
+	assert.Equal(t, start, run.StartedAt)
+	assert.Equal(t, start.Add(2*time.Second), run.FinishedAt)
+	assert.Equal(t, "golang@sha256:1", run.Jobs[0].ImageDigest)
+	assert.Equal(t, "command execution failed", run.Jobs[1].Error)
+}
+
+func TestSaveRunAndLoadRun(t *testing.T) {
+	t.Setenv("PIN_HOME", t.TempDir())
+
+	run := RunMetadata{ID: newRunID(), Pipeline: "test", Status: JobStatusSuccess, Jobs: []JobSnapshot{{Name: "build"}}}
+
+	assert.Equal(t, nil, saveRun(run, []byte("workflow: []")))
+
+	loaded, err := loadRun(run.ID)
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, run.Pipeline, loaded.Pipeline)
+	assert.Equal(t, run.Jobs[0].Name, loaded.Jobs[0].Name)
+}
@@ -0,0 +1,164 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/fatih/color"
+)
+
+// getMaxParallel parses the number of jobs that can run at the same time.
+func getMaxParallel(maxParallel interface{}) (int, error) {
+	if maxParallel == nil {
+		return 0, nil
+	}
+
+	n, ok := maxParallel.(int)
+
+	if !ok || n < 1 {
+		return 0, fmt.Errorf("invalid maxParallel: %v, use a positive number", maxParallel)
+	}
+
+	return n, nil
+}
+
+// schedule runs the jobs of the execution graph built by linkJobs. A job is
+// ready once every job it needs finished, ready jobs are dispatched in
+// workflow order while fewer than maxParallel jobs run, 0 does not limit
+// them. Jobs report back on a single channel so no job waits on another one
+// itself. With failFast the first failure cancels the running jobs and the
+// jobs that did not start are skipped.
+func (r *Runner) schedule(pipeline Pipeline) {
+	jobs := pipeline.Workflow
+
+	ctx, abort := context.WithCancel(r.ctx)
+	defer abort()
+
+	jobsRunner := *r
+	jobsRunner.ctx = ctx
+
+	waiting := map[*Job]int{}
+	dependents := map[*Job][]*Job{}
+
+	for _, job := range jobs {
+		waiting[job] = len(job.Needs)
+
+		for _, need := range job.Needs {
+			dependents[need] = append(dependents[need], job)
+		}
+	}
+
+	finished := make(chan *Job)
+	ready := []*Job{}
+	running := map[*Job]bool{}
+
+	dispatch := func() {
+		for len(ready) > 0 && (pipeline.MaxParallel == 0 || len(running) < pipeline.MaxParallel) {
+			job := ready[0]
+			ready = ready[1:]
+			running[job] = true
+
+			go func() {
+				jobsRunner.jobRunner(job, pipeline.LogsWithTime)
+				finished <- job
+			}()
+		}
+	}
+
+	for _, job := range jobs {
+		if waiting[job] == 0 {
+			ready = append(ready, job)
+		}
+	}
+
+	dispatch()
+
+	for len(running) > 0 {
+		job := <-finished
+		delete(running, job)
+
+		// a failure a minSuccess group can tolerate does not cancel the run
+		if pipeline.FailFast && job.Status == JobStatusFailed && groupMinSuccess(job.Group) == 0 && ctx.Err() == nil {
+			abort()
+			r.cancelRunning(job, running)
+		}
+
+		for _, dependent := range dependents[job] {
+			if waiting[dependent]--; waiting[dependent] == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+
+		dispatch()
+	}
+}
+
+// cancelRunning removes the containers of the jobs still running when failed
+// failed, their cancelled context ends plugins and pending docker calls.
+func (r Runner) cancelRunning(failed *Job, running map[*Job]bool) {
+	if len(running) == 0 {
+		return
+	}
+
+	color.Set(color.FgRed)
+	r.pipelineLogger().Printf("%s failed, cancelling %d running jobs (failFast)", failed.Name, len(running))
+	color.Unset()
+
+	var wg sync.WaitGroup
+
+	for job := range running {
+		wg.Add(1)
+
+		go func(job *Job) {
+			defer wg.Done()
+			removeJobContainers(job)
+		}(job)
+	}
+
+	wg.Wait()
+}
+
+// runError is the error of the first failed job that is not tolerated, the
+// jobs cancelled after it fail the run only when no job failed.
+func runError(jobs []*Job) error {
+	var cancelled error
+
+	for _, job := range jobs {
+		if job.Err == nil || job.Tolerated {
+			continue
+		}
+
+		if job.Status != JobStatusCancelled {
+			return job.Err
+		}
+
+		if cancelled == nil {
+			cancelled = job.Err
+		}
+	}
+
+	return cancelled
+}
+
+// needsError returns the error that skips a job, the error of a job it
+// needs that failed or was skipped itself. A parallel group with a
+// minSuccess only skips the job when the threshold is not met.
+func needsError(job *Job) error {
+	if len(job.Needs) > 0 && job.Needs[0].IsParallel && groupMinSuccess(job.Needs) != 0 {
+		if ok, _ := groupSucceeded(job.Needs); ok {
+			return nil
+		}
+	}
+
+	for _, need := range job.Needs {
+		switch need.Status {
+		case JobStatusFailed:
+			return need.Err
+		case JobStatusSkipped:
+			return needsError(need)
+		}
+	}
+
+	return nil
+}
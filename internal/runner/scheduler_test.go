@@ -0,0 +1,222 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/muhammedikinci/pin/internal/interfaces"
+	"github.com/stretchr/testify/assert"
+)
+
+func schedulerPlugin(t *testing.T, name, script string) string {
+	path := filepath.Join(t.TempDir(), name)
+	assert.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+script+"\n"), 0755))
+
+	return path
+}
+
+func TestScheduleWaitsForTheWholeParallelGroup(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are shell scripts")
+	}
+
+	defer func(f func() (interfaces.Client, error)) { newDockerClient = f }(newDockerClient)
+
+	newDockerClient = func() (interfaces.Client, error) {
+		t.Fatal("docker client created for a pipeline of plugin jobs")
+		return nil, nil
+	}
+
+	slow := &Job{Name: "slow", Uses: schedulerPlugin(t, "slow", "sleep 0.3"), IsParallel: true}
+	fast := &Job{Name: "fast", Uses: schedulerPlugin(t, "fast", "true"), IsParallel: true}
+	deploy := &Job{Name: "deploy", Uses: schedulerPlugin(t, "deploy", "true")}
+	notify := &Job{Name: "notify", Uses: schedulerPlugin(t, "notify", "true")}
+
+	workflow := []*Job{slow, fast, deploy, notify}
+	linkJobs(workflow)
+
+	r := Runner{hooks: &hookLog{}}
+
+	assert.NoError(t, r.run(Pipeline{Workflow: workflow}))
+
+	for _, job := range workflow {
+		assert.Equal(t, JobStatusSuccess, job.Status, job.Name)
+	}
+
+	assert.False(t, deploy.StartedAt.Before(slow.FinishedAt))
+	assert.False(t, deploy.StartedAt.Before(fast.FinishedAt))
+	assert.False(t, notify.StartedAt.Before(deploy.FinishedAt))
+}
+
+func TestScheduleSkipsTheJobsAfterAFailure(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are shell scripts")
+	}
+
+	defer func(f func() (interfaces.Client, error)) { newDockerClient = f }(newDockerClient)
+
+	newDockerClient = func() (interfaces.Client, error) {
+		t.Fatal("docker client created for a pipeline of plugin jobs")
+		return nil, nil
+	}
+
+	// the failing job is not the last one of the group
+	failing := &Job{Name: "failing", Uses: schedulerPlugin(t, "failing", "exit 1"), IsParallel: true}
+	slow := &Job{Name: "slow", Uses: schedulerPlugin(t, "slow", "sleep 0.2"), IsParallel: true}
+	deploy := &Job{Name: "deploy", Uses: schedulerPlugin(t, "deploy", "true")}
+	notify := &Job{Name: "notify", Uses: schedulerPlugin(t, "notify", "true")}
+
+	workflow := []*Job{failing, slow, deploy, notify}
+	linkJobs(workflow)
+
+	r := Runner{hooks: &hookLog{}}
+
+	err := r.run(Pipeline{Workflow: workflow})
+
+	assert.Error(t, err)
+	assert.Equal(t, failing.Err, err)
+	assert.Equal(t, JobStatusFailed, failing.Status)
+	assert.Equal(t, JobStatusSuccess, slow.Status)
+	assert.Equal(t, JobStatusSkipped, deploy.Status)
+	assert.Equal(t, JobStatusSkipped, notify.Status)
+	assert.Equal(t, failing.Err, needsError(notify))
+}
+
+func TestScheduleRunsAtMostMaxParallelJobs(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are shell scripts")
+	}
+
+	defer func(f func() (interfaces.Client, error)) { newDockerClient = f }(newDockerClient)
+
+	newDockerClient = func() (interfaces.Client, error) {
+		t.Fatal("docker client created for a pipeline of plugin jobs")
+		return nil, nil
+	}
+
+	workflow := []*Job{}
+
+	for _, name := range []string{"a", "b", "c"} {
+		workflow = append(workflow, &Job{Name: name, Uses: schedulerPlugin(t, name, "sleep 0.1"), IsParallel: true})
+	}
+
+	linkJobs(workflow)
+
+	r := Runner{hooks: &hookLog{}}
+
+	assert.NoError(t, r.run(Pipeline{Workflow: workflow, MaxParallel: 2}))
+
+	// c waits for a free slot, a and b run together
+	assert.True(t, workflow[1].StartedAt.Before(workflow[0].FinishedAt))
+	assert.False(t, workflow[2].StartedAt.Before(workflow[0].FinishedAt) && workflow[2].StartedAt.Before(workflow[1].FinishedAt))
+}
+
+func TestScheduleFailFastCancelsTheRunningJobs(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are shell scripts")
+	}
+
+	defer func(f func() (interfaces.Client, error)) { newDockerClient = f }(newDockerClient)
+
+	newDockerClient = func() (interfaces.Client, error) {
+		t.Fatal("docker client created for a pipeline of plugin jobs")
+		return nil, nil
+	}
+
+	failing := &Job{Name: "failing", Uses: schedulerPlugin(t, "failing", "exit 1"), IsParallel: true}
+	slow := &Job{Name: "slow", Uses: schedulerPlugin(t, "slow", "exec sleep 5"), IsParallel: true}
+	waiting := &Job{Name: "waiting", Uses: schedulerPlugin(t, "waiting", "true"), IsParallel: true}
+	deploy := &Job{Name: "deploy", Uses: schedulerPlugin(t, "deploy", "true")}
+
+	workflow := []*Job{failing, slow, waiting, deploy}
+	linkJobs(workflow)
+
+	r := Runner{hooks: &hookLog{}}
+
+	started := time.Now()
+
+	// waiting only gets a slot after failing ended
+	err := r.run(Pipeline{Workflow: workflow, MaxParallel: 2, FailFast: true})
+
+	assert.Less(t, time.Since(started), 4*time.Second)
+	assert.Equal(t, failing.Err, err)
+	assert.Equal(t, JobStatusFailed, failing.Status)
+	assert.Equal(t, JobStatusCancelled, slow.Status)
+	assert.Equal(t, JobStatusSkipped, waiting.Status)
+	assert.Equal(t, JobStatusSkipped, deploy.Status)
+}
+
+func TestScheduleWithoutFailFastRunsTheIndependentJobs(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are shell scripts")
+	}
+
+	defer func(f func() (interfaces.Client, error)) { newDockerClient = f }(newDockerClient)
+
+	newDockerClient = func() (interfaces.Client, error) {
+		t.Fatal("docker client created for a pipeline of plugin jobs")
+		return nil, nil
+	}
+
+	// lint only gets a slot after build ended
+	lint := &Job{Name: "lint", Uses: schedulerPlugin(t, "lint", "true"), IsParallel: true}
+	build := &Job{Name: "build", Uses: schedulerPlugin(t, "build", "exit 1")}
+	deploy := &Job{Name: "deploy", Uses: schedulerPlugin(t, "deploy", "true")}
+
+	workflow := []*Job{build, deploy, lint}
+	linkJobs(workflow)
+
+	r := Runner{hooks: &hookLog{}}
+
+	err := r.run(Pipeline{Workflow: workflow, MaxParallel: 1})
+
+	assert.Equal(t, build.Err, err)
+	assert.Equal(t, JobStatusFailed, build.Status)
+	assert.Equal(t, JobStatusSkipped, deploy.Status)
+	assert.Equal(t, JobStatusSuccess, lint.Status)
+}
+
+func TestParseFailFast(t *testing.T) {
+	for _, c := range []struct {
+		value    string
+		failFast bool
+	}{
+		{"", false},
+		{"failFast: true", true},
+		{"failFast: false", false},
+	} {
+		config, _ := decodeConfig([]byte(c.value + "\nworkflow: []\n"))
+
+		pipeline, _, err := parse(config)
+
+		assert.NoError(t, err)
+		assert.Equal(t, c.failFast, pipeline.FailFast, c.value)
+	}
+
+	config, _ := decodeConfig([]byte("failFast: sometimes\nworkflow: []\n"))
+
+	_, _, err := parse(config)
+
+	assert.EqualError(t, err, "invalid failFast: sometimes")
+}
+
+func TestGetMaxParallel(t *testing.T) {
+	maxParallel, err := getMaxParallel(nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, maxParallel)
+
+	maxParallel, err = getMaxParallel(4)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 4, maxParallel)
+
+	for _, invalid := range []interface{}{0, -1, "4", 1.5} {
+		_, err = getMaxParallel(invalid)
+
+		assert.Error(t, err, invalid)
+	}
+}
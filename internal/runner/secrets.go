@@ -0,0 +1,136 @@
+package runner
+
+import (
+	"errors"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/muhammedikinci/pin/internal/pin_error"
+)
+
+// minSecretLength keeps short values like 1 or true from being masked
+// everywhere they appear.
+const minSecretLength = 4
+
+const secretMask = "***"
+
+// secretRegistry holds the values of the variables with secret looking
+// names, see sensitiveEnvPattern, they are masked in everything a job logs,
+// its script output and its error.
+type secretRegistry struct {
+	mu       sync.RWMutex
+	values   map[string]bool
+	replacer *strings.Replacer
+}
+
+func newSecretRegistry() *secretRegistry {
+	return &secretRegistry{values: map[string]bool{}}
+}
+
+// add registers the values of the secret looking variables of env.
+func (s *secretRegistry) add(env []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, v := range env {
+		name, value, found := strings.Cut(v, "=")
+
+		if found && len(value) >= minSecretLength && sensitiveEnvPattern.MatchString(name) {
+			s.values[value] = true
+		}
+	}
+
+	values := []string{}
+
+	for value := range s.values {
+		values = append(values, value)
+	}
+
+	// a secret containing another one is masked as a whole
+	sort.Slice(values, func(i, j int) bool { return len(values[i]) > len(values[j]) })
+
+	pairs := []string{}
+
+	for _, value := range values {
+		pairs = append(pairs, value, secretMask)
+	}
+
+	s.replacer = strings.NewReplacer(pairs...)
+}
+
+func (s *secretRegistry) mask(text string) string {
+	if s == nil {
+		return text
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.replacer == nil {
+		return text
+	}
+
+	return s.replacer.Replace(text)
+}
+
+// writer masks the secrets in every write to w, the loggers and the line
+// writers of the jobs write whole lines so a secret is never split.
+func (s *secretRegistry) writer(w io.Writer) io.Writer {
+	if s == nil {
+		return w
+	}
+
+	return maskedWriter{w: w, secrets: s}
+}
+
+type maskedWriter struct {
+	w       io.Writer
+	secrets *secretRegistry
+}
+
+func (m maskedWriter) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(m.w, m.secrets.mask(string(p))); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// maskError hides the secrets in the message of err, the chain is kept so
+// the error code and the exit code of the job are still found.
+func (s *secretRegistry) maskError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	message := s.mask(err.Error())
+
+	if message == err.Error() {
+		return err
+	}
+
+	masked := &maskedError{message: message, err: err}
+
+	var pinErr *pin_error.PinError
+
+	if errors.As(err, &pinErr) {
+		return pin_error.New(pinErr.Code, pinErr.Operation, s.mask(pinErr.Message), masked)
+	}
+
+	return masked
+}
+
+type maskedError struct {
+	message string
+	err     error
+}
+
+func (e *maskedError) Error() string {
+	return e.message
+}
+
+func (e *maskedError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,89 @@
+package runner
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/muhammedikinci/pin/internal/interfaces"
+	"github.com/muhammedikinci/pin/internal/pin_error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecretRegistryMasksSecretLookingValues(t *testing.T) {
+	secrets := newSecretRegistry()
+
+	secrets.add([]string{"API_TOKEN=abcd1234", "DB_PASSWORD=abcd1234-long", "DEBUG=true", "AUTH_ENABLED=1", "PASSWORD"})
+
+	assert.Equal(t, "token *** and ***, debug true 1", secrets.mask("token abcd1234 and abcd1234-long, debug true 1"))
+
+	var b bytes.Buffer
+
+	n, err := secrets.writer(&b).Write([]byte("curl -H 'Authorization: abcd1234'\n"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 34, n)
+	assert.Equal(t, "curl -H 'Authorization: ***'\n", b.String())
+
+	var none *secretRegistry
+
+	assert.Equal(t, "abcd1234", none.mask("abcd1234"))
+	assert.Equal(t, &b, none.writer(&b))
+}
+
+func TestMaskErrorKeepsTheErrorChain(t *testing.T) {
+	secrets := newSecretRegistry()
+	secrets.add([]string{"API_TOKEN=abcd1234"})
+
+	cmdErr := &commandError{exitCode: 2}
+
+	assert.Equal(t, error(cmdErr), secrets.maskError(cmdErr))
+	assert.Nil(t, secrets.maskError(nil))
+
+	err := secrets.maskError(fmt.Errorf("login with abcd1234 failed: %w", cmdErr))
+
+	assert.EqualError(t, err, "login with *** failed: command execution failed")
+	assert.True(t, errors.As(err, &cmdErr))
+
+	err = secrets.maskError(pin_error.New(pin_error.CodeTimeout, "push", "push with abcd1234 timed out", nil))
+
+	assert.Equal(t, pin_error.CodeTimeout, classifyError(err).Code)
+	assert.Equal(t, "push with *** timed out", classifyError(err).Message)
+}
+
+func TestJobOutputAndErrorsAreMasked(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugin is a shell script")
+	}
+
+	defer func(f func() (interfaces.Client, error)) { newDockerClient = f }(newDockerClient)
+
+	newDockerClient = func() (interfaces.Client, error) {
+		t.Fatal("docker client created for a pipeline of plugin jobs")
+		return nil, nil
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "deploy")
+	os.WriteFile(path, []byte("#!/bin/sh\necho \"{\\\"type\\\":\\\"log\\\",\\\"message\\\":\\\"token $API_TOKEN\\\"}\"\necho \"stderr $API_TOKEN\" >&2\necho \"{\\\"type\\\":\\\"error\\\",\\\"message\\\":\\\"rejected $API_TOKEN\\\"}\"\n"), 0755)
+
+	logDir := filepath.Join(dir, "logs")
+	job := &Job{Name: "deploy", Uses: path, Env: []string{"API_TOKEN=s3cr3t-value"}}
+
+	r := Runner{runID: "20220515-101500-a1b2c3", hooks: &hookLog{}, logDir: logDir}
+
+	err := r.run(Pipeline{Workflow: []*Job{job}})
+
+	assert.EqualError(t, err, "plugin "+path+" failed: rejected ***")
+	assert.EqualError(t, job.Err, "plugin "+path+" failed: rejected ***")
+
+	b, _ := os.ReadFile(jobLogPath(logDir, "20220515-101500-a1b2c3", "deploy"))
+
+	assert.Contains(t, string(b), "token ***")
+	assert.Contains(t, string(b), "stderr ***")
+	assert.NotContains(t, string(b), "s3cr3t-value")
+}
@@ -0,0 +1,106 @@
+package runner
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/fatih/color"
+	"github.com/muhammedikinci/pin/internal/interfaces"
+)
+
+const serviceReadyTimeout = 60 * time.Second
+
+func (r *Runner) startServices(currentJob *Job) error {
+	networkName := currentJob.Name + "_network_" + strconv.Itoa(int(time.Now().UnixMilli()))
+
+	networkID, err := currentJob.ContainerManager.CreateNetwork(r.ctx, networkName, r.labels(currentJob))
+
+	if err != nil {
+		return err
+	}
+
+	currentJob.Network = networkID
+
+	for i := range currentJob.Services {
+		service := &currentJob.Services[i]
+
+		color.Set(color.FgGreen)
+		currentJob.InfoLog.Printf("Starting service: %s (%s)", service.Name, service.Image)
+		color.Unset()
+
+		isImageAvailable, err := currentJob.ImageManager.CheckTheImageAvailable(r.ctx, service.Image)
+
+		if err != nil {
+			return err
+		}
+
+		if !isImageAvailable {
+			if err := currentJob.ImageManager.PullImage(r.ctx, service.Image); err != nil {
+				return err
+			}
+		}
+
+		resp, err := currentJob.ContainerManager.StartContainer(r.ctx, interfaces.ContainerOptions{
+			Name:           currentJob.Name + "_" + service.Name,
+			Image:          service.Image,
+			Env:            service.Env,
+			Network:        networkID,
+			NetworkAliases: []string{service.Name},
+			Labels:         r.serviceLabels(currentJob, service.Name),
+			Healthcheck:    service.Healthcheck,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		service.Container = resp
+
+		if err := r.cli.ContainerStart(r.ctx, resp.ID, types.ContainerStartOptions{}); err != nil {
+			return err
+		}
+
+		if err := currentJob.ContainerManager.WaitForContainer(r.ctx, resp.ID, readyTimeout(service.Healthcheck)); err != nil {
+			return err
+		}
+
+		currentJob.InfoLog.Printf("Service ready: %s", service.Name)
+	}
+
+	return nil
+}
+
+// stopServices is best effort, teardown must not hide the job result.
+func (r *Runner) stopServices(currentJob *Job) {
+	ctx := r.ctx
+
+	if ctx.Err() != nil {
+		timeout := time.Second * 10
+
+		if currentJob.StopGracePeriod != nil {
+			timeout += *currentJob.StopGracePeriod
+		}
+
+		timedContext, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		ctx = timedContext
+	}
+
+	for _, service := range currentJob.Services {
+		if service.Container.ID == "" {
+			continue
+		}
+
+		if currentJob.StopGracePeriod != nil {
+			currentJob.ContainerManager.StopContainer(ctx, service.Container.ID, currentJob.StopGracePeriod)
+		}
+
+		currentJob.ContainerManager.RemoveContainer(ctx, service.Container.ID, true)
+	}
+
+	if currentJob.Network != "" {
+		currentJob.ContainerManager.RemoveNetwork(ctx, currentJob.Network)
+	}
+}
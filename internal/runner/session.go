@@ -0,0 +1,139 @@
+package runner
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/fatih/color"
+)
+
+const (
+	SessionModeIsolated = "isolated"
+	SessionModeShared   = "shared"
+)
+
+// sessionScriptExecutor runs every script entry in one long-lived shell, so
+// exported variables and the working directory survive between steps. Each
+// step is followed by a marker line carrying its exit code.
+func (r Runner) sessionScriptExecutor(currentJob Job) error {
+	exec, err := r.cli.ContainerExecCreate(r.ctx, currentJob.Container.ID, types.ExecConfig{
+		AttachStdin:  true,
+		AttachStdout: true,
+		AttachStderr: true,
+		Cmd:          []string{"sh"},
+		WorkingDir:   currentJob.WorkDir,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	res, err := r.cli.ContainerExecAttach(r.ctx, exec.ID, types.ExecStartCheck{})
+
+	if err != nil {
+		return err
+	}
+
+	defer res.Close()
+
+	pr, pw := io.Pipe()
+
+	go func() {
+		_, err := stdcopy.StdCopy(pw, pw, res.Reader)
+		pw.CloseWithError(err)
+	}()
+
+	reader := bufio.NewReader(pr)
+	marker := sessionMarker()
+
+	currentJob.InfoLog.Println("Shared shell session started")
+
+	for _, cmd := range currentJob.Script {
+		currentJob.InfoLog.Printf("Execute command: %s", cmd)
+
+		startedAt := time.Now()
+
+		if _, err := fmt.Fprintf(res.Conn, "{\n%s\n} </dev/null 2>&1\necho \"%s $?\"\n", cmd, marker); err != nil {
+			return err
+		}
+
+		exitCode, ok := readSessionStep(reader, marker, jobWriter(currentJob))
+		currentJob.Timings.step(cmd, startedAt)
+
+		if ok {
+			exitCode = r.injectFailure(currentJob, cmd, exitCode)
+		}
+
+		if !ok {
+			color.Set(color.FgRed)
+			currentJob.InfoLog.Println("Shell session ended unexpectedly")
+			color.Unset()
+
+			return r.removeFailedContainer(currentJob, &commandError{exitCode: -1})
+		}
+
+		if exitCode != 0 {
+			color.Set(color.FgRed)
+			currentJob.InfoLog.Printf("Command execution failed with exit code %d", exitCode)
+			color.Unset()
+
+			return r.removeFailedContainer(currentJob, &commandError{exitCode: exitCode})
+		}
+
+		currentJob.InfoLog.Println("Command execution successful")
+	}
+
+	res.CloseWrite()
+
+	return nil
+}
+
+// readSessionStep streams the output to w until the marker, ok is false when
+// the shell exits before printing the marker. The marker ends the last line
+// of a step whose output does not end with a newline. Lines are read without
+// a length limit, a step may print minified or base64 data on a single line.
+func readSessionStep(reader *bufio.Reader, marker string, w io.Writer) (int, bool) {
+	for {
+		line, readErr := reader.ReadString('\n')
+		line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+
+		if i := strings.Index(line, marker+" "); i >= 0 {
+			if i > 0 {
+				fmt.Fprintln(w, line[:i])
+			}
+
+			exitCode, err := strconv.Atoi(line[i+len(marker)+1:])
+
+			if err != nil {
+				return -1, true
+			}
+
+			return exitCode, true
+		}
+
+		if readErr != nil {
+			if line != "" {
+				fmt.Fprintln(w, line)
+			}
+
+			return -1, false
+		}
+
+		fmt.Fprintln(w, line)
+	}
+}
+
+func sessionMarker() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+
+	return "__PIN_STEP_" + hex.EncodeToString(b) + "__"
+}
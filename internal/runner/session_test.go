@@ -0,0 +1,77 @@
+package runner
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadSessionStepReturnsOutputAndExitCode(t *testing.T) {
+	marker := "__PIN_STEP_test__"
+	reader := bufio.NewReader(strings.NewReader("hello\nworld\n" + marker + " 0\nfailed\n" + marker + " 2\npartial\n"))
+
+	var output bytes.Buffer
+
+	exitCode, ok := readSessionStep(reader, marker, &output)
+
+	assert.Equal(t, "hello\nworld\n", output.String())
+	assert.Equal(t, 0, exitCode)
+	assert.Equal(t, true, ok)
+
+	output.Reset()
+	exitCode, ok = readSessionStep(reader, marker, &output)
+
+	assert.Equal(t, "failed\n", output.String())
+	assert.Equal(t, 2, exitCode)
+	assert.Equal(t, true, ok)
+
+	output.Reset()
+	_, ok = readSessionStep(reader, marker, &output)
+
+	assert.Equal(t, "partial\n", output.String())
+	assert.Equal(t, false, ok)
+}
+
+func TestReadSessionStepFindsTheMarkerAfterOutputWithoutNewline(t *testing.T) {
+	marker := "__PIN_STEP_test__"
+	reader := bufio.NewReader(strings.NewReader("line\nfoo" + marker + " 3\n" + marker + " 0\npartial"))
+
+	var output bytes.Buffer
+
+	exitCode, ok := readSessionStep(reader, marker, &output)
+
+	assert.Equal(t, "line\nfoo\n", output.String())
+	assert.Equal(t, 3, exitCode)
+	assert.Equal(t, true, ok)
+
+	// a step without any output
+	output.Reset()
+	exitCode, ok = readSessionStep(reader, marker, &output)
+
+	assert.Empty(t, output.String())
+	assert.Equal(t, 0, exitCode)
+	assert.Equal(t, true, ok)
+
+	output.Reset()
+	_, ok = readSessionStep(reader, marker, &output)
+
+	assert.Equal(t, "partial\n", output.String())
+	assert.Equal(t, false, ok)
+}
+
+func TestReadSessionStepReadsLinesLongerThanTheScannerLimit(t *testing.T) {
+	marker := "__PIN_STEP_test__"
+	long := strings.Repeat("a", 1024*1024)
+	reader := bufio.NewReader(strings.NewReader(long + "\n" + marker + " 0\n"))
+
+	var output bytes.Buffer
+
+	exitCode, ok := readSessionStep(reader, marker, &output)
+
+	assert.Equal(t, long+"\n", output.String())
+	assert.Equal(t, 0, exitCode)
+	assert.Equal(t, true, ok)
+}
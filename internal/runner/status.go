@@ -0,0 +1,282 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/muhammedikinci/pin/internal/container_manager"
+	"github.com/muhammedikinci/pin/internal/interfaces"
+)
+
+// statusRuns is how many recent runs pin status lists.
+const statusRuns = 10
+
+type StatusOptions struct {
+	// Resources lists the docker resources of pin instead of the runs.
+	Resources bool
+	// TTL is how old job and service containers and networks get before
+	// they are reported as stale.
+	TTL time.Duration
+	// DetachedTTL is how old the containers of detached jobs get before they
+	// are reported as stale.
+	DetachedTTL time.Duration
+	// VolumeTTL is how old workspace volumes get before they are reported as
+	// stale.
+	VolumeTTL time.Duration
+	// Clean removes the stale resources.
+	Clean bool
+}
+
+// ResourceStatus is a docker resource of pin with the run that owns it.
+type ResourceStatus struct {
+	interfaces.Resource
+	RunID string
+	Job   string
+	Age   time.Duration
+	// Detached is a container a job with detach left running.
+	Detached bool
+	Stale    bool
+}
+
+func (s ResourceStatus) kind() string {
+	if s.Detached {
+		return "detached"
+	}
+
+	return s.Kind
+}
+
+// Status prints the recent runs, or the containers, networks, volumes and
+// detached jobs pin left in docker with their age and owning run.
+func Status(options StatusOptions) error {
+	if !options.Resources {
+		if options.Clean {
+			err := errors.New("--clean can only be used with --resources")
+			fmt.Println(err)
+			return err
+		}
+
+		if err := printRuns(); err != nil {
+			fmt.Println(err)
+			return err
+		}
+
+		return nil
+	}
+
+	if err := resourceStatus(options); err != nil {
+		fmt.Println(err)
+		return err
+	}
+
+	return nil
+}
+
+func printRuns() error {
+	ids := CompleteRunIDs()
+
+	if len(ids) > statusRuns {
+		ids = ids[:statusRuns]
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "RUN\tPIPELINE\tSTATUS\tSTARTED\tDETACHED")
+
+	for _, id := range ids {
+		run, err := loadRun(id)
+
+		// runs without metadata are still running or were killed
+		if err != nil {
+			fmt.Fprintf(w, "%s\t-\trunning\t-\t-\n", id)
+			continue
+		}
+
+		detached := 0
+
+		for _, job := range run.Jobs {
+			if job.DetachedContainer != "" {
+				detached++
+			}
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\n", run.ID, run.Pipeline, run.Status, run.StartedAt.Local().Format("2006-01-02 15:04:05"), detached)
+	}
+
+	return w.Flush()
+}
+
+func resourceStatus(options StatusOptions) error {
+	cli, err := newDockerClient()
+
+	if err != nil {
+		return err
+	}
+
+	infoLog := log.New(os.Stdout, glyph(glyphJob)+" status ", 0)
+	containerManager := container_manager.NewContainerManager(cli, infoLog)
+	ctx := context.Background()
+
+	resources, err := containerManager.ListResources(ctx, workspaceVolumePrefix)
+
+	if err != nil {
+		return err
+	}
+
+	statuses := resourceStatuses(resources, options, time.Now())
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "KIND\tNAME\tRUN\tJOB\tAGE\tSTATE")
+
+	for _, status := range statuses {
+		state := orDash(status.State)
+
+		if status.Stale {
+			state += " (stale)"
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", status.kind(), status.Name, orDash(status.RunID), orDash(status.Job), formatAge(status.Age), state)
+	}
+
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	stale := []ResourceStatus{}
+
+	for _, status := range statuses {
+		if status.Stale {
+			stale = append(stale, status)
+		}
+	}
+
+	if len(stale) == 0 {
+		return nil
+	}
+
+	if !options.Clean {
+		color.Set(color.FgYellow)
+		fmt.Printf("warning: %d resources are older than their ttl, remove them with pin status --resources --clean\n", len(stale))
+		color.Unset()
+
+		return nil
+	}
+
+	removed := 0
+
+	for _, status := range stale {
+		if err := containerManager.RemoveResource(ctx, status.Resource); err != nil {
+			infoLog.Printf("Skipped %s %s (%s)", status.kind(), status.Name, err)
+			continue
+		}
+
+		if status.Detached {
+			forgetDetachedContainer(status.RunID, status.ID)
+		}
+
+		infoLog.Printf("Removed %s %s", status.kind(), status.Name)
+		removed++
+	}
+
+	color.Set(color.FgGreen)
+	infoLog.Printf("Removed %d stale resources", removed)
+	color.Unset()
+
+	return nil
+}
+
+// resourceStatuses finds the owning run of every resource and marks the
+// ones older than the ttl of their kind as stale, a zero ttl never expires.
+// Containers recorded by a run as detached have their own ttl.
+func resourceStatuses(resources []interfaces.Resource, options StatusOptions, now time.Time) []ResourceStatus {
+	runs := map[string]RunMetadata{}
+	statuses := []ResourceStatus{}
+
+	for _, resource := range resources {
+		status := ResourceStatus{
+			Resource: resource,
+			RunID:    resource.Labels[LabelRunID],
+			Job:      resource.Labels[LabelJob],
+		}
+
+		if !resource.Created.IsZero() {
+			status.Age = now.Sub(resource.Created)
+		}
+
+		if resource.Kind == interfaces.ResourceContainer && status.RunID != "" {
+			run, ok := runs[status.RunID]
+
+			if !ok {
+				run, _ = loadRun(status.RunID)
+				runs[status.RunID] = run
+			}
+
+			for _, job := range run.Jobs {
+				if job.DetachedContainer == resource.ID {
+					status.Detached = true
+				}
+			}
+		}
+
+		ttl := options.TTL
+
+		if status.Detached {
+			ttl = options.DetachedTTL
+		} else if resource.Kind == interfaces.ResourceVolume {
+			ttl = options.VolumeTTL
+		}
+
+		status.Stale = ttl > 0 && !resource.Created.IsZero() && status.Age > ttl
+
+		statuses = append(statuses, status)
+	}
+
+	sort.SliceStable(statuses, func(i, j int) bool {
+		return statuses[i].Age > statuses[j].Age
+	})
+
+	return statuses
+}
+
+// forgetDetachedContainer clears a removed container from its run, so pin
+// down does not try to stop it again.
+func forgetDetachedContainer(runID, containerID string) {
+	run, err := loadRun(runID)
+
+	if err != nil {
+		return
+	}
+
+	for i := range run.Jobs {
+		if run.Jobs[i].DetachedContainer == containerID {
+			run.Jobs[i].DetachedContainer = ""
+		}
+	}
+
+	writeRunMetadata(run)
+}
+
+// formatAge rounds an age to the largest unit, like docker ps does.
+func formatAge(age time.Duration) string {
+	switch {
+	case age <= 0:
+		return "-"
+	case age < time.Minute:
+		return strconv.Itoa(int(age.Seconds())) + "s"
+	case age < time.Hour:
+		return strconv.Itoa(int(age.Minutes())) + "m"
+	case age < 48*time.Hour:
+		return strconv.Itoa(int(age.Hours())) + "h"
+	}
+
+	return strconv.Itoa(int(age.Hours()/24)) + "d"
+}
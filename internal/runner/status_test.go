@@ -0,0 +1,56 @@
+package runner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/muhammedikinci/pin/internal/interfaces"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResourceStatusesAppliesTheTTLOfEveryKind(t *testing.T) {
+	t.Setenv("PIN_HOME", t.TempDir())
+
+	err := saveRun(RunMetadata{ID: "20220515-100000-aaaaaa", Jobs: []JobSnapshot{{Name: "web", DetachedContainer: "web-container"}}}, []byte("workflow: []"))
+	assert.NoError(t, err)
+
+	now := time.Date(2022, 5, 20, 10, 0, 0, 0, time.UTC)
+	labels := map[string]string{LabelRunID: "20220515-100000-aaaaaa", LabelJob: "web"}
+
+	statuses := resourceStatuses([]interfaces.Resource{
+		{Kind: interfaces.ResourceContainer, ID: "web-container", Name: "web_1", State: "running", Labels: labels, Created: now.Add(-5 * 24 * time.Hour)},
+		{Kind: interfaces.ResourceContainer, ID: "build-container", Name: "build_1", State: "exited", Labels: labels, Created: now.Add(-2 * time.Hour)},
+		{Kind: interfaces.ResourceNetwork, ID: "n", Name: "test_network_1", Labels: labels, Created: now.Add(-30 * time.Minute)},
+		{Kind: interfaces.ResourceVolume, ID: "pin_workspace_x_build", Name: "pin_workspace_x_build"},
+	}, StatusOptions{TTL: time.Hour, DetachedTTL: 7 * 24 * time.Hour}, now)
+
+	assert.Equal(t, 4, len(statuses))
+
+	assert.Equal(t, "web_1", statuses[0].Name)
+	assert.Equal(t, "detached", statuses[0].kind())
+	assert.Equal(t, false, statuses[0].Stale)
+
+	assert.Equal(t, "build_1", statuses[1].Name)
+	assert.Equal(t, "container", statuses[1].kind())
+	assert.Equal(t, "20220515-100000-aaaaaa", statuses[1].RunID)
+	assert.Equal(t, true, statuses[1].Stale)
+
+	assert.Equal(t, "network", statuses[2].kind())
+	assert.Equal(t, false, statuses[2].Stale)
+
+	// volumes without a creation time never expire
+	assert.Equal(t, "volume", statuses[3].kind())
+	assert.Equal(t, false, statuses[3].Stale)
+}
+
+func TestFormatAge(t *testing.T) {
+	assert.Equal(t, "-", formatAge(0))
+	assert.Equal(t, "42s", formatAge(42*time.Second))
+	assert.Equal(t, "5m", formatAge(5*time.Minute+10*time.Second))
+	assert.Equal(t, "30h", formatAge(30*time.Hour))
+	assert.Equal(t, "3d", formatAge(80*time.Hour))
+}
+
+func TestStatusCleanNeedsResources(t *testing.T) {
+	assert.EqualError(t, Status(StatusOptions{Clean: true}), "--clean can only be used with --resources")
+}
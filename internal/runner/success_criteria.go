@@ -0,0 +1,194 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// SuccessCriteria is checked at the end of a run in which every job
+// succeeded, the run fails when a criterion is not met.
+type SuccessCriteria struct {
+	Healthcheck *Healthcheck
+}
+
+// Healthcheck requests URL until it answers with Status, retrying Retries
+// times with Interval between the attempts.
+type Healthcheck struct {
+	URL      string
+	Status   int
+	Retries  int
+	Interval time.Duration
+	Timeout  time.Duration
+}
+
+type HealthcheckResult struct {
+	URL      string `json:"url"`
+	Status   int    `json:"status,omitempty"`
+	Attempts int    `json:"attempts"`
+	Error    string `json:"error,omitempty"`
+}
+
+func getSuccessCriteria(successCriteria interface{}) (*SuccessCriteria, error) {
+	if successCriteria == nil {
+		return nil, nil
+	}
+
+	criteriaMap := getStringMap(successCriteria)
+
+	if len(criteriaMap) == 0 {
+		return nil, errors.New("successCriteria must be a map")
+	}
+
+	for key := range criteriaMap {
+		if key != "healthcheck" {
+			return nil, fmt.Errorf("unknown success criterion: %s", key)
+		}
+	}
+
+	healthcheck, err := getHealthcheck(criteriaMap["healthcheck"])
+
+	if err != nil {
+		return nil, fmt.Errorf("healthcheck: %w", err)
+	}
+
+	return &SuccessCriteria{Healthcheck: healthcheck}, nil
+}
+
+func getHealthcheck(healthcheck interface{}) (*Healthcheck, error) {
+	if healthcheck == nil {
+		return nil, nil
+	}
+
+	healthcheckMap := getStringMap(healthcheck)
+
+	url, _ := healthcheckMap["url"].(string)
+
+	if url == "" {
+		return nil, errors.New("url not specified")
+	}
+
+	check := &Healthcheck{
+		URL:      url,
+		Status:   http.StatusOK,
+		Retries:  3,
+		Interval: 5 * time.Second,
+		Timeout:  10 * time.Second,
+	}
+
+	if status, ok := healthcheckMap["status"]; ok {
+		if check.Status, ok = status.(int); !ok {
+			return nil, fmt.Errorf("invalid status: %v", status)
+		}
+	}
+
+	if retries, ok := healthcheckMap["retries"]; ok {
+		if check.Retries, ok = retries.(int); !ok || check.Retries < 0 {
+			return nil, fmt.Errorf("invalid retries: %v", retries)
+		}
+	}
+
+	for key, target := range map[string]*time.Duration{"interval": &check.Interval, "timeout": &check.Timeout} {
+		d, err := getDuration(healthcheckMap[key])
+
+		if err != nil {
+			return nil, err
+		}
+
+		if d != nil {
+			*target = *d
+		}
+	}
+
+	return check, nil
+}
+
+// checkSuccessCriteria runs the healthcheck of the criteria, the result is
+// kept for the run metadata.
+func (r *Runner) checkSuccessCriteria(criteria *SuccessCriteria) error {
+	if criteria == nil || criteria.Healthcheck == nil {
+		return nil
+	}
+
+	result := runHealthcheck(r.ctx, *criteria.Healthcheck, r.pipelineLogger())
+	r.healthcheck = &result
+
+	if result.Error != "" {
+		return fmt.Errorf("healthcheck failed: %s", result.Error)
+	}
+
+	return nil
+}
+
+func runHealthcheck(ctx context.Context, check Healthcheck, infoLog *log.Logger) HealthcheckResult {
+	result := HealthcheckResult{URL: check.URL}
+	client := &http.Client{Timeout: check.Timeout}
+
+	for {
+		result.Attempts++
+
+		color.Set(color.FgBlue)
+		infoLog.Printf("Healthcheck %s (attempt %d)", check.URL, result.Attempts)
+		color.Unset()
+
+		err := healthcheckRequest(ctx, client, check, &result)
+
+		if err == nil {
+			color.Set(color.FgGreen)
+			infoLog.Printf("%s Healthcheck passed: %s returned %d", glyph(glyphSuccess), check.URL, result.Status)
+			color.Unset()
+
+			result.Error = ""
+
+			return result
+		}
+
+		result.Error = err.Error()
+
+		if result.Attempts > check.Retries {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			result.Error = ctx.Err().Error()
+			return result
+		case <-time.After(check.Interval):
+		}
+	}
+
+	color.Set(color.FgRed)
+	infoLog.Printf("%s Healthcheck failed: %s", glyph(glyphFailure), result.Error)
+	color.Unset()
+
+	return result
+}
+
+func healthcheckRequest(ctx context.Context, client *http.Client, check Healthcheck, result *HealthcheckResult) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, check.URL, nil)
+
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Do(req)
+
+	if err != nil {
+		return err
+	}
+
+	resp.Body.Close()
+
+	result.Status = resp.StatusCode
+
+	if resp.StatusCode != check.Status {
+		return fmt.Errorf("%s returned %d, expected %d", check.URL, resp.StatusCode, check.Status)
+	}
+
+	return nil
+}
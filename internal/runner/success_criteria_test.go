@@ -0,0 +1,83 @@
+package runner
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetSuccessCriteriaAppliesDefaults(t *testing.T) {
+	criteria, err := getSuccessCriteria(map[string]interface{}{
+		"healthcheck": map[string]interface{}{
+			"url":      "https://example.com/health",
+			"interval": "1s",
+		},
+	})
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, &Healthcheck{
+		URL:      "https://example.com/health",
+		Status:   200,
+		Retries:  3,
+		Interval: time.Second,
+		Timeout:  10 * time.Second,
+	}, criteria.Healthcheck)
+
+	_, err = getSuccessCriteria(map[string]interface{}{"healthcheck": map[string]interface{}{"status": 204}})
+
+	assert.EqualError(t, err, "healthcheck: url not specified")
+
+	_, err = getSuccessCriteria(map[string]interface{}{"coverage": 80})
+
+	assert.EqualError(t, err, "unknown success criterion: coverage")
+}
+
+func TestRunHealthcheckRetriesUntilExpectedStatus(t *testing.T) {
+	requests := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		if requests < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	defer server.Close()
+
+	check := Healthcheck{URL: server.URL, Status: http.StatusOK, Retries: 3, Interval: time.Millisecond, Timeout: time.Second}
+
+	result := runHealthcheck(context.Background(), check, log.New(io.Discard, "", 0))
+
+	assert.Equal(t, HealthcheckResult{URL: server.URL, Status: http.StatusOK, Attempts: 3}, result)
+}
+
+func TestCheckSuccessCriteriaFailsAfterRetries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+
+	defer server.Close()
+
+	r := Runner{ctx: context.Background()}
+
+	err := r.checkSuccessCriteria(&SuccessCriteria{Healthcheck: &Healthcheck{
+		URL:      server.URL,
+		Status:   http.StatusOK,
+		Retries:  1,
+		Interval: time.Millisecond,
+		Timeout:  time.Second,
+	}})
+
+	assert.EqualError(t, err, "healthcheck failed: "+server.URL+" returned 502, expected 200")
+	assert.Equal(t, 2, r.healthcheck.Attempts)
+}
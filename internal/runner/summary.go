@@ -0,0 +1,127 @@
+package runner
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// printSummary prints a table of the jobs of a finished run, so what passed
+// does not have to be searched in the interleaved job logs.
+func printSummary(w io.Writer, report RunReport) {
+	if len(report.Jobs) == 0 {
+		return
+	}
+
+	fmt.Fprintln(w)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "JOB\tSTATUS\tATTEMPTS\tDURATION\tEXIT CODE")
+
+	for i, row := range summaryRows(report) {
+		// every status is colored, so the escape codes do not break the
+		// alignment of the table
+		row[1] = summaryColor(report.Jobs[i]).Sprint(row[1])
+
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+
+	tw.Flush()
+
+	printTestReports(w, report.Jobs)
+	printTimings(w, report.Jobs)
+	printResources(w, report.Jobs)
+
+	fmt.Fprintf(w, "Run %s %s in %s\n", report.ID, summaryColor(JobReport{Status: report.Status}).Sprint(report.Status), report.Duration.Round(time.Millisecond))
+}
+
+// printResources lists the peaks of the jobs whose container was sampled.
+func printResources(w io.Writer, jobs []JobReport) {
+	for _, job := range jobs {
+		if job.Resources == nil {
+			continue
+		}
+
+		fmt.Fprintf(
+			w,
+			"Resources of %s: peak cpu %.1f%%, peak memory %s, network rx %s / tx %s\n",
+			job.Name,
+			job.Resources.PeakCPUPercent,
+			formatBytes(job.Resources.PeakMemoryBytes),
+			formatBytes(job.Resources.NetworkRxBytes),
+			formatBytes(job.Resources.NetworkTxBytes),
+		)
+	}
+}
+
+func summaryRows(report RunReport) [][]string {
+	rows := [][]string{}
+
+	for _, job := range report.Jobs {
+		status := job.Status
+
+		if job.Tolerated {
+			status += " (tolerated)"
+		}
+
+		exitCode := "-"
+
+		if job.ExitCode != nil {
+			exitCode = fmt.Sprint(*job.ExitCode)
+		}
+
+		rows = append(rows, []string{
+			job.Name,
+			status,
+			fmt.Sprint(job.Attempts),
+			job.Duration.Round(time.Millisecond).String(),
+			exitCode,
+		})
+	}
+
+	return rows
+}
+
+func summaryColor(job JobReport) *color.Color {
+	switch {
+	case job.Tolerated:
+		return color.New(color.FgYellow)
+	case job.Status == JobStatusFailed:
+		return color.New(color.FgRed)
+	case job.Status == JobStatusSuccess || job.Status == JobStatusCached || job.Status == JobStatusCacheHit:
+		return color.New(color.FgGreen)
+	default:
+		return color.New(color.FgYellow)
+	}
+}
+
+// jobAttempts counts the runs of the rerun chain that ran the job, the run
+// itself included.
+func jobAttempts(run RunMetadata, name string) int {
+	attempts := 0
+
+	for {
+		for _, job := range run.Jobs {
+			if job.Name == name && job.Status != "" && job.Status != JobStatusSkipped {
+				attempts++
+			}
+		}
+
+		if run.RerunOf == "" {
+			return attempts
+		}
+
+		previous, err := loadRun(run.RerunOf)
+
+		if err != nil {
+			return attempts
+		}
+
+		run = previous
+	}
+}
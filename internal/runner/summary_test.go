@@ -0,0 +1,79 @@
+package runner
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrintSummaryListsEveryJob(t *testing.T) {
+	defer func(noColor bool) { color.NoColor = noColor }(color.NoColor)
+
+	color.NoColor = true
+
+	exitCode := 1
+
+	report := RunReport{ID: "20220515-101500-a1b2c3", Status: JobStatusFailed, Duration: 90 * time.Second, Jobs: []JobReport{
+		{Name: "build", Status: JobStatusSuccess, Attempts: 1, Duration: time.Minute},
+		{Name: "chrome", Status: JobStatusFailed, Tolerated: true, Attempts: 2, Duration: 1500 * time.Millisecond, ExitCode: &exitCode},
+		{Name: "deploy", Status: JobStatusSkipped},
+	}}
+
+	var b bytes.Buffer
+
+	printSummary(&b, report)
+
+	assert.Equal(t, `
+JOB     STATUS              ATTEMPTS  DURATION  EXIT CODE
+build   success             1         1m0s      -
+chrome  failed (tolerated)  2         1.5s      1
+deploy  skipped             0         0s        -
+Run 20220515-101500-a1b2c3 failed in 1m30s
+`, b.String())
+
+	b.Reset()
+
+	printSummary(&b, RunReport{})
+
+	assert.Empty(t, b.String())
+}
+
+func TestPrintSummaryListsTheResourcePeaks(t *testing.T) {
+	defer func(noColor bool) { color.NoColor = noColor }(color.NoColor)
+
+	color.NoColor = true
+
+	report := RunReport{ID: "20220515-101500-a1b2c3", Status: JobStatusSuccess, Jobs: []JobReport{
+		{Name: "build", Status: JobStatusSuccess, Attempts: 1, Resources: &JobResources{PeakCPUPercent: 85, PeakMemoryBytes: 512 << 20, NetworkRxBytes: 2048, NetworkTxBytes: 100}},
+		{Name: "deploy", Status: JobStatusSuccess, Attempts: 1},
+	}}
+
+	var b bytes.Buffer
+
+	printSummary(&b, report)
+
+	assert.Contains(t, b.String(), "Resources of build: peak cpu 85.0%, peak memory 512.0MiB, network rx 2.0KiB / tx 100B\n")
+	assert.NotContains(t, b.String(), "Resources of deploy")
+}
+
+func TestJobAttemptsCountsTheRunsThatRanTheJob(t *testing.T) {
+	t.Setenv("PIN_HOME", t.TempDir())
+
+	assert.NoError(t, saveRun(RunMetadata{ID: "20220515-100000-aaaaaa", Jobs: []JobSnapshot{
+		{Name: "build", Status: JobStatusSuccess},
+		{Name: "test", Status: JobStatusFailed},
+		{Name: "deploy", Status: JobStatusSkipped},
+	}}, []byte("workflow: []")))
+
+	run := RunMetadata{ID: "20220515-110000-bbbbbb", RerunOf: "20220515-100000-aaaaaa", Jobs: []JobSnapshot{
+		{Name: "test", Status: JobStatusSuccess},
+		{Name: "deploy", Status: JobStatusSuccess},
+	}}
+
+	assert.Equal(t, 1, jobAttempts(run, "build"))
+	assert.Equal(t, 2, jobAttempts(run, "test"))
+	assert.Equal(t, 1, jobAttempts(run, "deploy"))
+}
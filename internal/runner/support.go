@@ -0,0 +1,328 @@
+package runner
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/client"
+	"gopkg.in/yaml.v3"
+)
+
+// recentRunsInBundle is how many of the latest runs a support bundle keeps.
+const recentRunsInBundle = 5
+
+var credentialsInURL = regexp.MustCompile(`://[^/@\s]+@`)
+
+type bundlePlanJob struct {
+	Name       string   `json:"name"`
+	Image      string   `json:"image"`
+	Dockerfile string   `json:"dockerfile,omitempty"`
+	Parallel   bool     `json:"parallel"`
+	Services   []string `json:"services,omitempty"`
+	Ports      []string `json:"ports,omitempty"`
+	Volumes    []string `json:"volumes,omitempty"`
+	Warnings   []string `json:"warnings,omitempty"`
+}
+
+type bundleSystem struct {
+	PinVersion string      `json:"pinVersion"`
+	OS         string      `json:"os"`
+	Arch       string      `json:"arch"`
+	GoVersion  string      `json:"goVersion"`
+	Docker     interface{} `json:"docker,omitempty"`
+	DockerErr  string      `json:"dockerError,omitempty"`
+}
+
+// SupportBundle writes a tar.gz with the redacted pipeline config, its plan,
+// the latest runs and information about pin and docker for bug reports.
+func SupportBundle(pipelinePath, output string) error {
+	if output == "" {
+		output = "pin-support-" + time.Now().Format("20060102-150405") + ".tar.gz"
+	}
+
+	files := map[string][]byte{}
+
+	if pipelinePath != "" {
+		if err := addPipelineToBundle(files, pipelinePath); err != nil {
+			fmt.Println(err)
+			return err
+		}
+	}
+
+	addRunsToBundle(files)
+
+	system, _ := json.MarshalIndent(collectSystemInfo(), "", "  ")
+	files["system.json"] = system
+
+	if err := writeBundle(output, files); err != nil {
+		fmt.Println(err)
+		return err
+	}
+
+	fmt.Printf("Support bundle written to %s, check it before sharing\n", output)
+
+	return nil
+}
+
+func addPipelineToBundle(files map[string][]byte, pipelinePath string) error {
+	content, err := os.ReadFile(pipelinePath)
+
+	if err != nil {
+		return err
+	}
+
+	redacted, err := redactConfig(content)
+
+	if err != nil {
+		return err
+	}
+
+	files["pipeline.yaml"] = redacted
+
+	config, err := readConfig(pipelinePath)
+
+	if err != nil {
+		return err
+	}
+
+	pipeline, warnings, err := parse(config)
+
+	if err != nil {
+		files["plan-error.txt"] = []byte(redactText(err.Error()) + "\n")
+		return nil
+	}
+
+	plan := []bundlePlanJob{}
+
+	for _, job := range pipeline.Workflow {
+		planJob := bundlePlanJob{
+			Name:       job.Name,
+			Image:      job.Image,
+			Dockerfile: job.Dockerfile,
+			Parallel:   job.IsParallel,
+			Ports:      portStrings(job.Port),
+			Volumes:    job.Volumes,
+		}
+
+		for _, service := range job.Services {
+			planJob.Services = append(planJob.Services, service.Image)
+		}
+
+		for _, warning := range warnings {
+			if warning.Job == job.Name {
+				planJob.Warnings = append(planJob.Warnings, warning.Message)
+			}
+		}
+
+		plan = append(plan, planJob)
+	}
+
+	b, err := json.MarshalIndent(plan, "", "  ")
+
+	if err != nil {
+		return err
+	}
+
+	files["plan.json"] = b
+
+	return nil
+}
+
+// addRunsToBundle adds the metadata and the redacted config of the latest
+// runs, run metadata already masks secret looking variables.
+func addRunsToBundle(files map[string][]byte) {
+	dir, err := runsDir()
+
+	if err != nil {
+		return
+	}
+
+	entries, err := os.ReadDir(dir)
+
+	if err != nil {
+		return
+	}
+
+	ids := []string{}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			ids = append(ids, entry.Name())
+		}
+	}
+
+	sort.Sort(sort.Reverse(sort.StringSlice(ids)))
+
+	if len(ids) > recentRunsInBundle {
+		ids = ids[:recentRunsInBundle]
+	}
+
+	for _, id := range ids {
+		if b, err := os.ReadFile(filepath.Join(dir, id, "run.json")); err == nil {
+			files["runs/"+id+"/run.json"] = []byte(redactText(string(b)))
+		}
+
+		if b, err := os.ReadFile(filepath.Join(dir, id, "pipeline.yaml")); err == nil {
+			if redacted, err := redactConfig(b); err == nil {
+				files["runs/"+id+"/pipeline.yaml"] = redacted
+			}
+		}
+	}
+}
+
+func collectSystemInfo() bundleSystem {
+	system := bundleSystem{
+		PinVersion: Version,
+		OS:         runtime.GOOS,
+		Arch:       runtime.GOARCH,
+		GoVersion:  runtime.Version(),
+	}
+
+	cli, err := client.NewClientWithOpts()
+
+	if err == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		var info interface{}
+		info, err = cli.Info(ctx)
+
+		if err == nil {
+			system.Docker = info
+		}
+	}
+
+	if err != nil {
+		system.DockerErr = err.Error()
+	}
+
+	return system
+}
+
+// redactConfig replaces the values of secret looking keys, KEY=VALUE
+// entries and credentials in urls, webhooks are removed completely because
+// their url is the secret.
+func redactConfig(content []byte) ([]byte, error) {
+	var root yaml.Node
+
+	if err := yaml.Unmarshal(content, &root); err != nil {
+		return nil, err
+	}
+
+	redactNode(&root)
+
+	b, err := yaml.Marshal(&root)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+func redactNode(node *yaml.Node) {
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+
+			if value.Kind == yaml.ScalarNode && (sensitiveEnvPattern.MatchString(key.Value) || strings.EqualFold(key.Value, "webhook")) {
+				value.Value = "***"
+				value.Style = 0
+				continue
+			}
+
+			redactNode(value)
+		}
+
+		return
+	}
+
+	if node.Kind == yaml.ScalarNode {
+		node.Value = redactText(node.Value)
+		return
+	}
+
+	for _, child := range node.Content {
+		redactNode(child)
+	}
+}
+
+// redactText masks KEY=VALUE pairs with secret looking names and the user
+// info of urls in free text.
+func redactText(text string) string {
+	text = credentialsInURL.ReplaceAllString(text, "://***@")
+
+	lines := strings.Split(text, "\n")
+
+	for i, line := range lines {
+		fields := strings.Fields(line)
+
+		for _, field := range fields {
+			name, _, found := strings.Cut(strings.Trim(field, `"',`), "=")
+
+			if found && sensitiveEnvPattern.MatchString(name) {
+				line = strings.Replace(line, strings.Trim(field, `"',`), name+"=***", 1)
+			}
+		}
+
+		lines[i] = line
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func writeBundle(output string, files map[string][]byte) error {
+	f, err := os.Create(output)
+
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	gw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gw)
+
+	names := []string{}
+
+	for name := range files {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		content := files[name]
+
+		header := &tar.Header{
+			Name:    "pin-support/" + name,
+			Mode:    0644,
+			Size:    int64(len(content)),
+			ModTime: time.Now(),
+		}
+
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+
+		if _, err := tw.Write(content); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+
+	return gw.Close()
+}
@@ -0,0 +1,77 @@
+package runner
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	PhasePull      = "pull"
+	PhaseBuild     = "build"
+	PhaseCopy      = "copy"
+	PhaseExec      = "exec"
+	PhaseArtifacts = "artifacts"
+)
+
+// Timing is how long a phase or a script step of a job took.
+type Timing struct {
+	Name     string        `json:"name" yaml:"name"`
+	Duration time.Duration `json:"duration" yaml:"duration"`
+}
+
+// JobTimings are recorded through a pointer, like the script output, because
+// the script executors get a copy of the job.
+type JobTimings struct {
+	Phases []Timing `json:"phases,omitempty" yaml:"phases,omitempty"`
+	Steps  []Timing `json:"steps,omitempty" yaml:"steps,omitempty"`
+}
+
+func (t *JobTimings) phase(name string, startedAt time.Time) {
+	if t == nil {
+		return
+	}
+
+	t.Phases = append(t.Phases, Timing{Name: name, Duration: time.Since(startedAt)})
+}
+
+func (t *JobTimings) step(command string, startedAt time.Time) {
+	if t == nil {
+		return
+	}
+
+	t.Steps = append(t.Steps, Timing{Name: command, Duration: time.Since(startedAt)})
+}
+
+// recordedTimings returns nil for jobs that ended before anything was timed,
+// so they have no timings in the run history and the report.
+func recordedTimings(job *Job) *JobTimings {
+	if job.Timings == nil || len(job.Timings.Phases)+len(job.Timings.Steps) == 0 {
+		return nil
+	}
+
+	return job.Timings
+}
+
+// printTimings prints the phases and the script steps of every timed job
+// below the run summary.
+func printTimings(w io.Writer, jobs []JobReport) {
+	for _, job := range jobs {
+		if job.Timings == nil {
+			continue
+		}
+
+		phases := []string{}
+
+		for _, phase := range job.Timings.Phases {
+			phases = append(phases, fmt.Sprintf("%s %s", phase.Name, phase.Duration.Round(time.Millisecond)))
+		}
+
+		fmt.Fprintf(w, "Timings of %s: %s\n", job.Name, strings.Join(phases, ", "))
+
+		for _, step := range job.Timings.Steps {
+			fmt.Fprintf(w, "  %s %s\n", step.Duration.Round(time.Millisecond), strings.SplitN(step.Name, "\n", 2)[0])
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package runner
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobTimingsRecordPhasesAndSteps(t *testing.T) {
+	timings := &JobTimings{}
+
+	timings.phase(PhasePull, time.Now().Add(-time.Second))
+	timings.step("go test ./...", time.Now())
+
+	assert.Len(t, timings.Phases, 1)
+	assert.Equal(t, PhasePull, timings.Phases[0].Name)
+	assert.GreaterOrEqual(t, timings.Phases[0].Duration, time.Second)
+	assert.Equal(t, "go test ./...", timings.Steps[0].Name)
+
+	// jobs built outside of jobRunner have no timings to record
+	var none *JobTimings
+
+	none.phase(PhaseExec, time.Now())
+	none.step("ls", time.Now())
+
+	assert.Nil(t, recordedTimings(&Job{}))
+	assert.Nil(t, recordedTimings(&Job{Timings: &JobTimings{}}))
+	assert.Equal(t, timings, recordedTimings(&Job{Timings: timings}))
+}
+
+func TestPrintTimingsListsPhasesAndSteps(t *testing.T) {
+	var b bytes.Buffer
+
+	printTimings(&b, []JobReport{
+		{Name: "build", Timings: &JobTimings{
+			Phases: []Timing{{Name: PhasePull, Duration: 1200 * time.Millisecond}, {Name: PhaseCopy, Duration: 80 * time.Millisecond}, {Name: PhaseExec, Duration: time.Minute}},
+			Steps:  []Timing{{Name: "go mod download", Duration: 12 * time.Second}, {Name: "go test ./...\ngo vet ./...", Duration: 48 * time.Second}},
+		}},
+		{Name: "deploy"},
+	})
+
+	assert.Equal(t, `Timings of build: pull 1.2s, copy 80ms, exec 1m0s
+  12s go mod download
+  48s go test ./...
+`, b.String())
+}
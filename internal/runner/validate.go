@@ -0,0 +1,100 @@
+package runner
+
+import (
+	"errors"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	FindingError   = "error"
+	FindingWarning = "warning"
+)
+
+// Finding is an error or a warning of a validated pipeline document.
+type Finding struct {
+	Severity string `json:"severity"`
+	Job      string `json:"job,omitempty"`
+	Field    string `json:"field,omitempty"`
+	Message  string `json:"message"`
+}
+
+func (f Finding) String() string {
+	if f.Job == "" {
+		return f.Message
+	}
+
+	return f.Job + ": " + f.Message
+}
+
+// ValidateBytes parses a pipeline document and returns its warnings and the
+// error that stops it from running as findings, nothing is executed and no
+// state is shared so it is safe to call concurrently. The document has no
+// file, its relative paths are resolved against the current directory like
+// a pipeline read from stdin. The error is only returned when the document
+// is not valid yaml.
+func ValidateBytes(b []byte) ([]Finding, error) {
+	config, err := decodeConfig(b)
+
+	if err == nil {
+		err = resolveConfigPaths(config, configDir(stdinConfigPath))
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return validateConfig(config), nil
+}
+
+func validateConfig(config map[string]interface{}) []Finding {
+	_, warnings, parseErr := parse(config)
+
+	findings := []Finding{}
+
+	for _, w := range warnings {
+		findings = append(findings, Finding{Severity: FindingWarning, Job: w.Job, Field: w.Field, Message: w.Message})
+	}
+
+	if parseErr != nil {
+		findings = append(findings, Finding{Severity: FindingError, Message: parseErr.Error()})
+	}
+
+	return findings
+}
+
+// decodeConfig decodes a pipeline document into a map with lower-cased keys,
+// so fields are matched case-insensitively.
+func decodeConfig(b []byte) (map[string]interface{}, error) {
+	config := map[string]interface{}{}
+
+	if err := yaml.Unmarshal(b, &config); err != nil {
+		return nil, err
+	}
+
+	if config == nil {
+		return nil, errors.New("pipeline must be a yaml mapping")
+	}
+
+	lowerKeys(config)
+
+	return config, nil
+}
+
+// lowerKeys lower-cases the keys of a map and of the maps nested in it, maps
+// in lists are left to getStringMap. Fields whose keys are names, like env,
+// labels and buildArgs, are read only in the KEY=VALUE list form, which keeps
+// their case.
+func lowerKeys(m map[string]interface{}) {
+	for key, val := range m {
+		if nested, ok := val.(map[string]interface{}); ok {
+			lowerKeys(nested)
+		}
+
+		if lower := strings.ToLower(key); lower != key {
+			delete(m, key)
+			m[lower] = val
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package runner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateBytesReturnsWarningsAndErrors(t *testing.T) {
+	findings, err := ValidateBytes([]byte(`workflow:
+  - Build
+
+colour: red
+
+Build:
+  Image: golang:alpine3.15
+  copyfile: true
+  volumes:
+    - /data:/data:rx
+`))
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, []Finding{
+		{Severity: FindingWarning, Field: "colour", Message: `unknown field "colour"`},
+		{Severity: FindingError, Message: "invalid volume mode: /data:/data:rx"},
+	}, findings)
+}
+
+func TestValidateBytesReturnsErrorForInvalidYaml(t *testing.T) {
+	_, err := ValidateBytes([]byte("workflow: [build"))
+
+	assert.Error(t, err)
+}
+
+func TestValidateBytesReportsWronglyTypedFields(t *testing.T) {
+	for field, expected := range map[string]string{
+		"tags: [1]":                    "invalid tags: [1]",
+		`copyFiles: "yes"`:             "invalid copyFiles: yes",
+		"parallel: 1":                  "invalid parallel: 1",
+		"workDir: [/app]":              "invalid workDir: [/app]",
+		"script: {run: make}":          "invalid script: map[run:make]",
+		"cache: {key: go, paths: [1]}": "invalid cache paths: [1]",
+	} {
+		findings, err := ValidateBytes([]byte("workflow:\n  - build\n\nbuild:\n  image: golang:alpine3.15\n  " + field + "\n"))
+
+		assert.Equal(t, err, nil)
+		assert.Equal(t, []Finding{{Severity: FindingError, Message: expected}}, findings, field)
+	}
+
+	findings, err := ValidateBytes([]byte("workflow:\n  - build\n\nbuild:\n  image: [golang]\n"))
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, []Finding{{Severity: FindingError, Message: "invalid image: [golang]"}}, findings)
+}
+
+func TestValidateBytesReportsWronglyTypedHooksAndPluginJobs(t *testing.T) {
+	findings, err := ValidateBytes([]byte(`workflow:
+  - deploy
+
+hostHooks:
+  prePipeline:
+    script: ./check.sh
+    fatal: "no"
+
+deploy:
+  uses: ./plugins/deploy
+`))
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, []Finding{{Severity: FindingError, Message: "hostHooks: invalid prePipeline fatal: no"}}, findings)
+
+	findings, err = ValidateBytes([]byte(`workflow:
+  - deploy
+
+deploy:
+  uses: ./plugins/deploy
+  tags: [1]
+`))
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, []Finding{{Severity: FindingError, Message: "invalid tags: [1]"}}, findings)
+}
@@ -0,0 +1,76 @@
+package runner
+
+import (
+	"fmt"
+	"strings"
+)
+
+// schemaVersions are the versions of the pipeline format this pin reads, a
+// file without version is version 1.
+var schemaVersions = []int{1, 2}
+
+// latestSchemaVersion is the version new pipelines should declare. Version 2
+// rejects unknown and deprecated fields that version 1 only warns about.
+const latestSchemaVersion = 2
+
+func getSchemaVersion(version interface{}) (int, error) {
+	if version == nil {
+		return 1, nil
+	}
+
+	v, ok := version.(int)
+
+	if !ok {
+		return 0, fmt.Errorf("invalid version: %v", version)
+	}
+
+	for _, supported := range schemaVersions {
+		if v == supported {
+			return v, nil
+		}
+	}
+
+	if v > latestSchemaVersion {
+		return 0, fmt.Errorf("pipeline version %d is not supported by pin %s (supported: %s), upgrade pin to run it", v, Version, supportedSchemaVersions())
+	}
+
+	return 0, fmt.Errorf("invalid version: %d, supported: %s", v, supportedSchemaVersions())
+}
+
+func supportedSchemaVersions() string {
+	versions := []string{}
+
+	for _, v := range schemaVersions {
+		versions = append(versions, fmt.Sprint(v))
+	}
+
+	return strings.Join(versions, ", ")
+}
+
+// strictWarnings returns the first warning version 2 treats as an error: an
+// unknown or a deprecated field.
+func strictWarnings(warnings []Warning, version int) error {
+	if version < 2 {
+		return nil
+	}
+
+	for _, w := range warnings {
+		if w.Deprecated || isUnknownField(w) {
+			return fmt.Errorf("%s, version %d does not allow unknown or deprecated fields", w, version)
+		}
+	}
+
+	return nil
+}
+
+func isUnknownField(w Warning) bool {
+	if w.Field == "" {
+		return false
+	}
+
+	if w.Job == "" {
+		return !knownPipelineFields[w.Field]
+	}
+
+	return !knownJobFields[w.Field]
+}
@@ -0,0 +1,82 @@
+package runner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetSchemaVersion(t *testing.T) {
+	version, err := getSchemaVersion(nil)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, version)
+
+	version, err = getSchemaVersion(2)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, version)
+
+	_, err = getSchemaVersion(3)
+
+	assert.EqualError(t, err, "pipeline version 3 is not supported by pin "+Version+" (supported: 1, 2), upgrade pin to run it")
+
+	_, err = getSchemaVersion(0)
+
+	assert.EqualError(t, err, "invalid version: 0, supported: 1, 2")
+
+	_, err = getSchemaVersion("two")
+
+	assert.EqualError(t, err, "invalid version: two")
+}
+
+func TestVersion2RejectsUnknownFields(t *testing.T) {
+	pipeline := `
+workflow:
+  - build
+
+build:
+  image: golang:alpine3.15
+  copyfile: true
+  script:
+    - go build ./...
+`
+
+	config, _ := decodeConfig([]byte(pipeline))
+
+	parsed, warnings, err := parse(config)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, parsed.Version)
+	assert.Equal(t, []Warning{{Job: "build", Field: "copyfile", Message: `unknown field "copyfile"`}}, warnings)
+
+	config, _ = decodeConfig([]byte("version: 2\n" + pipeline))
+
+	_, _, err = parse(config)
+
+	assert.EqualError(t, err, `build: unknown field "copyfile", version 2 does not allow unknown or deprecated fields`)
+
+	config, _ = decodeConfig([]byte("version: 2\ncolour: red\n" + pipeline))
+
+	_, _, err = parse(config)
+
+	assert.EqualError(t, err, `unknown field "colour", version 2 does not allow unknown or deprecated fields`)
+}
+
+func TestVersion2AllowsOtherWarnings(t *testing.T) {
+	config, _ := decodeConfig([]byte(`
+version: 2
+
+workflow:
+  - build
+
+build:
+  image: golang:alpine3.15
+`))
+
+	parsed, warnings, err := parse(config)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, parsed.Version)
+	assert.Equal(t, []Warning{{Job: "build", Field: "script", Message: "script is empty, the job only starts a container"}}, warnings)
+}
@@ -0,0 +1,245 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/muhammedikinci/pin/internal/glob"
+)
+
+// Warning is a non-fatal finding of the parser, the pipeline can still run.
+type Warning struct {
+	Job         string `json:"job,omitempty"`
+	Field       string `json:"field,omitempty"`
+	Message     string `json:"message"`
+	Deprecated  bool   `json:"deprecated,omitempty"`
+	Replacement string `json:"replacement,omitempty"`
+	RemovedIn   string `json:"removedIn,omitempty"`
+}
+
+func (w Warning) String() string {
+	if w.Job == "" {
+		return w.Message
+	}
+
+	return fmt.Sprintf("%s: %s", w.Job, w.Message)
+}
+
+// decodeConfig lower-cases every key, so the known fields are kept lower-cased too.
+var knownPipelineFields = map[string]bool{
+	"version":         true,
+	"workflow":        true,
+	"logswithtime":    true,
+	"onsuccess":       true,
+	"onfailure":       true,
+	"hosthooks":       true,
+	"successcriteria": true,
+	"docker":          true,
+	"plugins":         true,
+	"gitnotes":        true,
+	"maxparallel":     true,
+	"failfast":        true,
+	"env":             true,
+	"envfile":         true,
+}
+
+var knownJobFields = map[string]bool{
+	"image":           true,
+	"dockerfile":      true,
+	"buildargs":       true,
+	"target":          true,
+	"imagetag":        true,
+	"imagefallbacks":  true,
+	"expects":         true,
+	"healthcheck":     true,
+	"env":             true,
+	"stdin":           true,
+	"envfile":         true,
+	"envpassthrough":  true,
+	"description":     true,
+	"tags":            true,
+	"workdir":         true,
+	"copyfiles":       true,
+	"soloexecution":   true,
+	"sessionmode":     true,
+	"parallel":        true,
+	"minsuccess":      true,
+	"copyignore":      true,
+	"copyinclude":     true,
+	"mountfiles":      true,
+	"compression":     true,
+	"copystrategy":    true,
+	"script":          true,
+	"port":            true,
+	"services":        true,
+	"stopgraceperiod": true,
+	"docker":          true,
+	"volumes":         true,
+	"privileged":      true,
+	"capadd":          true,
+	"capdrop":         true,
+	"extrahosts":      true,
+	"dns":             true,
+	"dnssearch":       true,
+	"hostname":        true,
+	"labels":          true,
+	"onsuccess":       true,
+	"onfailure":       true,
+	"cache":           true,
+	"artifacts":       true,
+	"reports":         true,
+	"skipifunchanged": true,
+	"resultcache":     true,
+	"detach":          true,
+	"uses":            true,
+	"with":            true,
+}
+
+func pipelineWarnings(config map[string]interface{}, flows []string) []Warning {
+	warnings := []Warning{}
+
+	inWorkflow := map[string]bool{}
+
+	for _, flow := range flows {
+		inWorkflow[strings.ToLower(flow)] = true
+	}
+
+	keys := []string{}
+
+	for key := range config {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if knownPipelineFields[key] || inWorkflow[key] {
+			continue
+		}
+
+		if d, ok := deprecation(key, true); ok {
+			warnings = append(warnings, d.warning(""))
+			continue
+		}
+
+		if _, ok := config[key].(map[string]interface{}); ok {
+			warnings = append(warnings, Warning{Job: key, Message: "job is defined but not in workflow"})
+			continue
+		}
+
+		warnings = append(warnings, Warning{Field: key, Message: fmt.Sprintf("unknown field %q", key)})
+	}
+
+	return warnings
+}
+
+func jobWarnings(name string, configMap map[string]interface{}, job *Job) []Warning {
+	warnings := []Warning{}
+
+	keys := []string{}
+
+	for key := range configMap {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if d, ok := deprecation(key, false); ok {
+			warnings = append(warnings, d.warning(name))
+			continue
+		}
+
+		if !knownJobFields[key] {
+			warnings = append(warnings, Warning{Job: name, Field: key, Message: fmt.Sprintf("unknown field %q", key)})
+		}
+	}
+
+	if job.MinSuccess != 0 && !job.IsParallel {
+		warnings = append(warnings, Warning{Job: name, Field: "minsuccess", Message: "minSuccess has no effect without parallel"})
+	}
+
+	if job.Uses != "" {
+		return append(warnings, pluginJobWarnings(name, configMap)...)
+	}
+
+	if len(job.Script) == 0 && !job.Detach {
+		warnings = append(warnings, Warning{Job: name, Field: "script", Message: "script is empty, the job only starts a container"})
+	}
+
+	if job.Stdin != nil && job.SessionMode == SessionModeShared {
+		warnings = append(warnings, Warning{Job: name, Field: "stdin", Message: "stdin is not passed to commands in shared session mode"})
+	}
+
+	for _, pattern := range job.EnvPassthrough {
+		if glob.HasMeta(pattern) {
+			warnings = append(warnings, Warning{Job: name, Field: "envpassthrough", Message: fmt.Sprintf("envPassthrough pattern %s passes every matching host variable to the container, secrets included", pattern)})
+		}
+	}
+
+	if job.Dockerfile != "" {
+		if _, err := os.Stat(job.Dockerfile); err != nil {
+			warnings = append(warnings, Warning{Job: name, Field: "dockerfile", Message: fmt.Sprintf("dockerfile %s does not exist", job.Dockerfile)})
+		}
+	}
+
+	for _, volume := range job.Volumes {
+		source, _, _ := strings.Cut(volume, ":")
+
+		if !filepath.IsAbs(source) {
+			continue
+		}
+
+		if _, err := os.Stat(source); err != nil {
+			warnings = append(warnings, Warning{Job: name, Field: "volumes", Message: fmt.Sprintf("volume source %s does not exist, docker creates it as an empty directory", source)})
+		}
+	}
+
+	if len(job.BuildArgs) > 0 && job.Dockerfile == "" {
+		warnings = append(warnings, Warning{Job: name, Field: "buildargs", Message: "buildArgs has no effect without dockerfile"})
+	}
+
+	if job.Target != "" && job.Dockerfile == "" {
+		warnings = append(warnings, Warning{Job: name, Field: "target", Message: "target has no effect without dockerfile"})
+	}
+
+	if job.ImageTag != "" && job.Dockerfile == "" {
+		warnings = append(warnings, Warning{Job: name, Field: "imagetag", Message: "imageTag has no effect without dockerfile"})
+	}
+
+	if len(job.CopyIgnore) > 0 && !job.CopyFiles {
+		warnings = append(warnings, Warning{Job: name, Field: "copyignore", Message: "copyIgnore has no effect without copyFiles"})
+	}
+
+	if len(job.CopyInclude) > 0 && !job.CopyFiles {
+		warnings = append(warnings, Warning{Job: name, Field: "copyinclude", Message: "copyInclude has no effect without copyFiles"})
+	}
+
+	if job.Privileged && (len(job.CapAdd) > 0 || len(job.CapDrop) > 0) {
+		warnings = append(warnings, Warning{Job: name, Field: "privileged", Message: "capAdd and capDrop have no effect in privileged mode"})
+	}
+
+	hostPorts := map[string]bool{}
+
+	for _, port := range job.Port {
+		if hostPorts[port.Out] {
+			warnings = append(warnings, Warning{Job: name, Field: "port", Message: fmt.Sprintf("host port %s is mapped more than once", port.Out)})
+		}
+
+		hostPorts[port.Out] = true
+	}
+
+	return warnings
+}
+
+func printWarnings(warnings []Warning) {
+	for _, warning := range warnings {
+		color.Set(color.FgYellow)
+		fmt.Printf("warning: %s\n", warning)
+		color.Unset()
+	}
+}
@@ -0,0 +1,225 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/muhammedikinci/pin/internal/ignore"
+)
+
+const (
+	watchInterval = 500 * time.Millisecond
+	// watchDebounce is how long the files have to stay unchanged before the
+	// pipeline runs again, so saving several files runs it once.
+	watchDebounce = 300 * time.Millisecond
+)
+
+type fileState struct {
+	size    int64
+	modTime time.Time
+}
+
+// watchSet is what watch compares between runs: the files below dir and the
+// pipeline files, which may be outside of it. A file below dir is skipped
+// when every job that copies the project ignores it with copyIgnore.
+type watchSet struct {
+	dir         string
+	files       []string
+	copyIgnores [][]string
+}
+
+func newWatchSet(filepaths []string, pipeline Pipeline) watchSet {
+	set := watchSet{dir: ".", files: filepaths}
+
+	for _, job := range pipeline.Workflow {
+		if job.MountFiles {
+			// a mount sees every file
+			set.copyIgnores = append(set.copyIgnores, nil)
+		} else if job.CopyFiles {
+			set.copyIgnores = append(set.copyIgnores, job.CopyIgnore)
+		}
+	}
+
+	return set
+}
+
+func (set watchSet) ignored(name string) bool {
+	for _, patterns := range set.copyIgnores {
+		if !ignore.Ignored(name, patterns) {
+			return false
+		}
+	}
+
+	return len(set.copyIgnores) > 0
+}
+
+// watch runs the pipeline and runs it again whenever a project file or one
+// of the pipeline files changes, until it is interrupted. Jobs clean up their
+// containers when they end, jobs with skipIfUnchanged or resultCache only
+// run again when their inputs changed.
+func watch(name string, filepaths []string, options ApplyOptions) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	for {
+		set := newWatchSet(filepaths, applyOnce(name, filepaths, options))
+
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		// taken after the run so the artifacts it wrote do not start another
+		snapshot, err := set.snapshot()
+
+		if err != nil {
+			fmt.Println(err)
+			return err
+		}
+
+		color.Set(color.FgBlue)
+		fmt.Println("Watching for changes...")
+		color.Unset()
+
+		if !waitForChange(ctx, set, snapshot, watchInterval, watchDebounce) {
+			return nil
+		}
+
+		color.Set(color.FgBlue)
+		fmt.Println("Change detected, running the pipeline again")
+		color.Unset()
+	}
+}
+
+// applyOnce reads the pipeline files again and runs them and returns the
+// pipeline that ran. Errors are printed and the change after them is waited
+// for.
+func applyOnce(name string, filepaths []string, options ApplyOptions) Pipeline {
+	config, content, err := loadApplyConfig(filepaths, options)
+
+	if err != nil {
+		fmt.Println(err)
+		return Pipeline{}
+	}
+
+	pipeline, warnings, err := parse(config)
+
+	printWarnings(warnings)
+
+	if err != nil {
+		fmt.Println(err)
+		return Pipeline{}
+	}
+
+	if len(options.Jobs) > 0 {
+		if pipeline, err = onlyJobs(pipeline, options.Jobs); err != nil {
+			fmt.Println(err)
+			return Pipeline{}
+		}
+	}
+
+	executePipeline(name, filepaths[0], content, pipeline, "")
+
+	return pipeline
+}
+
+// waitForChange polls the files of set until they differ from the snapshot
+// and then stay the same for debounce, it returns false when ctx ends first.
+func waitForChange(ctx context.Context, set watchSet, snapshot map[string]fileState, interval, debounce time.Duration) bool {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var changedAt time.Time
+
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ticker.C:
+		}
+
+		current, err := set.snapshot()
+
+		if err != nil {
+			continue
+		}
+
+		if !sameSnapshot(snapshot, current) {
+			snapshot = current
+			changedAt = time.Now()
+
+			continue
+		}
+
+		if !changedAt.IsZero() && time.Since(changedAt) >= debounce {
+			return true
+		}
+	}
+}
+
+// snapshot records the size and modification time of the pipeline files and
+// of every file below dir, hidden directories like .git are skipped.
+func (set watchSet) snapshot() (map[string]fileState, error) {
+	snapshot := map[string]fileState{}
+
+	for _, path := range set.files {
+		// a missing file is a change once it is created again
+		if info, err := os.Stat(path); err == nil {
+			snapshot[filepath.Clean(path)] = fileState{size: info.Size(), modTime: info.ModTime()}
+		}
+	}
+
+	err := filepath.Walk(set.dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+
+			return err
+		}
+
+		rel, err := filepath.Rel(set.dir, path)
+
+		if err != nil {
+			return err
+		}
+
+		name := filepath.ToSlash(rel)
+
+		if info.IsDir() {
+			if path != set.dir && (strings.HasPrefix(info.Name(), ".") || set.ignored(name)) {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if info.Mode().IsRegular() && !set.ignored(name) {
+			snapshot[path] = fileState{size: info.Size(), modTime: info.ModTime()}
+		}
+
+		return nil
+	})
+
+	return snapshot, err
+}
+
+func sameSnapshot(a, b map[string]fileState) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for path, state := range a {
+		if other, ok := b[path]; !ok || other != state {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,87 @@
+package runner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatchSnapshotSkipsHiddenDirectories(t *testing.T) {
+	dir := t.TempDir()
+
+	os.MkdirAll(filepath.Join(dir, ".git"), 0755)
+	os.WriteFile(filepath.Join(dir, ".git", "HEAD"), []byte("ref"), 0644)
+	os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main"), 0644)
+
+	snapshot, err := watchSet{dir: dir}.snapshot()
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(snapshot))
+	assert.Contains(t, snapshot, filepath.Join(dir, "main.go"))
+}
+
+func TestWatchSnapshotHasThePipelineFilesAndSkipsIgnoredPaths(t *testing.T) {
+	dir := t.TempDir()
+	config := filepath.Join(t.TempDir(), "pipeline.yaml")
+
+	os.WriteFile(config, []byte("workflow: []"), 0644)
+	os.MkdirAll(filepath.Join(dir, "node_modules", "left-pad"), 0755)
+	os.WriteFile(filepath.Join(dir, "node_modules", "left-pad", "index.js"), []byte("module.exports"), 0644)
+	os.WriteFile(filepath.Join(dir, "main.js"), []byte("require('left-pad')"), 0644)
+	os.WriteFile(filepath.Join(dir, "debug.log"), []byte("started"), 0644)
+
+	set := newWatchSet([]string{config}, Pipeline{Workflow: []*Job{
+		{Name: "test", CopyFiles: true, CopyIgnore: []string{"^node_modules", "\\.log$"}},
+		{Name: "lint", CopyFiles: true, CopyIgnore: []string{"^node_modules"}},
+		{Name: "notify"},
+	}})
+	set.dir = dir
+
+	snapshot, err := set.snapshot()
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 3, len(snapshot))
+	assert.Contains(t, snapshot, config)
+	assert.Contains(t, snapshot, filepath.Join(dir, "main.js"))
+	// lint copies the log file
+	assert.Contains(t, snapshot, filepath.Join(dir, "debug.log"))
+
+	// a job mounting the project sees the ignored files too
+	set.copyIgnores = append(set.copyIgnores, nil)
+
+	snapshot, _ = set.snapshot()
+
+	assert.Contains(t, snapshot, filepath.Join(dir, "node_modules", "left-pad", "index.js"))
+}
+
+func TestWaitForChangeReturnsAfterChange(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main"), 0644)
+
+	snapshot, _ := watchSet{dir: dir}.snapshot()
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		os.WriteFile(filepath.Join(dir, "new.go"), []byte("package main"), 0644)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	assert.Equal(t, true, waitForChange(ctx, watchSet{dir: dir}, snapshot, 10*time.Millisecond, 30*time.Millisecond))
+}
+
+func TestWaitForChangeStopsWithContext(t *testing.T) {
+	dir := t.TempDir()
+
+	snapshot, _ := watchSet{dir: dir}.snapshot()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	assert.Equal(t, false, waitForChange(ctx, watchSet{dir: dir}, snapshot, 10*time.Millisecond, 30*time.Millisecond))
+}
@@ -0,0 +1,427 @@
+package runner
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/fatih/color"
+	"github.com/muhammedikinci/pin/internal/ignore"
+	"github.com/muhammedikinci/pin/internal/interfaces"
+)
+
+const (
+	CopyStrategyFull  = "full"
+	CopyStrategyDelta = "delta"
+)
+
+// workspaceMount is where the persistent workspace volume is mounted in the
+// job container when copyStrategy is delta.
+const workspaceMount = "/pin-workspace"
+
+const workspaceMarker = ".pin-workspace-id"
+
+// workspaceVolumePrefix starts the names of the workspace volumes, docker
+// creates them without labels.
+const workspaceVolumePrefix = "pin_workspace_"
+
+// workspaceManifest is what the workspace volume contains according to the
+// last successful sync, the id is also written into the volume so a removed
+// or foreign volume is detected.
+type workspaceManifest struct {
+	ID    string            `json:"id"`
+	Files map[string]string `json:"files"`
+}
+
+func getCopyStrategy(copyStrategy interface{}) (string, error) {
+	if copyStrategy == nil {
+		return CopyStrategyFull, nil
+	}
+
+	strategy, _ := copyStrategy.(string)
+
+	if strategy != CopyStrategyFull && strategy != CopyStrategyDelta {
+		return "", fmt.Errorf("invalid copyStrategy: %v", copyStrategy)
+	}
+
+	return strategy, nil
+}
+
+func workspaceVolume(jobName string) (string, error) {
+	project, err := projectID()
+
+	if err != nil {
+		return "", err
+	}
+
+	return workspaceVolumePrefix + project + "_" + unsafeKeyChars.ReplaceAllString(jobName, "_"), nil
+}
+
+func workspaceManifestFile(jobName string) (string, error) {
+	base, err := stateDir()
+
+	if err != nil {
+		return "", err
+	}
+
+	project, err := projectID()
+
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(base, "workspaces", project, unsafeKeyChars.ReplaceAllString(jobName, "_")+".json"), nil
+}
+
+func loadWorkspaceManifest(jobName string) workspaceManifest {
+	manifest := workspaceManifest{Files: map[string]string{}}
+
+	file, err := workspaceManifestFile(jobName)
+
+	if err != nil {
+		return manifest
+	}
+
+	b, err := os.ReadFile(file)
+
+	if err != nil || json.Unmarshal(b, &manifest) != nil || manifest.Files == nil {
+		return workspaceManifest{Files: map[string]string{}}
+	}
+
+	return manifest
+}
+
+func saveWorkspaceManifest(jobName string, manifest workspaceManifest) error {
+	file, err := workspaceManifestFile(jobName)
+
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(manifest)
+
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(file, b, 0644)
+}
+
+// workspaceFiles hashes the project files with the same ignore and include
+// rules as copyFiles, names are slash separated and relative to root.
+func workspaceFiles(root string, copyIgnore, copyInclude []string) (map[string]string, error) {
+	files := map[string]string{}
+
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, path)
+
+		if err != nil {
+			return err
+		}
+
+		name := filepath.ToSlash(rel)
+
+		if info.IsDir() {
+			if rel != "." && (ignore.Ignored(name, copyIgnore) || !ignore.Included(name, true, copyInclude)) {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if !info.Mode().IsRegular() || ignore.Ignored(name, copyIgnore) || !ignore.Included(name, false, copyInclude) {
+			return nil
+		}
+
+		f, err := os.Open(path)
+
+		if err != nil {
+			return err
+		}
+
+		defer f.Close()
+
+		h := sha256.New()
+
+		if _, err := io.Copy(h, f); err != nil {
+			return err
+		}
+
+		files[name] = hex.EncodeToString(h.Sum(nil))
+
+		return nil
+	})
+
+	return files, err
+}
+
+// workspaceDelta returns the files that are new or changed and the files
+// that were removed since the previous manifest, both sorted.
+func workspaceDelta(previous, current map[string]string) ([]string, []string) {
+	changed := []string{}
+	removed := []string{}
+
+	for name, sum := range current {
+		if previous[name] != sum {
+			changed = append(changed, name)
+		}
+	}
+
+	for name := range previous {
+		if _, ok := current[name]; !ok {
+			removed = append(removed, name)
+		}
+	}
+
+	sort.Strings(changed)
+	sort.Strings(removed)
+
+	return changed, removed
+}
+
+// syncWorkspace uploads only the files that changed since the previous run
+// into the workspace volume and copies the volume into the work directory.
+func (r Runner) syncWorkspace(currentJob *Job) error {
+	currentPath, err := projectDir()
+
+	if err != nil {
+		return err
+	}
+
+	current, err := workspaceFiles(currentPath, currentJob.CopyIgnore, currentJob.CopyInclude)
+
+	if err != nil {
+		return err
+	}
+
+	manifest := loadWorkspaceManifest(currentJob.Name)
+
+	if volumeID := r.workspaceID(currentJob); manifest.ID == "" || volumeID != manifest.ID {
+		if volumeID != "" {
+			currentJob.InfoLog.Println("Workspace volume does not match the last sync, sending all files")
+
+			if err := r.execScript("find "+workspaceMount+" -mindepth 1 -delete", currentJob); err != nil {
+				return err
+			}
+		}
+
+		manifest = workspaceManifest{ID: newRunID(), Files: map[string]string{}}
+	}
+
+	changed, removed := workspaceDelta(manifest.Files, current)
+
+	// the archive is streamed to docker like the full copy, it is not held
+	// in memory
+	pr, pw := io.Pipe()
+	done := make(chan error, 1)
+
+	go func() {
+		err := writeWorkspaceArchive(pw, currentPath, changed, manifest.ID, currentJob.Compression)
+
+		pw.CloseWithError(err)
+		done <- err
+	}()
+
+	err = r.cli.CopyToContainer(r.ctx, currentJob.Container.ID, workspaceMount, pr, types.CopyToContainerOptions{})
+
+	// unblocks the archive when docker stopped reading early
+	pr.CloseWithError(io.ErrClosedPipe)
+
+	if archiveErr := <-done; archiveErr != nil && !errors.Is(archiveErr, io.ErrClosedPipe) {
+		return archiveErr
+	}
+
+	if err != nil {
+		return err
+	}
+
+	script := ""
+
+	for _, name := range removed {
+		script += "rm -f " + shellQuote(workspaceMount+"/"+name) + "\n"
+	}
+
+	script += "mkdir -p " + shellQuote(currentJob.WorkDir) + " && cp -a " + workspaceMount + "/. " + shellQuote(currentJob.WorkDir) +
+		" && rm -f " + shellQuote(currentJob.WorkDir+"/"+workspaceMarker)
+
+	if err := r.execScript(script, currentJob); err != nil {
+		return err
+	}
+
+	color.Set(color.FgGreen)
+	currentJob.InfoLog.Printf("Workspace synced: %d changed, %d removed, %d unchanged files", len(changed), len(removed), len(current)-len(changed))
+	color.Unset()
+
+	manifest.Files = current
+
+	return saveWorkspaceManifest(currentJob.Name, manifest)
+}
+
+// workspaceID reads the marker of the workspace volume, empty when the
+// volume is new.
+func (r Runner) workspaceID(currentJob *Job) string {
+	reader, _, err := r.cli.CopyFromContainer(r.ctx, currentJob.Container.ID, workspaceMount+"/"+workspaceMarker)
+
+	if err != nil {
+		return ""
+	}
+
+	defer reader.Close()
+
+	tr := tar.NewReader(reader)
+
+	if _, err := tr.Next(); err != nil {
+		return ""
+	}
+
+	b, _ := io.ReadAll(tr)
+
+	return strings.TrimSpace(string(b))
+}
+
+// writeWorkspaceArchive writes the tar of the changed files and the marker
+// of the sync to out.
+func writeWorkspaceArchive(out io.Writer, root string, files []string, id, compression string) error {
+	w := out
+	var gw *gzip.Writer
+
+	if compression == interfaces.CompressionGzip {
+		gw = gzip.NewWriter(out)
+		w = gw
+	}
+
+	tw := tar.NewWriter(w)
+
+	for _, name := range files {
+		if err := addFileToArchive(tw, root, name); err != nil {
+			return err
+		}
+	}
+
+	marker := []byte(id + "\n")
+
+	if err := tw.WriteHeader(&tar.Header{Name: workspaceMarker, Mode: 0644, Size: int64(len(marker)), Typeflag: tar.TypeReg}); err != nil {
+		return err
+	}
+
+	if _, err := tw.Write(marker); err != nil {
+		return err
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+
+	if gw != nil {
+		return gw.Close()
+	}
+
+	return nil
+}
+
+func addFileToArchive(tw *tar.Writer, root, name string) error {
+	path := filepath.Join(root, filepath.FromSlash(name))
+
+	info, err := os.Stat(path)
+
+	if err != nil {
+		return err
+	}
+
+	header, err := tar.FileInfoHeader(info, info.Name())
+
+	if err != nil {
+		return err
+	}
+
+	header.Name = name
+
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+
+	f, err := os.Open(path)
+
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	_, err = io.Copy(tw, f)
+
+	return err
+}
+
+// execScript runs a shell script in the job container and fails on a non
+// zero exit code.
+func (r Runner) execScript(script string, currentJob *Job) error {
+	output, exitCode, err := r.execOutput(script, currentJob)
+
+	if err != nil {
+		return err
+	}
+
+	if exitCode != 0 {
+		return fmt.Errorf("workspace sync failed: %s", strings.TrimSpace(output))
+	}
+
+	return nil
+}
+
+// execOutput runs a shell script in the job container and returns its
+// combined output and exit code.
+func (r Runner) execOutput(script string, currentJob *Job) (string, int, error) {
+	exec, err := r.cli.ContainerExecCreate(r.ctx, currentJob.Container.ID, types.ExecConfig{
+		AttachStdout: true,
+		AttachStderr: true,
+		Cmd:          []string{"sh", "-c", script},
+	})
+
+	if err != nil {
+		return "", 0, err
+	}
+
+	res, err := r.cli.ContainerExecAttach(r.ctx, exec.ID, types.ExecStartCheck{})
+
+	if err != nil {
+		return "", 0, err
+	}
+
+	var output bytes.Buffer
+
+	stdcopy.StdCopy(&output, &output, res.Reader)
+	res.Close()
+
+	status, err := r.cli.ContainerExecInspect(r.ctx, exec.ID)
+
+	if err != nil {
+		return "", 0, err
+	}
+
+	return output.String(), status.ExitCode, nil
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
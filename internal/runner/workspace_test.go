@@ -0,0 +1,65 @@
+package runner
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkspaceDeltaFindsChangedAndRemovedFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	os.MkdirAll(filepath.Join(dir, "cmd"), 0755)
+	os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module test"), 0644)
+	os.WriteFile(filepath.Join(dir, "cmd", "main.go"), []byte("package main"), 0644)
+	os.WriteFile(filepath.Join(dir, "debug.log"), []byte("ignored"), 0644)
+
+	previous, err := workspaceFiles(dir, []string{`\.log$`}, nil)
+
+	assert.Equal(t, err, nil)
+	assert.Len(t, previous, 2)
+
+	os.WriteFile(filepath.Join(dir, "cmd", "main.go"), []byte("package main\n\nfunc main() {}"), 0644)
+	os.WriteFile(filepath.Join(dir, "README.md"), []byte("# test"), 0644)
+	os.Remove(filepath.Join(dir, "go.mod"))
+
+	current, err := workspaceFiles(dir, []string{`\.log$`}, nil)
+
+	assert.Equal(t, err, nil)
+
+	changed, removed := workspaceDelta(previous, current)
+
+	assert.Equal(t, []string{"README.md", "cmd/main.go"}, changed)
+	assert.Equal(t, []string{"go.mod"}, removed)
+}
+
+func TestWorkspaceArchiveContainsChangedFilesAndMarker(t *testing.T) {
+	dir := t.TempDir()
+
+	os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main"), 0644)
+
+	var buf bytes.Buffer
+
+	err := writeWorkspaceArchive(&buf, dir, []string{"main.go"}, "sync-1", "none")
+
+	assert.Equal(t, err, nil)
+
+	names := []string{}
+	tr := tar.NewReader(&buf)
+
+	for {
+		header, err := tr.Next()
+
+		if err != nil {
+			break
+		}
+
+		names = append(names, header.Name)
+	}
+
+	assert.Equal(t, []string{"main.go", workspaceMarker}, names)
+}
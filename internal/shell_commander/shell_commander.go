@@ -37,7 +37,7 @@ func (sc ShellCommander) PrepareShellCommands(soloExecution bool, scripts []stri
 }
 
 func (sc ShellCommander) wrapCommand(cmd string) string {
-	return "#!/bin/sh\nexec > /shell_command_output.log 2>&1\n" + cmd
+	return "#!/bin/sh\nexec 2>&1\n" + cmd
 }
 
 func (sc ShellCommander) ShellToTar(cmd string) (*bytes.Buffer, error) {
@@ -0,0 +1,55 @@
+//go:build windows
+
+package terminal
+
+import (
+	"golang.org/x/sys/windows"
+)
+
+// State is the mode of a console before MakeRaw changed it.
+type State struct {
+	mode uint32
+}
+
+// IsTerminal reports whether fd is a console.
+func IsTerminal(fd int) bool {
+	var mode uint32
+
+	return windows.GetConsoleMode(windows.Handle(fd), &mode) == nil
+}
+
+// MakeRaw puts the console in raw mode with virtual terminal input, keys are
+// passed on as they are typed and not echoed, so the shell of a container
+// handles them.
+func MakeRaw(fd int) (*State, error) {
+	var mode uint32
+
+	if err := windows.GetConsoleMode(windows.Handle(fd), &mode); err != nil {
+		return nil, err
+	}
+
+	raw := mode &^ (windows.ENABLE_ECHO_INPUT | windows.ENABLE_PROCESSED_INPUT | windows.ENABLE_LINE_INPUT)
+	raw |= windows.ENABLE_VIRTUAL_TERMINAL_INPUT
+
+	if err := windows.SetConsoleMode(windows.Handle(fd), raw); err != nil {
+		return nil, err
+	}
+
+	return &State{mode: mode}, nil
+}
+
+// Restore sets the console back to the mode MakeRaw saved.
+func Restore(fd int, state *State) error {
+	return windows.SetConsoleMode(windows.Handle(fd), state.mode)
+}
+
+// Size returns the width and height of the console window.
+func Size(fd int) (int, int, error) {
+	var info windows.ConsoleScreenBufferInfo
+
+	if err := windows.GetConsoleScreenBufferInfo(windows.Handle(fd), &info); err != nil {
+		return 0, 0, err
+	}
+
+	return int(info.Window.Right - info.Window.Left + 1), int(info.Window.Bottom - info.Window.Top + 1), nil
+}
@@ -0,0 +1,218 @@
+package timeout_client
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/registry"
+	"github.com/muhammedikinci/pin/internal/interfaces"
+	"github.com/muhammedikinci/pin/internal/pin_error"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// defaultStopTimeout is what the daemon waits for a container to stop when
+// no timeout is given.
+const defaultStopTimeout = 10 * time.Second
+
+// timeoutClient gives every docker call that does not stream a deadline, a
+// call that exceeds it fails with a TIMEOUT PinError. Image pulls and
+// builds, copies and exec attach stream data and are left as is.
+type timeoutClient struct {
+	interfaces.Client
+	timeout time.Duration
+}
+
+func NewTimeoutClient(cli interfaces.Client, timeout time.Duration) timeoutClient {
+	return timeoutClient{
+		Client:  cli,
+		timeout: timeout,
+	}
+}
+
+func (tc timeoutClient) call(ctx context.Context, operation string, timeout time.Duration, fn func(ctx context.Context) error) error {
+	callCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := fn(callCtx)
+
+	if err != nil && ctx.Err() == nil && errors.Is(callCtx.Err(), context.DeadlineExceeded) {
+		return pin_error.Timeout(operation, timeout, err)
+	}
+
+	return err
+}
+
+func (tc timeoutClient) ContainerCreate(ctx context.Context, config *container.Config, hostConfig *container.HostConfig, networkingConfig *network.NetworkingConfig, platform *v1.Platform, containerName string) (container.ContainerCreateCreatedBody, error) {
+	var resp container.ContainerCreateCreatedBody
+
+	err := tc.call(ctx, "ContainerCreate", tc.timeout, func(ctx context.Context) (err error) {
+		resp, err = tc.Client.ContainerCreate(ctx, config, hostConfig, networkingConfig, platform, containerName)
+		return err
+	})
+
+	return resp, err
+}
+
+// ContainerStop waits for the stop timeout on top of the call timeout, the
+// daemon only answers after the container stopped.
+func (tc timeoutClient) ContainerStop(ctx context.Context, containerID string, timeout *time.Duration) error {
+	stopTimeout := defaultStopTimeout
+
+	if timeout != nil {
+		stopTimeout = *timeout
+	}
+
+	return tc.call(ctx, "ContainerStop", tc.timeout+stopTimeout, func(ctx context.Context) error {
+		return tc.Client.ContainerStop(ctx, containerID, timeout)
+	})
+}
+
+func (tc timeoutClient) ContainerRemove(ctx context.Context, containerID string, options types.ContainerRemoveOptions) error {
+	return tc.call(ctx, "ContainerRemove", tc.timeout, func(ctx context.Context) error {
+		return tc.Client.ContainerRemove(ctx, containerID, options)
+	})
+}
+
+func (tc timeoutClient) ContainerStart(ctx context.Context, containerID string, options types.ContainerStartOptions) error {
+	return tc.call(ctx, "ContainerStart", tc.timeout, func(ctx context.Context) error {
+		return tc.Client.ContainerStart(ctx, containerID, options)
+	})
+}
+
+func (tc timeoutClient) ContainerExecCreate(ctx context.Context, container string, config types.ExecConfig) (types.IDResponse, error) {
+	var resp types.IDResponse
+
+	err := tc.call(ctx, "ContainerExecCreate", tc.timeout, func(ctx context.Context) (err error) {
+		resp, err = tc.Client.ContainerExecCreate(ctx, container, config)
+		return err
+	})
+
+	return resp, err
+}
+
+func (tc timeoutClient) ContainerExecInspect(ctx context.Context, execID string) (types.ContainerExecInspect, error) {
+	var resp types.ContainerExecInspect
+
+	err := tc.call(ctx, "ContainerExecInspect", tc.timeout, func(ctx context.Context) (err error) {
+		resp, err = tc.Client.ContainerExecInspect(ctx, execID)
+		return err
+	})
+
+	return resp, err
+}
+
+func (tc timeoutClient) ImageList(ctx context.Context, options types.ImageListOptions) ([]types.ImageSummary, error) {
+	var resp []types.ImageSummary
+
+	err := tc.call(ctx, "ImageList", tc.timeout, func(ctx context.Context) (err error) {
+		resp, err = tc.Client.ImageList(ctx, options)
+		return err
+	})
+
+	return resp, err
+}
+
+func (tc timeoutClient) ContainerKill(ctx context.Context, containerID string, signal string) error {
+	return tc.call(ctx, "ContainerKill", tc.timeout, func(ctx context.Context) error {
+		return tc.Client.ContainerKill(ctx, containerID, signal)
+	})
+}
+
+func (tc timeoutClient) ContainerList(ctx context.Context, options types.ContainerListOptions) ([]types.Container, error) {
+	var resp []types.Container
+
+	err := tc.call(ctx, "ContainerList", tc.timeout, func(ctx context.Context) (err error) {
+		resp, err = tc.Client.ContainerList(ctx, options)
+		return err
+	})
+
+	return resp, err
+}
+
+func (tc timeoutClient) ContainerInspect(ctx context.Context, containerID string) (types.ContainerJSON, error) {
+	var resp types.ContainerJSON
+
+	err := tc.call(ctx, "ContainerInspect", tc.timeout, func(ctx context.Context) (err error) {
+		resp, err = tc.Client.ContainerInspect(ctx, containerID)
+		return err
+	})
+
+	return resp, err
+}
+
+func (tc timeoutClient) NetworkCreate(ctx context.Context, name string, options types.NetworkCreate) (types.NetworkCreateResponse, error) {
+	var resp types.NetworkCreateResponse
+
+	err := tc.call(ctx, "NetworkCreate", tc.timeout, func(ctx context.Context) (err error) {
+		resp, err = tc.Client.NetworkCreate(ctx, name, options)
+		return err
+	})
+
+	return resp, err
+}
+
+func (tc timeoutClient) NetworkRemove(ctx context.Context, networkID string) error {
+	return tc.call(ctx, "NetworkRemove", tc.timeout, func(ctx context.Context) error {
+		return tc.Client.NetworkRemove(ctx, networkID)
+	})
+}
+
+func (tc timeoutClient) NetworkList(ctx context.Context, options types.NetworkListOptions) ([]types.NetworkResource, error) {
+	var resp []types.NetworkResource
+
+	err := tc.call(ctx, "NetworkList", tc.timeout, func(ctx context.Context) (err error) {
+		resp, err = tc.Client.NetworkList(ctx, options)
+		return err
+	})
+
+	return resp, err
+}
+
+func (tc timeoutClient) ImageRemove(ctx context.Context, imageID string, options types.ImageRemoveOptions) ([]types.ImageDeleteResponseItem, error) {
+	var resp []types.ImageDeleteResponseItem
+
+	err := tc.call(ctx, "ImageRemove", tc.timeout, func(ctx context.Context) (err error) {
+		resp, err = tc.Client.ImageRemove(ctx, imageID, options)
+		return err
+	})
+
+	return resp, err
+}
+
+func (tc timeoutClient) ImageInspectWithRaw(ctx context.Context, imageID string) (types.ImageInspect, []byte, error) {
+	var resp types.ImageInspect
+	var raw []byte
+
+	err := tc.call(ctx, "ImageInspect", tc.timeout, func(ctx context.Context) (err error) {
+		resp, raw, err = tc.Client.ImageInspectWithRaw(ctx, imageID)
+		return err
+	})
+
+	return resp, raw, err
+}
+
+func (tc timeoutClient) ServerVersion(ctx context.Context) (types.Version, error) {
+	var resp types.Version
+
+	err := tc.call(ctx, "ServerVersion", tc.timeout, func(ctx context.Context) (err error) {
+		resp, err = tc.Client.ServerVersion(ctx)
+		return err
+	})
+
+	return resp, err
+}
+
+func (tc timeoutClient) DistributionInspect(ctx context.Context, image, encodedRegistryAuth string) (registry.DistributionInspect, error) {
+	var resp registry.DistributionInspect
+
+	err := tc.call(ctx, "DistributionInspect", tc.timeout, func(ctx context.Context) (err error) {
+		resp, err = tc.Client.DistributionInspect(ctx, image, encodedRegistryAuth)
+		return err
+	})
+
+	return resp, err
+}
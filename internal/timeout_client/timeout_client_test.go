@@ -0,0 +1,85 @@
+package timeout_client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/golang/mock/gomock"
+	"github.com/muhammedikinci/pin/internal/mocks"
+	"github.com/muhammedikinci/pin/internal/pin_error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWedgedCallReturnsTimeoutPinError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	mockCli := mocks.NewMockClient(ctrl)
+
+	mockCli.
+		EXPECT().
+		ContainerStart(gomock.Any(), "id", types.ContainerStartOptions{}).
+		DoAndReturn(func(ctx context.Context, containerID string, options types.ContainerStartOptions) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
+	tc := NewTimeoutClient(mockCli, 10*time.Millisecond)
+
+	err := tc.ContainerStart(context.Background(), "id", types.ContainerStartOptions{})
+
+	var pinErr *pin_error.PinError
+
+	assert.True(t, errors.As(err, &pinErr))
+	assert.Equal(t, pin_error.CodeTimeout, pinErr.Code)
+	assert.Equal(t, "ContainerStart", pinErr.Operation)
+	assert.EqualError(t, err, "TIMEOUT: ContainerStart did not finish within 10ms")
+}
+
+func TestContainerStopWaitsForStopTimeout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	mockCli := mocks.NewMockClient(ctrl)
+
+	stopTimeout := 20 * time.Millisecond
+
+	mockCli.
+		EXPECT().
+		ContainerStop(gomock.Any(), "id", &stopTimeout).
+		DoAndReturn(func(ctx context.Context, containerID string, timeout *time.Duration) error {
+			time.Sleep(stopTimeout)
+			return ctx.Err()
+		})
+
+	tc := NewTimeoutClient(mockCli, 10*time.Millisecond)
+
+	assert.Equal(t, nil, tc.ContainerStop(context.Background(), "id", &stopTimeout))
+}
+
+func TestCanceledContextIsNotATimeout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	mockCli := mocks.NewMockClient(ctrl)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	mockCli.
+		EXPECT().
+		ContainerRemove(gomock.Any(), "id", types.ContainerRemoveOptions{}).
+		DoAndReturn(func(ctx context.Context, containerID string, options types.ContainerRemoveOptions) error {
+			return ctx.Err()
+		})
+
+	tc := NewTimeoutClient(mockCli, time.Second)
+
+	assert.Equal(t, context.Canceled, tc.ContainerRemove(ctx, "id", types.ContainerRemoveOptions{}))
+}
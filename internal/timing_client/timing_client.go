@@ -0,0 +1,165 @@
+package timing_client
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/muhammedikinci/pin/internal/interfaces"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// timingClient logs how long the docker calls of a job take, for verbose
+// output. Calls that stream data are timed until the daemon answers, not
+// until the stream ends.
+type timingClient struct {
+	interfaces.Client
+	log interfaces.Log
+}
+
+func NewTimingClient(cli interfaces.Client, log interfaces.Log) timingClient {
+	return timingClient{
+		Client: cli,
+		log:    log,
+	}
+}
+
+func (tc timingClient) timed(operation string, started time.Time, err error) {
+	if err != nil {
+		tc.log.Printf("docker %s failed after %s: %s", operation, time.Since(started).Round(time.Millisecond), err)
+		return
+	}
+
+	tc.log.Printf("docker %s took %s", operation, time.Since(started).Round(time.Millisecond))
+}
+
+func (tc timingClient) ContainerCreate(ctx context.Context, config *container.Config, hostConfig *container.HostConfig, networkingConfig *network.NetworkingConfig, platform *v1.Platform, containerName string) (container.ContainerCreateCreatedBody, error) {
+	started := time.Now()
+	resp, err := tc.Client.ContainerCreate(ctx, config, hostConfig, networkingConfig, platform, containerName)
+	tc.timed("ContainerCreate", started, err)
+
+	return resp, err
+}
+
+func (tc timingClient) ContainerStart(ctx context.Context, containerID string, options types.ContainerStartOptions) error {
+	started := time.Now()
+	err := tc.Client.ContainerStart(ctx, containerID, options)
+	tc.timed("ContainerStart", started, err)
+
+	return err
+}
+
+func (tc timingClient) ContainerStop(ctx context.Context, containerID string, timeout *time.Duration) error {
+	started := time.Now()
+	err := tc.Client.ContainerStop(ctx, containerID, timeout)
+	tc.timed("ContainerStop", started, err)
+
+	return err
+}
+
+func (tc timingClient) ContainerRemove(ctx context.Context, containerID string, options types.ContainerRemoveOptions) error {
+	started := time.Now()
+	err := tc.Client.ContainerRemove(ctx, containerID, options)
+	tc.timed("ContainerRemove", started, err)
+
+	return err
+}
+
+func (tc timingClient) ContainerInspect(ctx context.Context, containerID string) (types.ContainerJSON, error) {
+	started := time.Now()
+	resp, err := tc.Client.ContainerInspect(ctx, containerID)
+	tc.timed("ContainerInspect", started, err)
+
+	return resp, err
+}
+
+func (tc timingClient) ContainerExecCreate(ctx context.Context, container string, config types.ExecConfig) (types.IDResponse, error) {
+	started := time.Now()
+	resp, err := tc.Client.ContainerExecCreate(ctx, container, config)
+	tc.timed("ContainerExecCreate", started, err)
+
+	return resp, err
+}
+
+func (tc timingClient) ContainerExecAttach(ctx context.Context, execID string, config types.ExecStartCheck) (types.HijackedResponse, error) {
+	started := time.Now()
+	resp, err := tc.Client.ContainerExecAttach(ctx, execID, config)
+	tc.timed("ContainerExecAttach", started, err)
+
+	return resp, err
+}
+
+func (tc timingClient) ContainerExecInspect(ctx context.Context, execID string) (types.ContainerExecInspect, error) {
+	started := time.Now()
+	resp, err := tc.Client.ContainerExecInspect(ctx, execID)
+	tc.timed("ContainerExecInspect", started, err)
+
+	return resp, err
+}
+
+func (tc timingClient) CopyToContainer(ctx context.Context, containerID string, dstPath string, content io.Reader, options types.CopyToContainerOptions) error {
+	started := time.Now()
+	err := tc.Client.CopyToContainer(ctx, containerID, dstPath, content, options)
+	tc.timed("CopyToContainer", started, err)
+
+	return err
+}
+
+func (tc timingClient) CopyFromContainer(ctx context.Context, containerID string, srcPath string) (io.ReadCloser, types.ContainerPathStat, error) {
+	started := time.Now()
+	reader, stat, err := tc.Client.CopyFromContainer(ctx, containerID, srcPath)
+	tc.timed("CopyFromContainer", started, err)
+
+	return reader, stat, err
+}
+
+func (tc timingClient) ImagePull(ctx context.Context, refStr string, options types.ImagePullOptions) (io.ReadCloser, error) {
+	started := time.Now()
+	reader, err := tc.Client.ImagePull(ctx, refStr, options)
+	tc.timed("ImagePull", started, err)
+
+	return reader, err
+}
+
+func (tc timingClient) ImageBuild(ctx context.Context, buildContext io.Reader, options types.ImageBuildOptions) (types.ImageBuildResponse, error) {
+	started := time.Now()
+	resp, err := tc.Client.ImageBuild(ctx, buildContext, options)
+	tc.timed("ImageBuild", started, err)
+
+	return resp, err
+}
+
+func (tc timingClient) ImageInspectWithRaw(ctx context.Context, imageID string) (types.ImageInspect, []byte, error) {
+	started := time.Now()
+	inspect, raw, err := tc.Client.ImageInspectWithRaw(ctx, imageID)
+	tc.timed("ImageInspectWithRaw", started, err)
+
+	return inspect, raw, err
+}
+
+func (tc timingClient) ContainerKill(ctx context.Context, containerID string, signal string) error {
+	started := time.Now()
+	err := tc.Client.ContainerKill(ctx, containerID, signal)
+	tc.timed("ContainerKill", started, err)
+
+	return err
+}
+
+func (tc timingClient) NetworkCreate(ctx context.Context, name string, options types.NetworkCreate) (types.NetworkCreateResponse, error) {
+	started := time.Now()
+	resp, err := tc.Client.NetworkCreate(ctx, name, options)
+	tc.timed("NetworkCreate", started, err)
+
+	return resp, err
+}
+
+func (tc timingClient) NetworkRemove(ctx context.Context, networkID string) error {
+	started := time.Now()
+	err := tc.Client.NetworkRemove(ctx, networkID)
+	tc.timed("NetworkRemove", started, err)
+
+	return err
+}
@@ -0,0 +1,83 @@
+package timing_client
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/golang/mock/gomock"
+	"github.com/muhammedikinci/pin/internal/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCallsAreTimed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	mockCli := mocks.NewMockClient(ctrl)
+	mockLog := mocks.NewMockLog(ctrl)
+
+	mockCli.
+		EXPECT().
+		ContainerStart(gomock.Any(), "id", types.ContainerStartOptions{}).
+		Return(nil)
+
+	mockLog.
+		EXPECT().
+		Printf("docker %s took %s", "ContainerStart", gomock.Any()).
+		Times(1)
+
+	tc := NewTimingClient(mockCli, mockLog)
+
+	err := tc.ContainerStart(context.Background(), "id", types.ContainerStartOptions{})
+
+	assert.NoError(t, err)
+}
+
+func TestFailedCallsAreTimedWithTheError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	mockCli := mocks.NewMockClient(ctrl)
+	mockLog := mocks.NewMockLog(ctrl)
+
+	mockCli.
+		EXPECT().
+		NetworkRemove(gomock.Any(), "net").
+		Return(errors.New("not found"))
+
+	mockLog.
+		EXPECT().
+		Printf("docker %s failed after %s: %s", "NetworkRemove", gomock.Any(), gomock.Any()).
+		Times(1)
+
+	tc := NewTimingClient(mockCli, mockLog)
+
+	err := tc.NetworkRemove(context.Background(), "net")
+
+	assert.EqualError(t, err, "not found")
+}
+
+func TestUntimedCallsPassThrough(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	mockCli := mocks.NewMockClient(ctrl)
+	mockLog := mocks.NewMockLog(ctrl)
+
+	mockCli.
+		EXPECT().
+		ServerVersion(gomock.Any()).
+		Return(types.Version{Version: "24.0.0"}, nil)
+
+	tc := NewTimingClient(mockCli, mockLog)
+
+	version, err := tc.ServerVersion(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, "24.0.0", version.Version)
+}